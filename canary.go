@@ -0,0 +1,478 @@
+package frontier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"iter"
+	"strconv"
+	"time"
+
+	"github.com/aereal/frontier/cf"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudfront"
+	"github.com/aws/aws-sdk-go-v2/service/cloudfront/types"
+)
+
+var (
+	ErrCanaryInProgress = errors.New("the canary is already in progress")
+	ErrCanaryNotStarted = errors.New("no canary is in progress")
+)
+
+type FunctionNotAssociatedError struct {
+	FunctionName   string
+	DistributionID string
+}
+
+func (e *FunctionNotAssociatedError) Error() string {
+	return fmt.Sprintf("function %s is not associated with the distribution %s", e.FunctionName, e.DistributionID)
+}
+
+func (e *FunctionNotAssociatedError) Is(other error) bool {
+	otherErr := new(FunctionNotAssociatedError)
+	if !errors.As(other, &otherErr) {
+		return false
+	}
+	return *otherErr == *e
+}
+
+// CanaryTraffic decides the requests that the staging distribution serves:
+// the share of Weight, or the requests having the Header of the HeaderValue if the Header is given.
+type CanaryTraffic struct {
+	Weight      float32
+	Header      string
+	HeaderValue string
+}
+
+func (t *CanaryTraffic) toSDK() *types.TrafficConfig {
+	if t.Header != "" {
+		return &types.TrafficConfig{
+			Type:               types.ContinuousDeploymentPolicyTypeSingleHeader,
+			SingleHeaderConfig: &types.ContinuousDeploymentSingleHeaderConfig{Header: aws.String(t.Header), Value: aws.String(t.HeaderValue)},
+		}
+	}
+	return &types.TrafficConfig{
+		Type:               types.ContinuousDeploymentPolicyTypeSingleWeight,
+		SingleWeightConfig: &types.ContinuousDeploymentSingleWeightConfig{Weight: aws.Float32(t.Weight)},
+	}
+}
+
+type CanaryOption interface {
+	applyCanaryOption(cfg *configCanary)
+}
+
+type configCanary struct {
+	functionName string
+}
+
+// CanaryFunctionName sets the name of the function deployed as the canary; defaults to the function name suffixed with `-canary`.
+func CanaryFunctionName(name string) CanaryOption { return &optCanaryFunctionName{name: name} } //nolint:ireturn
+
+type optCanaryFunctionName struct{ name string }
+
+var _ CanaryOption = (*optCanaryFunctionName)(nil)
+
+func (o *optCanaryFunctionName) applyCanaryOption(cfg *configCanary) { cfg.functionName = o.name }
+
+type CanaryStatus string
+
+const (
+	CanaryStatusStarted  CanaryStatus = "started"
+	CanaryStatusPromoted CanaryStatus = "promoted"
+	CanaryStatusAborted  CanaryStatus = "aborted"
+)
+
+type CanaryResult struct {
+	Status                CanaryStatus
+	FunctionName          string
+	CanaryFunctionName    string
+	DistributionID        string
+	StagingDistributionID string
+	PolicyID              string
+}
+
+// NewCanary returns the Canary that deploys the functions with the deployer.
+func NewCanary(deployer *Deployer) *Canary {
+	return &Canary{deployer: deployer}
+}
+
+// Canary runs the canary release of the function with CloudFront continuous deployment.
+//
+// The canary function, a copy of the function with the new code, is associated with the staging distribution,
+// and the continuous deployment policy of the primary distribution routes the part of the traffic to the staging distribution.
+type Canary struct {
+	deployer *Deployer
+}
+
+// StartCanary deploys the code to the canary function and routes the traffic to the staging distribution that uses the canary function.
+// The staging distribution is copied from the primary distribution at the first time, and reused after that.
+func (c *Canary) StartCanary(ctx context.Context, configPath, distributionID string, traffic *CanaryTraffic, opts ...CanaryOption) (_ *CanaryResult, err error) {
+	ctx, span := startSpan(ctx, "frontier.StartCanary", attrConfigPath.String(configPath))
+	defer func() { endSpan(span, err) }()
+	fn, err := parseConfig(ctx, configPath)
+	if err != nil {
+		return nil, err
+	}
+	cfg := newConfigCanary(fn.Name, opts)
+	release, err := c.deployer.lock(ctx, fn.Name, 0)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	client, err := c.deployer.clientProvider.ProvideCloudFrontClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+	state, err := resolveCanaryState(ctx, client, distributionID)
+	if err != nil {
+		return nil, err
+	}
+	if state.policy != nil && aws.ToBool(state.policy.ContinuousDeploymentPolicyConfig.Enabled) {
+		return nil, ErrCanaryInProgress
+	}
+	mainARN, err := c.associatedFunctionARN(ctx, client, fn.Name, state)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := readCode(ctx, fn)
+	if err != nil {
+		return nil, err
+	}
+	canaryFn := *fn
+	canaryFn.Name = cfg.functionName
+	recordCodeSize(ctx, len(body), attrFunctionName.String(canaryFn.Name), attrOperation.String("canary"))
+	deployed := &DeployResult{FunctionName: canaryFn.Name}
+	if err := c.deployer.upsert(ctx, &canaryFn, body, true, deployed); err != nil {
+		return nil, err
+	}
+
+	if state.staging == nil {
+		out, err := client.CopyDistribution(ctx, &cloudfront.CopyDistributionInput{
+			PrimaryDistributionId: aws.String(distributionID),
+			CallerReference:       aws.String("frontier-canary-" + fn.Name + "-" + strconv.FormatInt(time.Now().UnixNano(), 10)),
+			IfMatch:               state.primaryETag,
+			Staging:               aws.Bool(true),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("CopyDistribution: %w", err)
+		}
+		state.staging = &stagingDistribution{id: aws.ToString(out.Distribution.Id), domainName: aws.ToString(out.Distribution.DomainName)}
+	}
+	if err := switchStagingFunction(ctx, client, state.staging.id, mainARN, deployed.FunctionARN); err != nil {
+		return nil, err
+	}
+
+	policyConfig := &types.ContinuousDeploymentPolicyConfig{
+		Enabled:                     aws.Bool(true),
+		StagingDistributionDnsNames: &types.StagingDistributionDnsNames{Quantity: aws.Int32(1), Items: []string{state.staging.domainName}},
+		TrafficConfig:               traffic.toSDK(),
+	}
+	if state.policy != nil {
+		if _, err := client.UpdateContinuousDeploymentPolicy(ctx, &cloudfront.UpdateContinuousDeploymentPolicyInput{
+			Id:                               state.policy.Id,
+			IfMatch:                          state.policyETag,
+			ContinuousDeploymentPolicyConfig: policyConfig,
+		}); err != nil {
+			return nil, fmt.Errorf("UpdateContinuousDeploymentPolicy: %w", err)
+		}
+	} else {
+		out, err := client.CreateContinuousDeploymentPolicy(ctx, &cloudfront.CreateContinuousDeploymentPolicyInput{ContinuousDeploymentPolicyConfig: policyConfig})
+		if err != nil {
+			return nil, fmt.Errorf("CreateContinuousDeploymentPolicy: %w", err)
+		}
+		state.policy = out.ContinuousDeploymentPolicy
+		state.primary.ContinuousDeploymentPolicyId = out.ContinuousDeploymentPolicy.Id
+		if _, err := client.UpdateDistribution(ctx, &cloudfront.UpdateDistributionInput{
+			Id:                 aws.String(distributionID),
+			IfMatch:            state.primaryETag,
+			DistributionConfig: state.primary,
+		}); err != nil {
+			return nil, fmt.Errorf("UpdateDistribution: %w", err)
+		}
+	}
+	return state.result(CanaryStatusStarted, fn.Name, cfg.functionName, distributionID), nil
+}
+
+// PromoteCanary publishes the code of the canary function to the function, and updates the primary distribution with the staging distribution
+// that is associated with the function again. The continuous deployment policy is disabled after that.
+func (c *Canary) PromoteCanary(ctx context.Context, configPath, distributionID string, opts ...CanaryOption) (_ *CanaryResult, err error) {
+	ctx, span := startSpan(ctx, "frontier.PromoteCanary", attrConfigPath.String(configPath))
+	defer func() { endSpan(span, err) }()
+	fn, err := parseConfig(ctx, configPath)
+	if err != nil {
+		return nil, err
+	}
+	cfg := newConfigCanary(fn.Name, opts)
+	release, err := c.deployer.lock(ctx, fn.Name, 0)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	client, err := c.deployer.clientProvider.ProvideCloudFrontClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+	state, err := resolveRunningCanary(ctx, client, distributionID)
+	if err != nil {
+		return nil, err
+	}
+	mainARN, err := c.associatedFunctionARN(ctx, client, fn.Name, state)
+	if err != nil {
+		return nil, err
+	}
+
+	canary, err := fetchRemoteFunction(ctx, client, cfg.functionName, types.FunctionStageLive)
+	if err != nil {
+		return nil, err
+	}
+	promotedFn := &Function{Name: fn.Name, Config: fn.Config}
+	if canary.Summary != nil {
+		promotedFn.Config = functionConfigFromSDK(canary.Summary.FunctionConfig)
+	}
+	if err := c.deployer.upsert(ctx, promotedFn, canary.Code, true, &DeployResult{FunctionName: fn.Name}); err != nil {
+		return nil, err
+	}
+
+	if err := switchStagingFunction(ctx, client, state.staging.id, functionARNOf(canary.Summary), mainARN); err != nil {
+		return nil, err
+	}
+	stagingETag, err := distributionETag(ctx, client, state.staging.id)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := client.UpdateDistributionWithStagingConfig(ctx, &cloudfront.UpdateDistributionWithStagingConfigInput{
+		Id:                    aws.String(distributionID),
+		StagingDistributionId: aws.String(state.staging.id),
+		IfMatch:               aws.String(aws.ToString(state.primaryETag) + ", " + stagingETag),
+	}); err != nil {
+		return nil, fmt.Errorf("UpdateDistributionWithStagingConfig: %w", err)
+	}
+	if err := disableCanaryPolicy(ctx, client, state); err != nil {
+		return nil, err
+	}
+	return state.result(CanaryStatusPromoted, fn.Name, cfg.functionName, distributionID), nil
+}
+
+// AbortCanary disables the continuous deployment policy so that the primary distribution serves all the traffic.
+func (c *Canary) AbortCanary(ctx context.Context, configPath, distributionID string, opts ...CanaryOption) (_ *CanaryResult, err error) {
+	ctx, span := startSpan(ctx, "frontier.AbortCanary", attrConfigPath.String(configPath))
+	defer func() { endSpan(span, err) }()
+	fn, err := parseConfig(ctx, configPath)
+	if err != nil {
+		return nil, err
+	}
+	cfg := newConfigCanary(fn.Name, opts)
+	release, err := c.deployer.lock(ctx, fn.Name, 0)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	client, err := c.deployer.clientProvider.ProvideCloudFrontClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+	state, err := resolveRunningCanary(ctx, client, distributionID)
+	if err != nil {
+		return nil, err
+	}
+	if err := disableCanaryPolicy(ctx, client, state); err != nil {
+		return nil, err
+	}
+	return state.result(CanaryStatusAborted, fn.Name, cfg.functionName, distributionID), nil
+}
+
+func newConfigCanary(functionName string, opts []CanaryOption) *configCanary {
+	cfg := &configCanary{functionName: functionName + "-canary"}
+	for _, o := range opts {
+		o.applyCanaryOption(cfg)
+	}
+	return cfg
+}
+
+// associatedFunctionARN returns the ARN of the function, and fails if the primary distribution does not use the function.
+func (c *Canary) associatedFunctionARN(ctx context.Context, client cf.CloudFrontClient, name string, state *canaryState) (string, error) {
+	var out *cloudfront.DescribeFunctionOutput
+	err := c.deployer.retrier.do(ctx, func() error {
+		var err error
+		out, err = client.DescribeFunction(ctx, &cloudfront.DescribeFunctionInput{Name: aws.String(name), Stage: types.FunctionStageLive})
+		return err
+	})
+	if err != nil {
+		return "", fmt.Errorf("DescribeFunction: %w", err)
+	}
+	arn := functionARNOf(out.FunctionSummary)
+	if arn == "" || !isFunctionAssociated(state.primary, arn) {
+		return "", &FunctionNotAssociatedError{FunctionName: name, DistributionID: aws.ToString(state.primaryID)}
+	}
+	return arn, nil
+}
+
+type stagingDistribution struct {
+	id         string
+	domainName string
+}
+
+// canaryState is the primary distribution, and the continuous deployment policy and the staging distribution if they exist.
+type canaryState struct {
+	primaryID   *string
+	primary     *types.DistributionConfig
+	primaryETag *string
+	policy      *types.ContinuousDeploymentPolicy
+	policyETag  *string
+	staging     *stagingDistribution
+}
+
+func (s *canaryState) result(status CanaryStatus, functionName, canaryFunctionName, distributionID string) *CanaryResult {
+	result := &CanaryResult{
+		Status:             status,
+		FunctionName:       functionName,
+		CanaryFunctionName: canaryFunctionName,
+		DistributionID:     distributionID,
+	}
+	if s.policy != nil {
+		result.PolicyID = aws.ToString(s.policy.Id)
+	}
+	if s.staging != nil {
+		result.StagingDistributionID = s.staging.id
+	}
+	return result
+}
+
+func resolveCanaryState(ctx context.Context, client cf.CloudFrontClient, distributionID string) (*canaryState, error) {
+	out, err := client.GetDistributionConfig(ctx, &cloudfront.GetDistributionConfigInput{Id: aws.String(distributionID)})
+	if err != nil {
+		return nil, fmt.Errorf("GetDistributionConfig: %w", err)
+	}
+	state := &canaryState{primaryID: aws.String(distributionID), primary: out.DistributionConfig, primaryETag: out.ETag}
+	policyID := aws.ToString(out.DistributionConfig.ContinuousDeploymentPolicyId)
+	if policyID == "" {
+		return state, nil
+	}
+	policyOut, err := client.GetContinuousDeploymentPolicy(ctx, &cloudfront.GetContinuousDeploymentPolicyInput{Id: aws.String(policyID)})
+	if err != nil {
+		return nil, fmt.Errorf("GetContinuousDeploymentPolicy: %w", err)
+	}
+	state.policy = policyOut.ContinuousDeploymentPolicy
+	state.policyETag = policyOut.ETag
+	state.staging, err = findStagingDistribution(ctx, client, state.policy.ContinuousDeploymentPolicyConfig.StagingDistributionDnsNames)
+	if err != nil {
+		return nil, err
+	}
+	return state, nil
+}
+
+func resolveRunningCanary(ctx context.Context, client cf.CloudFrontClient, distributionID string) (*canaryState, error) {
+	state, err := resolveCanaryState(ctx, client, distributionID)
+	if err != nil {
+		return nil, err
+	}
+	if state.policy == nil || state.staging == nil || !aws.ToBool(state.policy.ContinuousDeploymentPolicyConfig.Enabled) {
+		return nil, ErrCanaryNotStarted
+	}
+	return state, nil
+}
+
+func findStagingDistribution(ctx context.Context, client cf.CloudFrontClient, dnsNames *types.StagingDistributionDnsNames) (*stagingDistribution, error) {
+	if dnsNames == nil || len(dnsNames.Items) == 0 {
+		return nil, nil
+	}
+	paginator := cloudfront.NewListDistributionsPaginator(client, &cloudfront.ListDistributionsInput{})
+	for paginator.HasMorePages() {
+		out, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("ListDistributions: %w", err)
+		}
+		for _, dist := range out.DistributionList.Items {
+			if aws.ToBool(dist.Staging) && aws.ToString(dist.DomainName) == dnsNames.Items[0] {
+				return &stagingDistribution{id: aws.ToString(dist.Id), domainName: aws.ToString(dist.DomainName)}, nil
+			}
+		}
+	}
+	return nil, nil
+}
+
+// switchStagingFunction replaces the function associated with the behaviors of the staging distribution.
+func switchStagingFunction(ctx context.Context, client cf.CloudFrontClient, stagingID, fromARN, toARN string) error {
+	out, err := client.GetDistributionConfig(ctx, &cloudfront.GetDistributionConfigInput{Id: aws.String(stagingID)})
+	if err != nil {
+		return fmt.Errorf("GetDistributionConfig: %w", err)
+	}
+	if replaceFunctionAssociations(out.DistributionConfig, fromARN, toARN) == 0 {
+		return nil
+	}
+	if _, err := client.UpdateDistribution(ctx, &cloudfront.UpdateDistributionInput{
+		Id:                 aws.String(stagingID),
+		IfMatch:            out.ETag,
+		DistributionConfig: out.DistributionConfig,
+	}); err != nil {
+		return fmt.Errorf("UpdateDistribution: %w", err)
+	}
+	return nil
+}
+
+func distributionETag(ctx context.Context, client cf.CloudFrontClient, distributionID string) (string, error) {
+	out, err := client.GetDistributionConfig(ctx, &cloudfront.GetDistributionConfigInput{Id: aws.String(distributionID)})
+	if err != nil {
+		return "", fmt.Errorf("GetDistributionConfig: %w", err)
+	}
+	return aws.ToString(out.ETag), nil
+}
+
+func disableCanaryPolicy(ctx context.Context, client cf.CloudFrontClient, state *canaryState) error {
+	policyConfig := *state.policy.ContinuousDeploymentPolicyConfig
+	policyConfig.Enabled = aws.Bool(false)
+	if _, err := client.UpdateContinuousDeploymentPolicy(ctx, &cloudfront.UpdateContinuousDeploymentPolicyInput{
+		Id:                               state.policy.Id,
+		IfMatch:                          state.policyETag,
+		ContinuousDeploymentPolicyConfig: &policyConfig,
+	}); err != nil {
+		return fmt.Errorf("UpdateContinuousDeploymentPolicy: %w", err)
+	}
+	return nil
+}
+
+// replaceFunctionAssociations replaces the function of the associations in the cache behaviors and returns the number of the replaced associations.
+func replaceFunctionAssociations(cfg *types.DistributionConfig, fromARN, toARN string) int {
+	var n int
+	for association := range functionAssociationsOf(cfg) {
+		if aws.ToString(association.FunctionARN) == fromARN {
+			association.FunctionARN = aws.String(toARN)
+			n++
+		}
+	}
+	return n
+}
+
+func isFunctionAssociated(cfg *types.DistributionConfig, arn string) bool {
+	for association := range functionAssociationsOf(cfg) {
+		if aws.ToString(association.FunctionARN) == arn {
+			return true
+		}
+	}
+	return false
+}
+
+func functionAssociationsOf(cfg *types.DistributionConfig) iter.Seq[*types.FunctionAssociation] {
+	return func(yield func(*types.FunctionAssociation) bool) {
+		var associations []*types.FunctionAssociations
+		if cfg.DefaultCacheBehavior != nil {
+			associations = append(associations, cfg.DefaultCacheBehavior.FunctionAssociations)
+		}
+		if cfg.CacheBehaviors != nil {
+			for i := range cfg.CacheBehaviors.Items {
+				associations = append(associations, cfg.CacheBehaviors.Items[i].FunctionAssociations)
+			}
+		}
+		for _, as := range associations {
+			if as == nil {
+				continue
+			}
+			for i := range as.Items {
+				if !yield(&as.Items[i]) {
+					return
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,424 @@
+package frontier_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aereal/frontier"
+	"github.com/aereal/frontier/cf"
+	"github.com/aereal/frontier/internal/cfmock"
+	"github.com/aereal/frontier/lock"
+	"github.com/aws/aws-sdk-go-v2/service/cloudfront"
+	"github.com/aws/aws-sdk-go-v2/service/cloudfront/types"
+	"github.com/google/go-cmp/cmp"
+	"go.uber.org/mock/gomock"
+)
+
+const (
+	canaryPrimaryID  = "EPRIMARY"
+	canaryStagingID  = "ESTAGING"
+	canaryStagingDNS = "staging.cloudfront.net"
+	canaryPolicyID   = "policy-1"
+	mainFunctionARN  = "arn:aws:cloudfront::123456789012:function/test-func"
+	canaryFuncARN    = "arn:aws:cloudfront::123456789012:function/test-func-canary"
+)
+
+func TestCanary_Start(t *testing.T) {
+	weighted := &frontier.CanaryTraffic{Weight: 0.05}
+	testCases := []struct {
+		name       string
+		traffic    *frontier.CanaryTraffic
+		mock       func(c *cfmock.MockCloudFrontClient)
+		wantResult *frontier.CanaryResult
+		wantErr    error
+	}{
+		{
+			name:    "first time",
+			traffic: weighted,
+			mock: func(c *cfmock.MockCloudFrontClient) {
+				returnDistributionConfig(c, canaryPrimaryID, "etag-primary", distributionConfigWith(mainFunctionARN, ""))
+				returnFunctionARN(c, "test-func", mainFunctionARN)
+				expectCanaryDeployed(c)
+				c.EXPECT().
+					CopyDistribution(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, input *cloudfront.CopyDistributionInput, _ ...func(*cloudfront.Options)) (*cloudfront.CopyDistributionOutput, error) {
+						if *input.PrimaryDistributionId != canaryPrimaryID || *input.IfMatch != "etag-primary" || !*input.Staging {
+							t.Errorf("unexpected CopyDistributionInput: %#v", input)
+						}
+						return &cloudfront.CopyDistributionOutput{
+							Distribution: &types.Distribution{Id: ref(canaryStagingID), DomainName: ref(canaryStagingDNS)},
+							ETag:         ref("etag-staging"),
+						}, nil
+					}).
+					Times(1)
+				returnDistributionConfig(c, canaryStagingID, "etag-staging", distributionConfigWith(mainFunctionARN, ""))
+				expectDistributionUpdated(c, canaryStagingID, "etag-staging", distributionConfigWith(canaryFuncARN, ""))
+				c.EXPECT().
+					CreateContinuousDeploymentPolicy(gomock.Any(), &cloudfront.CreateContinuousDeploymentPolicyInput{
+						ContinuousDeploymentPolicyConfig: policyConfig(true, &types.TrafficConfig{
+							Type:               types.ContinuousDeploymentPolicyTypeSingleWeight,
+							SingleWeightConfig: &types.ContinuousDeploymentSingleWeightConfig{Weight: ref[float32](0.05)},
+						}),
+					}).
+					Return(&cloudfront.CreateContinuousDeploymentPolicyOutput{ContinuousDeploymentPolicy: &types.ContinuousDeploymentPolicy{Id: ref(canaryPolicyID)}}, nil).
+					Times(1)
+				expectDistributionUpdated(c, canaryPrimaryID, "etag-primary", distributionConfigWith(mainFunctionARN, canaryPolicyID))
+			},
+			wantResult: &frontier.CanaryResult{
+				Status:                frontier.CanaryStatusStarted,
+				FunctionName:          "test-func",
+				CanaryFunctionName:    "test-func-canary",
+				DistributionID:        canaryPrimaryID,
+				StagingDistributionID: canaryStagingID,
+				PolicyID:              canaryPolicyID,
+			},
+		},
+		{
+			name:    "reuse the staging distribution",
+			traffic: &frontier.CanaryTraffic{Header: "aws-cf-cd-canary", HeaderValue: "true"},
+			mock: func(c *cfmock.MockCloudFrontClient) {
+				returnDistributionConfig(c, canaryPrimaryID, "etag-primary", distributionConfigWith(mainFunctionARN, canaryPolicyID))
+				returnPolicy(c, false)
+				returnStagingDistribution(c)
+				returnFunctionARN(c, "test-func", mainFunctionARN)
+				expectCanaryDeployed(c)
+				returnDistributionConfig(c, canaryStagingID, "etag-staging", distributionConfigWith(mainFunctionARN, ""))
+				expectDistributionUpdated(c, canaryStagingID, "etag-staging", distributionConfigWith(canaryFuncARN, ""))
+				c.EXPECT().
+					UpdateContinuousDeploymentPolicy(gomock.Any(), &cloudfront.UpdateContinuousDeploymentPolicyInput{
+						Id:      ref(canaryPolicyID),
+						IfMatch: ref("etag-policy"),
+						ContinuousDeploymentPolicyConfig: policyConfig(true, &types.TrafficConfig{
+							Type:               types.ContinuousDeploymentPolicyTypeSingleHeader,
+							SingleHeaderConfig: &types.ContinuousDeploymentSingleHeaderConfig{Header: ref("aws-cf-cd-canary"), Value: ref("true")},
+						}),
+					}).
+					Return(&cloudfront.UpdateContinuousDeploymentPolicyOutput{}, nil).
+					Times(1)
+			},
+			wantResult: &frontier.CanaryResult{
+				Status:                frontier.CanaryStatusStarted,
+				FunctionName:          "test-func",
+				CanaryFunctionName:    "test-func-canary",
+				DistributionID:        canaryPrimaryID,
+				StagingDistributionID: canaryStagingID,
+				PolicyID:              canaryPolicyID,
+			},
+		},
+		{
+			name:    "in progress",
+			traffic: weighted,
+			mock: func(c *cfmock.MockCloudFrontClient) {
+				returnDistributionConfig(c, canaryPrimaryID, "etag-primary", distributionConfigWith(mainFunctionARN, canaryPolicyID))
+				returnPolicy(c, true)
+				returnStagingDistribution(c)
+			},
+			wantErr: frontier.ErrCanaryInProgress,
+		},
+		{
+			name:    "not associated",
+			traffic: weighted,
+			mock: func(c *cfmock.MockCloudFrontClient) {
+				returnDistributionConfig(c, canaryPrimaryID, "etag-primary", distributionConfigWith("arn:aws:cloudfront::123456789012:function/other", ""))
+				returnFunctionARN(c, "test-func", mainFunctionARN)
+			},
+			wantErr: &frontier.FunctionNotAssociatedError{FunctionName: "test-func", DistributionID: canaryPrimaryID},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			if deadline, ok := t.Deadline(); ok {
+				ctx, cancel = context.WithDeadline(ctx, deadline)
+			}
+			defer cancel()
+
+			ctrl := gomock.NewController(t)
+			client := cfmock.NewMockCloudFrontClient(ctrl)
+			tc.mock(client)
+			canary := frontier.NewCanary(frontier.NewDeployer(&cf.StaticCFProvider{Client: client}))
+			gotResult, gotErr := canary.StartCanary(ctx, "./testdata/config.yml", canaryPrimaryID, tc.traffic)
+			if !errors.Is(gotErr, tc.wantErr) {
+				t.Errorf("error:\n\twant: %s (%T)\n\t got: %s (%T)", tc.wantErr, tc.wantErr, gotErr, gotErr)
+			}
+			if diff := cmp.Diff(tc.wantResult, gotResult); diff != "" {
+				t.Errorf("result (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestCanary_Promote(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	if deadline, ok := t.Deadline(); ok {
+		ctx, cancel = context.WithDeadline(ctx, deadline)
+	}
+	defer cancel()
+
+	ctrl := gomock.NewController(t)
+	c := cfmock.NewMockCloudFrontClient(ctrl)
+	returnDistributionConfig(c, canaryPrimaryID, "etag-primary", distributionConfigWith(mainFunctionARN, canaryPolicyID))
+	returnPolicy(c, true)
+	returnStagingDistribution(c)
+	returnFunctionARN(c, "test-func", mainFunctionARN)
+	c.EXPECT().
+		GetFunction(gomock.Any(), &cloudfront.GetFunctionInput{Name: ref("test-func-canary"), Stage: types.FunctionStageLive}).
+		Return(&cloudfront.GetFunctionOutput{FunctionCode: functionCode, ETag: ref("etag-canary")}, nil).
+		Times(1)
+	returnFunctionARN(c, "test-func-canary", canaryFuncARN)
+	c.EXPECT().
+		GetFunction(gomock.Any(), &cloudfront.GetFunctionInput{Name: ref("test-func")}).
+		Return(&cloudfront.GetFunctionOutput{ETag: ref("etag-main")}, nil).
+		Times(1)
+	c.EXPECT().
+		UpdateFunction(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, input *cloudfront.UpdateFunctionInput, _ ...func(*cloudfront.Options)) (*cloudfront.UpdateFunctionOutput, error) {
+			if *input.Name != "test-func" || string(input.FunctionCode) != string(functionCode) {
+				t.Errorf("unexpected UpdateFunctionInput: %#v", input)
+			}
+			return &cloudfront.UpdateFunctionOutput{ETag: ref("etag-main-updated")}, nil
+		}).
+		Times(1)
+	c.EXPECT().
+		PublishFunction(gomock.Any(), &cloudfront.PublishFunctionInput{Name: ref("test-func"), IfMatch: ref("etag-main-updated")}).
+		Return(&cloudfront.PublishFunctionOutput{}, nil).
+		Times(1)
+	returnDistributionConfig(c, canaryStagingID, "etag-staging", distributionConfigWith(canaryFuncARN, ""))
+	expectDistributionUpdated(c, canaryStagingID, "etag-staging", distributionConfigWith(mainFunctionARN, ""))
+	returnDistributionConfig(c, canaryStagingID, "etag-staging-updated", distributionConfigWith(mainFunctionARN, ""))
+	c.EXPECT().
+		UpdateDistributionWithStagingConfig(gomock.Any(), &cloudfront.UpdateDistributionWithStagingConfigInput{
+			Id:                    ref(canaryPrimaryID),
+			StagingDistributionId: ref(canaryStagingID),
+			IfMatch:               ref("etag-primary, etag-staging-updated"),
+		}).
+		Return(&cloudfront.UpdateDistributionWithStagingConfigOutput{}, nil).
+		Times(1)
+	expectPolicyDisabled(c)
+
+	canary := frontier.NewCanary(frontier.NewDeployer(&cf.StaticCFProvider{Client: c}))
+	got, err := canary.PromoteCanary(ctx, "./testdata/config.yml", canaryPrimaryID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &frontier.CanaryResult{
+		Status:                frontier.CanaryStatusPromoted,
+		FunctionName:          "test-func",
+		CanaryFunctionName:    "test-func-canary",
+		DistributionID:        canaryPrimaryID,
+		StagingDistributionID: canaryStagingID,
+		PolicyID:              canaryPolicyID,
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("result (-want, +got):\n%s", diff)
+	}
+}
+
+func TestCanary_Abort(t *testing.T) {
+	testCases := []struct {
+		name       string
+		mock       func(c *cfmock.MockCloudFrontClient)
+		wantResult *frontier.CanaryResult
+		wantErr    error
+	}{
+		{
+			name: "ok",
+			mock: func(c *cfmock.MockCloudFrontClient) {
+				returnDistributionConfig(c, canaryPrimaryID, "etag-primary", distributionConfigWith(mainFunctionARN, canaryPolicyID))
+				returnPolicy(c, true)
+				returnStagingDistribution(c)
+				expectPolicyDisabled(c)
+			},
+			wantResult: &frontier.CanaryResult{
+				Status:                frontier.CanaryStatusAborted,
+				FunctionName:          "test-func",
+				CanaryFunctionName:    "test-func-canary",
+				DistributionID:        canaryPrimaryID,
+				StagingDistributionID: canaryStagingID,
+				PolicyID:              canaryPolicyID,
+			},
+		},
+		{
+			name: "not started",
+			mock: func(c *cfmock.MockCloudFrontClient) {
+				returnDistributionConfig(c, canaryPrimaryID, "etag-primary", distributionConfigWith(mainFunctionARN, ""))
+			},
+			wantErr: frontier.ErrCanaryNotStarted,
+		},
+		{
+			name: "already aborted",
+			mock: func(c *cfmock.MockCloudFrontClient) {
+				returnDistributionConfig(c, canaryPrimaryID, "etag-primary", distributionConfigWith(mainFunctionARN, canaryPolicyID))
+				returnPolicy(c, false)
+				returnStagingDistribution(c)
+			},
+			wantErr: frontier.ErrCanaryNotStarted,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			if deadline, ok := t.Deadline(); ok {
+				ctx, cancel = context.WithDeadline(ctx, deadline)
+			}
+			defer cancel()
+
+			ctrl := gomock.NewController(t)
+			client := cfmock.NewMockCloudFrontClient(ctrl)
+			tc.mock(client)
+			canary := frontier.NewCanary(frontier.NewDeployer(&cf.StaticCFProvider{Client: client}))
+			gotResult, gotErr := canary.AbortCanary(ctx, "./testdata/config.yml", canaryPrimaryID)
+			if !errors.Is(gotErr, tc.wantErr) {
+				t.Errorf("error:\n\twant: %s (%T)\n\t got: %s (%T)", tc.wantErr, tc.wantErr, gotErr, gotErr)
+			}
+			if diff := cmp.Diff(tc.wantResult, gotResult); diff != "" {
+				t.Errorf("result (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestCanary_Abort_locked(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	if deadline, ok := t.Deadline(); ok {
+		ctx, cancel = context.WithDeadline(ctx, deadline)
+	}
+	defer cancel()
+
+	dir := t.TempDir()
+	if _, err := lock.NewFileLocker(dir, lock.Owner("other")).TryLock(ctx, "test-func", time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	ctrl := gomock.NewController(t)
+	client := cfmock.NewMockCloudFrontClient(ctrl)
+	canary := frontier.NewCanary(frontier.NewDeployer(&cf.StaticCFProvider{Client: client}, frontier.WithLocker(lock.NewFileLocker(dir))))
+	_, err := canary.AbortCanary(ctx, "./testdata/config.yml", canaryPrimaryID)
+	var heldErr *lock.HeldError
+	if !errors.As(err, &heldErr) {
+		t.Fatalf("want HeldError, got %T %v", err, err)
+	}
+	if heldErr.Lease.Owner != "other" {
+		t.Errorf("owner: want other, got %s", heldErr.Lease.Owner)
+	}
+}
+
+func distributionConfigWith(functionARN, policyID string) *types.DistributionConfig {
+	associations := func() *types.FunctionAssociations {
+		return &types.FunctionAssociations{
+			Quantity: ref[int32](1),
+			Items:    []types.FunctionAssociation{{EventType: types.EventTypeViewerRequest, FunctionARN: ref(functionARN)}},
+		}
+	}
+	cfg := &types.DistributionConfig{
+		DefaultCacheBehavior: &types.DefaultCacheBehavior{TargetOriginId: ref("origin"), FunctionAssociations: associations()},
+		CacheBehaviors: &types.CacheBehaviors{
+			Quantity: ref[int32](1),
+			Items:    []types.CacheBehavior{{PathPattern: ref("/api/*"), TargetOriginId: ref("origin"), FunctionAssociations: associations()}},
+		},
+	}
+	if policyID != "" {
+		cfg.ContinuousDeploymentPolicyId = ref(policyID)
+	}
+	return cfg
+}
+
+func policyConfig(enabled bool, traffic *types.TrafficConfig) *types.ContinuousDeploymentPolicyConfig {
+	return &types.ContinuousDeploymentPolicyConfig{
+		Enabled:                     ref(enabled),
+		StagingDistributionDnsNames: &types.StagingDistributionDnsNames{Quantity: ref[int32](1), Items: []string{canaryStagingDNS}},
+		TrafficConfig:               traffic,
+	}
+}
+
+var canaryWeightedTraffic = &types.TrafficConfig{
+	Type:               types.ContinuousDeploymentPolicyTypeSingleWeight,
+	SingleWeightConfig: &types.ContinuousDeploymentSingleWeightConfig{Weight: ref[float32](0.05)},
+}
+
+func returnDistributionConfig(c *cfmock.MockCloudFrontClient, id, etag string, cfg *types.DistributionConfig) {
+	c.EXPECT().
+		GetDistributionConfig(gomock.Any(), &cloudfront.GetDistributionConfigInput{Id: ref(id)}).
+		Return(&cloudfront.GetDistributionConfigOutput{DistributionConfig: cfg, ETag: ref(etag)}, nil).
+		Times(1)
+}
+
+func expectDistributionUpdated(c *cfmock.MockCloudFrontClient, id, etag string, cfg *types.DistributionConfig) {
+	c.EXPECT().
+		UpdateDistribution(gomock.Any(), &cloudfront.UpdateDistributionInput{Id: ref(id), IfMatch: ref(etag), DistributionConfig: cfg}).
+		Return(&cloudfront.UpdateDistributionOutput{}, nil).
+		Times(1)
+}
+
+func returnFunctionARN(c *cfmock.MockCloudFrontClient, name, arn string) {
+	c.EXPECT().
+		DescribeFunction(gomock.Any(), &cloudfront.DescribeFunctionInput{Name: ref(name), Stage: types.FunctionStageLive}).
+		Return(&cloudfront.DescribeFunctionOutput{
+			FunctionSummary: &types.FunctionSummary{
+				Name:             ref(name),
+				FunctionConfig:   &types.FunctionConfig{Comment: ref("blah blah"), Runtime: types.FunctionRuntimeCloudfrontJs10},
+				FunctionMetadata: &types.FunctionMetadata{FunctionARN: ref(arn)},
+			},
+		}, nil).
+		Times(1)
+}
+
+func returnPolicy(c *cfmock.MockCloudFrontClient, enabled bool) {
+	c.EXPECT().
+		GetContinuousDeploymentPolicy(gomock.Any(), &cloudfront.GetContinuousDeploymentPolicyInput{Id: ref(canaryPolicyID)}).
+		Return(&cloudfront.GetContinuousDeploymentPolicyOutput{
+			ContinuousDeploymentPolicy: &types.ContinuousDeploymentPolicy{
+				Id:                               ref(canaryPolicyID),
+				ContinuousDeploymentPolicyConfig: policyConfig(enabled, canaryWeightedTraffic),
+			},
+			ETag: ref("etag-policy"),
+		}, nil).
+		Times(1)
+}
+
+func returnStagingDistribution(c *cfmock.MockCloudFrontClient) {
+	c.EXPECT().
+		ListDistributions(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(&cloudfront.ListDistributionsOutput{
+			DistributionList: &types.DistributionList{
+				Items: []types.DistributionSummary{
+					{Id: ref(canaryPrimaryID), DomainName: ref("primary.cloudfront.net"), Staging: ref(false)},
+					{Id: ref(canaryStagingID), DomainName: ref(canaryStagingDNS), Staging: ref(true)},
+				},
+			},
+		}, nil).
+		Times(1)
+}
+
+func expectCanaryDeployed(c *cfmock.MockCloudFrontClient) {
+	c.EXPECT().
+		GetFunction(gomock.Any(), &cloudfront.GetFunctionInput{Name: ref("test-func-canary")}).
+		Return(nil, errNoSuchFn).
+		Times(1)
+	c.EXPECT().
+		CreateFunction(gomock.Any(), &cloudfront.CreateFunctionInput{
+			Name:           ref("test-func-canary"),
+			FunctionCode:   functionCode,
+			FunctionConfig: &types.FunctionConfig{Comment: ref("blah blah"), Runtime: types.FunctionRuntimeCloudfrontJs10},
+		}).
+		Return(&cloudfront.CreateFunctionOutput{ETag: ref("etag-canary")}, nil).
+		Times(1)
+	c.EXPECT().
+		PublishFunction(gomock.Any(), &cloudfront.PublishFunctionInput{Name: ref("test-func-canary"), IfMatch: ref("etag-canary")}).
+		Return(&cloudfront.PublishFunctionOutput{
+			FunctionSummary: &types.FunctionSummary{FunctionMetadata: &types.FunctionMetadata{FunctionARN: ref(canaryFuncARN)}},
+		}, nil).
+		Times(1)
+}
+
+func expectPolicyDisabled(c *cfmock.MockCloudFrontClient) {
+	c.EXPECT().
+		UpdateContinuousDeploymentPolicy(gomock.Any(), &cloudfront.UpdateContinuousDeploymentPolicyInput{
+			Id:                               ref(canaryPolicyID),
+			IfMatch:                          ref("etag-policy"),
+			ContinuousDeploymentPolicyConfig: policyConfig(false, canaryWeightedTraffic),
+		}).
+		Return(&cloudfront.UpdateContinuousDeploymentPolicyOutput{}, nil).
+		Times(1)
+}
@@ -0,0 +1,147 @@
+package cf
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
+	"github.com/aws/aws-sdk-go-v2/service/cloudfront"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/aws/aws-sdk-go-v2/otelaws"
+)
+
+type CloudFrontClient interface {
+	CopyDistribution(ctx context.Context, params *cloudfront.CopyDistributionInput, optFns ...func(*cloudfront.Options)) (*cloudfront.CopyDistributionOutput, error)
+	CreateContinuousDeploymentPolicy(ctx context.Context, params *cloudfront.CreateContinuousDeploymentPolicyInput, optFns ...func(*cloudfront.Options)) (*cloudfront.CreateContinuousDeploymentPolicyOutput, error)
+	CreateFunction(ctx context.Context, params *cloudfront.CreateFunctionInput, optFns ...func(*cloudfront.Options)) (*cloudfront.CreateFunctionOutput, error)
+	DescribeFunction(ctx context.Context, params *cloudfront.DescribeFunctionInput, optFns ...func(*cloudfront.Options)) (*cloudfront.DescribeFunctionOutput, error)
+	GetContinuousDeploymentPolicy(ctx context.Context, params *cloudfront.GetContinuousDeploymentPolicyInput, optFns ...func(*cloudfront.Options)) (*cloudfront.GetContinuousDeploymentPolicyOutput, error)
+	GetDistributionConfig(ctx context.Context, params *cloudfront.GetDistributionConfigInput, optFns ...func(*cloudfront.Options)) (*cloudfront.GetDistributionConfigOutput, error)
+	GetFunction(ctx context.Context, params *cloudfront.GetFunctionInput, optFns ...func(*cloudfront.Options)) (*cloudfront.GetFunctionOutput, error)
+	ListDistributions(context.Context, *cloudfront.ListDistributionsInput, ...func(*cloudfront.Options)) (*cloudfront.ListDistributionsOutput, error)
+	PublishFunction(ctx context.Context, params *cloudfront.PublishFunctionInput, optFns ...func(*cloudfront.Options)) (*cloudfront.PublishFunctionOutput, error)
+	TestFunction(ctx context.Context, params *cloudfront.TestFunctionInput, optFns ...func(*cloudfront.Options)) (*cloudfront.TestFunctionOutput, error)
+	UpdateContinuousDeploymentPolicy(ctx context.Context, params *cloudfront.UpdateContinuousDeploymentPolicyInput, optFns ...func(*cloudfront.Options)) (*cloudfront.UpdateContinuousDeploymentPolicyOutput, error)
+	UpdateDistribution(ctx context.Context, params *cloudfront.UpdateDistributionInput, optFns ...func(*cloudfront.Options)) (*cloudfront.UpdateDistributionOutput, error)
+	UpdateDistributionWithStagingConfig(ctx context.Context, params *cloudfront.UpdateDistributionWithStagingConfigInput, optFns ...func(*cloudfront.Options)) (*cloudfront.UpdateDistributionWithStagingConfigOutput, error)
+	UpdateFunction(ctx context.Context, params *cloudfront.UpdateFunctionInput, optFns ...func(*cloudfront.Options)) (*cloudfront.UpdateFunctionOutput, error)
+}
+
+type Provider interface {
+	ProvideCloudFrontClient(ctx context.Context) (CloudFrontClient, error)
+}
+
+type Identity struct {
+	AccountID string
+	Region    string
+}
+
+type IdentityProvider interface {
+	ProvideIdentity(ctx context.Context) (*Identity, error)
+}
+
+type StaticCFProvider struct {
+	Client   CloudFrontClient
+	Identity *Identity
+}
+
+var (
+	_ Provider         = (*StaticCFProvider)(nil)
+	_ IdentityProvider = (*StaticCFProvider)(nil)
+)
+
+func (p *StaticCFProvider) ProvideCloudFrontClient(context.Context) (CloudFrontClient, error) { //nolint:ireturn
+	return p.Client, nil
+}
+
+func (p *StaticCFProvider) ProvideIdentity(context.Context) (*Identity, error) {
+	return p.Identity, nil
+}
+
+type SDKOptions struct {
+	Profile         string
+	Region          string
+	AssumeRoleARN   string
+	ExternalID      string
+	RoleSessionName string
+	EndpointURL     string
+	CassettePath    string
+}
+
+type SDKProvider struct {
+	recorder *Recorder
+	Options  SDKOptions
+}
+
+var (
+	_ Provider         = (*SDKProvider)(nil)
+	_ IdentityProvider = (*SDKProvider)(nil)
+)
+
+func (b *SDKProvider) ConfigureSDK(opts SDKOptions) {
+	b.Options = opts
+	b.recorder = nil
+	if opts.CassettePath != "" {
+		b.recorder = NewRecorder(opts.CassettePath, nil)
+	}
+}
+
+func (b *SDKProvider) ProvideCloudFrontClient(ctx context.Context) (CloudFrontClient, error) { //nolint:ireturn
+	cfg, err := b.loadConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var optFns []func(*cloudfront.Options)
+	if b.Options.EndpointURL != "" {
+		optFns = append(optFns, func(o *cloudfront.Options) { o.BaseEndpoint = aws.String(b.Options.EndpointURL) })
+	}
+	if b.recorder != nil {
+		// only CloudFront is recorded: the STS requests of the identity and the assumed role carry the credentials
+		optFns = append(optFns, func(o *cloudfront.Options) { o.HTTPClient = b.recorder })
+	}
+	return cloudfront.NewFromConfig(cfg, optFns...), nil
+}
+
+func (b *SDKProvider) ProvideIdentity(ctx context.Context) (*Identity, error) {
+	cfg, err := b.loadConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+	out, err := sts.NewFromConfig(cfg).GetCallerIdentity(ctx, &sts.GetCallerIdentityInput{})
+	if err != nil {
+		return nil, err
+	}
+	identity := &Identity{Region: cfg.Region}
+	if out.Account != nil {
+		identity.AccountID = *out.Account
+	}
+	return identity, nil
+}
+
+func (b *SDKProvider) loadConfig(ctx context.Context) (aws.Config, error) {
+	var loadOpts []func(*config.LoadOptions) error
+	if b.Options.Profile != "" {
+		loadOpts = append(loadOpts, config.WithSharedConfigProfile(b.Options.Profile))
+	}
+	if b.Options.Region != "" {
+		loadOpts = append(loadOpts, config.WithRegion(b.Options.Region))
+	}
+	cfg, err := config.LoadDefaultConfig(ctx, loadOpts...)
+	if err != nil {
+		return aws.Config{}, err
+	}
+	otelaws.AppendMiddlewares(&cfg.APIOptions)
+	if b.Options.AssumeRoleARN != "" {
+		provider := stscreds.NewAssumeRoleProvider(sts.NewFromConfig(cfg), b.Options.AssumeRoleARN, func(o *stscreds.AssumeRoleOptions) {
+			if b.Options.ExternalID != "" {
+				o.ExternalID = aws.String(b.Options.ExternalID)
+			}
+			if b.Options.RoleSessionName != "" {
+				o.RoleSessionName = b.Options.RoleSessionName
+			}
+		})
+		cfg.Credentials = aws.NewCredentialsCache(provider)
+	}
+	return cfg, nil
+}
@@ -2,14 +2,17 @@ package main
 
 import (
 	"context"
-	"log/slog"
 	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
 
 	"github.com/aereal/frontier"
+	"github.com/aereal/frontier/cf"
 	"github.com/aereal/frontier/controller/listdist"
-	"github.com/aereal/frontier/internal/cf"
+	"github.com/aereal/frontier/fnarn"
 	"github.com/aereal/frontier/internal/cli"
-	"github.com/aereal/frontier/internal/fnarn"
+	"github.com/aereal/frontier/lock"
 )
 
 func main() {
@@ -17,20 +20,48 @@ func main() {
 }
 
 func run() int {
-	sh := slog.NewJSONHandler(os.Stderr, &slog.HandlerOptions{})
-	sl := slog.New(sh)
-	slog.SetDefault(sl)
-	var cfBuilder cf.SDKProvider
+	cfBuilder := new(cf.SDKProvider)
 	arnResolver := fnarn.NewResolver(cfBuilder)
+	importer := frontier.NewImporter(cfBuilder)
+	withLocker := frontier.WithLocker(lock.NewFileLocker(lockDir()))
+	deployer := frontier.NewDeployer(cfBuilder, withLocker)
 	controllers := cli.Controllers{
 		RenderController:            frontier.NewRenderer(),
-		ImportController:            frontier.NewImporter(cfBuilder),
-		DeployController:            frontier.NewDeployer(cfBuilder),
-		ListDistributionsController: listdist.NewController(cfBuilder),
+		ImportController:            importer,
+		DeployController:            deployer,
+		UnlockController:            deployer,
+		ExportController:            frontier.NewExporter(),
+		DriftController:             frontier.NewDriftDetector(cfBuilder),
+		ListDistributionsController: listdist.NewController(cfBuilder, listDistributionsOptions(cfBuilder)...),
+		WatchController:             frontier.NewWatcher(deployer),
+		CompareController:           frontier.NewComparer(importer),
+		PromoteController:           frontier.NewPromoter(withLocker),
+		CanaryController:            frontier.NewCanary(deployer),
+		StatsController:             frontier.NewStatsReporter(cfBuilder),
+		LintController:              frontier.NewLinter(),
+		InitController:              frontier.NewInitializer(),
+		SDKConfigurer:               cfBuilder,
 	}
-	if err := cli.New(os.Stdin, os.Stdout, os.Stderr, controllers, arnResolver).Run(context.Background(), os.Args); err != nil {
-		slog.Error(err.Error(), slog.String("error", err.Error()))
-		return 1
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	return cli.New(os.Stdin, os.Stdout, os.Stderr, controllers, arnResolver).Execute(ctx, os.Args)
+}
+
+func listDistributionsOptions(identityProvider cf.IdentityProvider) []listdist.NewControllerOption {
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return nil
+	}
+	cache := listdist.NewCache(filepath.Join(cacheDir, "frontier", "distributions"), identityProvider)
+	return []listdist.NewControllerOption{listdist.WithCache(cache)}
+}
+
+func lockDir() string {
+	if dir := os.Getenv("FRONTIER_LOCK_DIR"); dir != "" {
+		return dir
+	}
+	if cacheDir, err := os.UserCacheDir(); err == nil {
+		return filepath.Join(cacheDir, "frontier", "locks")
 	}
-	return 0
+	return filepath.Join(os.TempDir(), "frontier", "locks")
 }
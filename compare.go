@@ -0,0 +1,152 @@
+package frontier
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/service/cloudfront/types"
+	"github.com/pmezard/go-difflib/difflib"
+	"gopkg.in/yaml.v3"
+)
+
+// CompareTarget is a side of the comparison: the function deployed to the stage, or the local config if ConfigPath is given.
+type CompareTarget struct {
+	FunctionName string
+	Stage        types.FunctionStage
+	ConfigPath   string
+}
+
+func (t *CompareTarget) String() string {
+	if t.ConfigPath != "" {
+		return t.ConfigPath
+	}
+	stage := t.Stage
+	if stage == "" {
+		stage = types.FunctionStageDevelopment
+	}
+	return t.FunctionName + "@" + string(stage)
+}
+
+// CompareResult holds the differences between the configs and the codes of two functions in the unified diff format.
+// The diff is empty if both are same.
+type CompareResult struct {
+	A          string
+	B          string
+	ConfigDiff string
+	CodeDiff   string
+}
+
+// Identical tells both the config and the code are same.
+func (r *CompareResult) Identical() bool {
+	return r.ConfigDiff == "" && r.CodeDiff == ""
+}
+
+// NewComparer returns the Comparer that fetches the deployed functions in the same way as the importer.
+func NewComparer(importer *Importer) *Comparer {
+	return &Comparer{importer: importer}
+}
+
+type Comparer struct {
+	importer *Importer
+}
+
+func (c *Comparer) Compare(ctx context.Context, a, b *CompareTarget) (_ *CompareResult, err error) {
+	ctx, span := startSpan(ctx, "frontier.Compare")
+	defer func() { endSpan(span, err) }()
+	configA, codeA, err := c.load(ctx, a)
+	if err != nil {
+		return nil, err
+	}
+	configB, codeB, err := c.load(ctx, b)
+	if err != nil {
+		return nil, err
+	}
+	result := &CompareResult{A: a.String(), B: b.String()}
+	// the labels are suffixed to tell the config diff from the code diff when both are printed
+	result.ConfigDiff, err = unifiedDiff(configA, configB, result.A+" (config)", result.B+" (config)")
+	if err != nil {
+		return nil, err
+	}
+	result.CodeDiff, err = unifiedDiff(codeA, codeB, result.A+" (code)", result.B+" (code)")
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// load returns the function config rendered in YAML and the code of the target.
+// The config excludes the name and the fields only the local config has, such as the code path and the hooks.
+func (c *Comparer) load(ctx context.Context, target *CompareTarget) ([]byte, []byte, error) {
+	var (
+		fn   *Function
+		code []byte
+	)
+	if target.ConfigPath != "" {
+		var err error
+		fn, err = ParseConfigFromPath(target.ConfigPath)
+		if err != nil {
+			return nil, nil, err
+		}
+		if fn.Code == nil || fn.Code.Path == "" {
+			return nil, nil, &InvalidConfigError{Field: "code.path", Reason: "is required"}
+		}
+		code, err = os.ReadFile(fn.Code.Path)
+		if err != nil {
+			return nil, nil, err
+		}
+	} else {
+		var (
+			remote *remoteFunction
+			err    error
+		)
+		fn, remote, err = c.importer.fetchFunction(ctx, target.FunctionName, target.Stage)
+		if err != nil {
+			return nil, nil, err
+		}
+		code = remote.Code
+	}
+	cfg := fn.Config
+	if cfg == nil {
+		cfg = new(FunctionConfig)
+	}
+	buf := new(bytes.Buffer)
+	enc := yaml.NewEncoder(buf)
+	enc.SetIndent(c.importer.indent)
+	if err := enc.Encode(&comparedConfig{Config: cfg}); err != nil {
+		return nil, nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, nil, err
+	}
+	return buf.Bytes(), code, nil
+}
+
+type comparedConfig struct {
+	Config *FunctionConfig `yaml:"config"`
+}
+
+func unifiedDiff(a, b []byte, fromFile, toFile string) (string, error) {
+	if bytes.Equal(a, b) {
+		return "", nil
+	}
+	return difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        splitLines(a),
+		B:        splitLines(b),
+		FromFile: fromFile,
+		ToFile:   toFile,
+		Context:  3,
+	})
+}
+
+// splitLines splits the text into the lines ending with the newline, unlike [difflib.SplitLines] that adds the empty last line.
+func splitLines(b []byte) []string {
+	lines := strings.SplitAfter(string(b), "\n")
+	last := len(lines) - 1
+	if lines[last] == "" {
+		return lines[:last]
+	}
+	lines[last] += "\n"
+	return lines
+}
@@ -0,0 +1,89 @@
+package frontier_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aereal/frontier"
+	"github.com/aereal/frontier/cf"
+	"github.com/aereal/frontier/internal/cfmock"
+	"github.com/aws/aws-sdk-go-v2/service/cloudfront/types"
+	"github.com/google/go-cmp/cmp"
+	"go.uber.org/mock/gomock"
+)
+
+func TestComparer_Compare(t *testing.T) {
+	local := &frontier.CompareTarget{ConfigPath: "./testdata/config.yml"}
+	dev := &frontier.CompareTarget{FunctionName: "test-func", Stage: types.FunctionStageDevelopment}
+	live := &frontier.CompareTarget{FunctionName: "test-func", Stage: types.FunctionStageLive}
+	testCases := []struct {
+		name       string
+		a, b       *frontier.CompareTarget
+		mock       func(c *cfmock.MockCloudFrontClient)
+		wantResult *frontier.CompareResult
+		wantErr    error
+	}{
+		{
+			name: "identical",
+			a:    local,
+			b:    dev,
+			mock: func(c *cfmock.MockCloudFrontClient) {
+				returnStage(c, types.FunctionStageDevelopment, functionCode, "blah blah")
+			},
+			wantResult: &frontier.CompareResult{A: "./testdata/config.yml", B: "test-func@DEVELOPMENT"},
+		},
+		{
+			name: "different",
+			a:    dev,
+			b:    live,
+			mock: func(c *cfmock.MockCloudFrontClient) {
+				returnStage(c, types.FunctionStageDevelopment, functionCode, "blah blah")
+				returnStage(c, types.FunctionStageLive, []byte("function handler(event) {\n  return event.request;\n}\n"), "hot fix")
+			},
+			wantResult: &frontier.CompareResult{
+				A:          "test-func@DEVELOPMENT",
+				B:          "test-func@LIVE",
+				ConfigDiff: "--- test-func@DEVELOPMENT (config)\n+++ test-func@LIVE (config)\n@@ -1,3 +1,3 @@\n config:\n-  comment: blah blah\n+  comment: hot fix\n   runtime: cloudfront-js-1.0\n",
+				CodeDiff:   "--- test-func@DEVELOPMENT (code)\n+++ test-func@LIVE (code)\n@@ -1,3 +1,3 @@\n function handler(event) {\n-  return event.response;\n+  return event.request;\n }\n",
+			},
+		},
+		{
+			name: "not found",
+			a:    local,
+			b:    live,
+			mock: func(c *cfmock.MockCloudFrontClient) {
+				returnNoSuchFunction(c, types.FunctionStageLive)
+			},
+			wantErr: errNoSuchFn,
+		},
+		{
+			name:    "config without code",
+			a:       &frontier.CompareTarget{ConfigPath: "./testdata/config-without-code.yml"},
+			b:       live,
+			mock:    func(*cfmock.MockCloudFrontClient) {},
+			wantErr: &frontier.InvalidConfigError{Field: "code.path", Reason: "is required"},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			if deadline, ok := t.Deadline(); ok {
+				ctx, cancel = context.WithDeadline(ctx, deadline)
+			}
+			defer cancel()
+
+			ctrl := gomock.NewController(t)
+			client := cfmock.NewMockCloudFrontClient(ctrl)
+			tc.mock(client)
+			comparer := frontier.NewComparer(frontier.NewImporter(&cf.StaticCFProvider{Client: client}))
+			gotResult, gotErr := comparer.Compare(ctx, tc.a, tc.b)
+			if !errors.Is(gotErr, tc.wantErr) {
+				t.Errorf("error:\n\twant: %s (%T)\n\t got: %s (%T)", tc.wantErr, tc.wantErr, gotErr, gotErr)
+			}
+			if diff := cmp.Diff(tc.wantResult, gotResult); diff != "" {
+				t.Errorf("result (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
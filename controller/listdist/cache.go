@@ -0,0 +1,114 @@
+package listdist
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/aereal/frontier"
+	"github.com/aereal/frontier/cf"
+)
+
+var ErrUnknownAccount = errors.New("cannot determine the account to key the cache")
+
+type NewCacheOption interface {
+	applyNewCacheOption(c *Cache)
+}
+
+func Clock(now func() time.Time) NewCacheOption { return &optClock{now: now} } //nolint:ireturn
+
+type optClock struct{ now func() time.Time }
+
+var _ NewCacheOption = (*optClock)(nil)
+
+func (o *optClock) applyNewCacheOption(c *Cache) { c.now = o.now }
+
+func NewCache(dir string, identityProvider cf.IdentityProvider, opts ...NewCacheOption) *Cache {
+	c := &Cache{
+		dir:              dir,
+		identityProvider: identityProvider,
+		now:              time.Now,
+	}
+	for _, o := range opts {
+		o.applyNewCacheOption(c)
+	}
+	return c
+}
+
+type Cache struct {
+	identityProvider cf.IdentityProvider
+	now              func() time.Time
+	dir              string
+}
+
+type cacheEntry struct {
+	FetchedAt    time.Time                      `json:"fetchedAt"`
+	Associations []frontier.FunctionAssociation `json:"associations"`
+}
+
+func (c *Cache) load(ctx context.Context, ttl time.Duration) ([]frontier.FunctionAssociation, bool, error) {
+	path, err := c.entryPath(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+	f, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	defer f.Close()
+	var entry cacheEntry
+	if err := json.NewDecoder(f).Decode(&entry); err != nil {
+		return nil, false, fmt.Errorf("json.Decoder.Decode: %w", err)
+	}
+	if c.now().Sub(entry.FetchedAt) >= ttl {
+		return nil, false, nil
+	}
+	return entry.Associations, true, nil
+}
+
+func (c *Cache) store(ctx context.Context, associations []frontier.FunctionAssociation) error {
+	path, err := c.entryPath(ctx)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	entry := cacheEntry{FetchedAt: c.now(), Associations: associations}
+	if err := json.NewEncoder(tmp).Encode(entry); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("json.Encoder.Encode: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+func (c *Cache) entryPath(ctx context.Context) (string, error) {
+	identity, err := c.identityProvider.ProvideIdentity(ctx)
+	if err != nil {
+		return "", err
+	}
+	if identity == nil || identity.AccountID == "" {
+		return "", ErrUnknownAccount
+	}
+	region := identity.Region
+	if region == "" {
+		region = "default"
+	}
+	return filepath.Join(c.dir, fmt.Sprintf("%s_%s.json", identity.AccountID, region)), nil
+}
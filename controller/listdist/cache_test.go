@@ -0,0 +1,125 @@
+package listdist_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aereal/frontier"
+	"github.com/aereal/frontier/cf"
+	"github.com/aereal/frontier/controller/listdist"
+	"github.com/aereal/frontier/internal/cfmock"
+	"github.com/aereal/frontier/internal/testexpectations"
+	"github.com/aws/aws-sdk-go-v2/service/cloudfront"
+	"github.com/aws/aws-sdk-go-v2/service/cloudfront/types"
+	"github.com/google/go-cmp/cmp"
+	"go.uber.org/mock/gomock"
+)
+
+func TestController_ListDistributions_cache(t *testing.T) {
+	type call struct {
+		elapsed  time.Duration
+		opts     []listdist.ListDistributionsOption
+		criteria *listdist.Criteria
+		want     []frontier.FunctionAssociation
+	}
+	ttl := time.Minute
+	testCases := []struct {
+		name       string
+		calls      []call
+		wantFetchs int
+	}{
+		{
+			name: "cache disabled",
+			calls: []call{
+				{criteria: listdist.NewCriteria(), want: allAssociations},
+				{criteria: listdist.NewCriteria(), want: allAssociations},
+			},
+			wantFetchs: 2,
+		},
+		{
+			name: "served from the cache within ttl",
+			calls: []call{
+				{criteria: listdist.NewCriteria(), opts: []listdist.ListDistributionsOption{listdist.CacheTTL(ttl)}, want: allAssociations},
+				{elapsed: time.Second, criteria: listdist.NewCriteria(), opts: []listdist.ListDistributionsOption{listdist.CacheTTL(ttl)}, want: allAssociations},
+			},
+			wantFetchs: 1,
+		},
+		{
+			name: "cached entries are filtered by the criteria",
+			calls: []call{
+				{criteria: listdist.NewCriteria(), opts: []listdist.ListDistributionsOption{listdist.CacheTTL(ttl)}, want: allAssociations},
+				{
+					elapsed:  time.Second,
+					criteria: listdist.NewCriteria(listdist.EqualDistributionDomainName("dist-2.test")),
+					opts:     []listdist.ListDistributionsOption{listdist.CacheTTL(ttl)},
+					want:     []frontier.FunctionAssociation{testexpectations.FunctionAssociatedInCustomCacheBehavior},
+				},
+			},
+			wantFetchs: 1,
+		},
+		{
+			name: "expired",
+			calls: []call{
+				{criteria: listdist.NewCriteria(), opts: []listdist.ListDistributionsOption{listdist.CacheTTL(ttl)}, want: allAssociations},
+				{elapsed: ttl, criteria: listdist.NewCriteria(), opts: []listdist.ListDistributionsOption{listdist.CacheTTL(ttl)}, want: allAssociations},
+			},
+			wantFetchs: 2,
+		},
+		{
+			name: "refresh",
+			calls: []call{
+				{criteria: listdist.NewCriteria(), opts: []listdist.ListDistributionsOption{listdist.CacheTTL(ttl)}, want: allAssociations},
+				{elapsed: time.Second, criteria: listdist.NewCriteria(), opts: []listdist.ListDistributionsOption{listdist.CacheTTL(ttl), listdist.RefreshCache(true)}, want: allAssociations},
+			},
+			wantFetchs: 2,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			if deadline, ok := t.Deadline(); ok {
+				ctx, cancel = context.WithDeadline(ctx, deadline)
+			}
+			defer cancel()
+
+			mockCtrl := gomock.NewController(t)
+			client := cfmock.NewMockCloudFrontClient(mockCtrl)
+			returnDists(distAssociatedInDefaultCacheBehavior, distAssociatedInCustomCacheBehavior)(client).Times(tc.wantFetchs)
+			provider := &cf.StaticCFProvider{
+				Client:   client,
+				Identity: &cf.Identity{AccountID: "123456789012", Region: "us-east-1"},
+			}
+			now := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+			cache := listdist.NewCache(t.TempDir(), provider, listdist.Clock(func() time.Time { return now }))
+			controller := listdist.NewController(provider, listdist.WithCache(cache))
+			for i, c := range tc.calls {
+				now = now.Add(c.elapsed)
+				got, err := collect(controller.ListDistributions(ctx, new(bytes.Buffer), c.criteria, c.opts...))
+				if err != nil {
+					t.Fatalf("#%d: ListDistributions: %+v", i, err)
+				}
+				if diff := cmp.Diff(c.want, got); diff != "" {
+					t.Errorf("#%d: associations (-want, +got):\n%s", i, diff)
+				}
+			}
+		})
+	}
+}
+
+var allAssociations = []frontier.FunctionAssociation{
+	testexpectations.FunctionAssociatedInDefaultCacheBehavior,
+	testexpectations.FunctionAssociatedInCustomCacheBehavior,
+}
+
+func returnDists(dists ...types.DistributionSummary) func(m *cfmock.MockCloudFrontClient) *cfmock.MockCloudFrontClientListDistributionsCall {
+	return func(m *cfmock.MockCloudFrontClient) *cfmock.MockCloudFrontClientListDistributionsCall {
+		out := &cloudfront.ListDistributionsOutput{
+			DistributionList: &types.DistributionList{Items: dists},
+		}
+		return m.EXPECT().
+			ListDistributions(gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(out, nil)
+	}
+}
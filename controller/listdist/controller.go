@@ -4,41 +4,207 @@ import (
 	"context"
 	"io"
 	"iter"
+	"log/slog"
 	"slices"
+	"time"
 
 	"github.com/aereal/frontier"
-	"github.com/aereal/frontier/internal/cf"
+	"github.com/aereal/frontier/cf"
 	"github.com/aws/aws-sdk-go-v2/service/cloudfront"
 	"github.com/aws/aws-sdk-go-v2/service/cloudfront/types"
+	"go.opentelemetry.io/otel/trace"
 )
 
-func NewController(clientProvider cf.Provider) *Controller {
-	return &Controller{
+type NewControllerOption interface {
+	applyNewControllerOption(c *Controller)
+}
+
+func WithCache(cache *Cache) NewControllerOption { return &optWithCache{cache: cache} } //nolint:ireturn
+
+type optWithCache struct{ cache *Cache }
+
+var _ NewControllerOption = (*optWithCache)(nil)
+
+func (o *optWithCache) applyNewControllerOption(c *Controller) { c.cache = o.cache }
+
+func NewController(clientProvider cf.Provider, opts ...NewControllerOption) *Controller {
+	c := &Controller{
 		clientProvider: clientProvider,
 	}
+	for _, o := range opts {
+		o.applyNewControllerOption(c)
+	}
+	return c
 }
 
 type Controller struct {
 	clientProvider cf.Provider
+	cache          *Cache
+}
+
+type ListDistributionsOption interface {
+	applyListDistributionsOption(cfg *configListDistributions)
+}
+
+type configListDistributions struct {
+	cacheTTL     time.Duration
+	limit        int
+	refreshCache bool
+}
+
+func CacheTTL(ttl time.Duration) ListDistributionsOption { return &optCacheTTL{ttl: ttl} } //nolint:ireturn
+
+type optCacheTTL struct{ ttl time.Duration }
+
+var _ ListDistributionsOption = (*optCacheTTL)(nil)
+
+func (o *optCacheTTL) applyListDistributionsOption(cfg *configListDistributions) {
+	cfg.cacheTTL = o.ttl
+}
+
+func RefreshCache(refresh bool) ListDistributionsOption { return &optRefreshCache{refresh: refresh} } //nolint:ireturn
+
+type optRefreshCache struct{ refresh bool }
+
+var _ ListDistributionsOption = (*optRefreshCache)(nil)
+
+func (o *optRefreshCache) applyListDistributionsOption(cfg *configListDistributions) {
+	cfg.refreshCache = o.refresh
+}
+
+func Limit(limit int) ListDistributionsOption { return &optLimit{limit: limit} } //nolint:ireturn
+
+type optLimit struct{ limit int }
+
+var _ ListDistributionsOption = (*optLimit)(nil)
+
+func (o *optLimit) applyListDistributionsOption(cfg *configListDistributions) {
+	cfg.limit = o.limit
+}
+
+func (c *Controller) ListDistributions(ctx context.Context, output io.Writer, criteria *Criteria, opts ...ListDistributionsOption) iter.Seq2[frontier.FunctionAssociation, error] {
+	var cfg configListDistributions
+	for _, o := range opts {
+		o.applyListDistributionsOption(&cfg)
+	}
+	return func(yield func(frontier.FunctionAssociation, error) bool) {
+		ctx, span := startSpan(ctx, "listdist.ListDistributions")
+		var err error
+		defer func() { endSpan(span, err) }()
+		associations := criteria.filtered(c.allAssociations(ctx, cfg))
+		if cfg.limit > 0 {
+			associations = limited(associations, cfg.limit)
+		}
+		for association, itemErr := range associations {
+			if itemErr != nil {
+				err = itemErr
+			}
+			if !yield(association, itemErr) {
+				return
+			}
+		}
+	}
+}
+
+func (c *Controller) allAssociations(ctx context.Context, cfg configListDistributions) iter.Seq2[frontier.FunctionAssociation, error] {
+	useCache := c.cache != nil && cfg.cacheTTL > 0
+	span := trace.SpanFromContext(ctx)
+	span.SetAttributes(attrCacheUsed.Bool(useCache))
+	if !useCache {
+		return c.fetchAssociations(ctx)
+	}
+	if !cfg.refreshCache {
+		cached, ok, err := c.cache.load(ctx, cfg.cacheTTL)
+		if err != nil {
+			slog.WarnContext(ctx, "failed to load cached distributions", slog.String("error", err.Error()))
+		}
+		span.SetAttributes(attrCacheHit.Bool(ok))
+		if ok {
+			return withoutError(slices.Values(cached))
+		}
+	}
+	return func(yield func(frontier.FunctionAssociation, error) bool) {
+		var fetched []frontier.FunctionAssociation
+		for association, err := range c.fetchAssociations(ctx) {
+			if err != nil {
+				yield(association, err)
+				return
+			}
+			fetched = append(fetched, association)
+			if !yield(association, nil) {
+				return
+			}
+		}
+		// store only complete results; the iteration may be stopped by the consumer
+		if err := c.cache.store(ctx, fetched); err != nil {
+			slog.WarnContext(ctx, "failed to store distributions to the cache", slog.String("error", err.Error()))
+		}
+	}
 }
 
-func (c *Controller) ListDistributions(ctx context.Context, output io.Writer, criteria *Criteria) ([]frontier.FunctionAssociation, error) {
-	client, err := c.clientProvider.ProvideCloudFrontClient(ctx)
+func (c *Controller) fetchAssociations(ctx context.Context) iter.Seq2[frontier.FunctionAssociation, error] {
+	return func(yield func(frontier.FunctionAssociation, error) bool) {
+		client, err := c.clientProvider.ProvideCloudFrontClient(ctx)
+		if err != nil {
+			yield(frontier.FunctionAssociation{}, err)
+			return
+		}
+		paginator := cloudfront.NewListDistributionsPaginator(client, &cloudfront.ListDistributionsInput{})
+		for page := 1; paginator.HasMorePages(); page++ {
+			out, err := fetchPage(ctx, paginator, page)
+			if err != nil {
+				yield(frontier.FunctionAssociation{}, err)
+				return
+			}
+			for _, dist := range out.DistributionList.Items {
+				for association := range iterateOverDist(dist) {
+					if !yield(association, nil) {
+						return
+					}
+				}
+			}
+		}
+	}
+}
+
+func fetchPage(ctx context.Context, paginator *cloudfront.ListDistributionsPaginator, page int) (_ *cloudfront.ListDistributionsOutput, err error) {
+	ctx, span := startSpan(ctx, "listdist.FetchPage", attrPage.Int(page))
+	defer func() { endSpan(span, err) }()
+	out, err := paginator.NextPage(ctx)
 	if err != nil {
 		return nil, err
 	}
-	var fnAssociations []frontier.FunctionAssociation
-	paginator := cloudfront.NewListDistributionsPaginator(client, &cloudfront.ListDistributionsInput{})
-	for paginator.HasMorePages() {
-		out, err := paginator.NextPage(ctx)
-		if err != nil {
-			return nil, err
+	recordPageFetched(ctx, operationListDists)
+	span.SetAttributes(attrDistributions.Int(len(out.DistributionList.Items)))
+	return out, nil
+}
+
+func withoutError[T any](xs iter.Seq[T]) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		for x := range xs {
+			if !yield(x, nil) {
+				return
+			}
 		}
-		for _, dist := range out.DistributionList.Items {
-			fnAssociations = slices.AppendSeq(fnAssociations, criteria.filtered(iterateOverDist(dist)))
+	}
+}
+
+func limited[T any](xs iter.Seq2[T, error], limit int) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var n int
+		for x, err := range xs {
+			if !yield(x, err) {
+				return
+			}
+			if err != nil {
+				continue
+			}
+			n++
+			if n >= limit {
+				return
+			}
 		}
 	}
-	return fnAssociations, nil
 }
 
 func convertSDKFunctionAssociations(in types.FunctionAssociation, dist types.DistributionSummary, isDefault bool, cachePolicyID string, targetOriginID *string) frontier.FunctionAssociation {
@@ -4,16 +4,22 @@ import (
 	"bytes"
 	"context"
 	"errors"
+	"iter"
 	"testing"
 
 	"github.com/aereal/frontier"
+	"github.com/aereal/frontier/cf"
 	"github.com/aereal/frontier/controller/listdist"
-	"github.com/aereal/frontier/internal/cf"
 	"github.com/aereal/frontier/internal/cfmock"
 	"github.com/aereal/frontier/internal/testexpectations"
 	"github.com/aws/aws-sdk-go-v2/service/cloudfront"
 	"github.com/aws/aws-sdk-go-v2/service/cloudfront/types"
 	"github.com/google/go-cmp/cmp"
+	"go.opentelemetry.io/otel"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 	"go.uber.org/mock/gomock"
 )
 
@@ -60,7 +66,7 @@ func TestController_ListDistributions(t *testing.T) {
 			}
 			controller := listdist.NewController(&cf.StaticCFProvider{Client: client})
 			buf := new(bytes.Buffer)
-			gotAssociations, gotErr := controller.ListDistributions(ctx, buf, tc.criteria)
+			gotAssociations, gotErr := collect(controller.ListDistributions(ctx, buf, tc.criteria))
 			if !errors.Is(gotErr, tc.wantErr) {
 				t.Errorf("error:\n\twant: %T %s\n\t got: %T %s", tc.wantErr, tc.wantErr, gotErr, gotErr)
 			}
@@ -74,6 +80,106 @@ func TestController_ListDistributions(t *testing.T) {
 	}
 }
 
+func TestController_ListDistributions_limit(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	if deadline, ok := t.Deadline(); ok {
+		ctx, cancel = context.WithDeadline(ctx, deadline)
+	}
+	defer cancel()
+
+	mockCtrl := gomock.NewController(t)
+	client := cfmock.NewMockCloudFrontClient(mockCtrl)
+	firstPage := &cloudfront.ListDistributionsOutput{
+		DistributionList: &types.DistributionList{
+			Items:       []types.DistributionSummary{distAssociatedInDefaultCacheBehavior},
+			IsTruncated: ref(true),
+			NextMarker:  ref("next"),
+		},
+	}
+	client.EXPECT().
+		ListDistributions(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(firstPage, nil).
+		Times(1)
+	controller := listdist.NewController(&cf.StaticCFProvider{Client: client})
+	got, err := collect(controller.ListDistributions(ctx, new(bytes.Buffer), listdist.NewCriteria(), listdist.Limit(1)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []frontier.FunctionAssociation{testexpectations.FunctionAssociatedInDefaultCacheBehavior}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("associations (-want, +got):\n%s", diff)
+	}
+}
+
+func TestController_ListDistributions_telemetry(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	if deadline, ok := t.Deadline(); ok {
+		ctx, cancel = context.WithDeadline(ctx, deadline)
+	}
+	defer cancel()
+
+	spanRecorder := tracetest.NewSpanRecorder()
+	prevTP := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spanRecorder)))
+	defer otel.SetTracerProvider(prevTP)
+	metricReader := sdkmetric.NewManualReader()
+	prevMP := otel.GetMeterProvider()
+	otel.SetMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(metricReader)))
+	defer otel.SetMeterProvider(prevMP)
+
+	mockCtrl := gomock.NewController(t)
+	client := cfmock.NewMockCloudFrontClient(mockCtrl)
+	gomock.InOrder(
+		client.EXPECT().
+			ListDistributions(gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(&cloudfront.ListDistributionsOutput{
+				DistributionList: &types.DistributionList{
+					Items:       []types.DistributionSummary{distAssociatedInDefaultCacheBehavior},
+					IsTruncated: ref(true),
+					NextMarker:  ref("next"),
+				},
+			}, nil),
+		client.EXPECT().
+			ListDistributions(gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(&cloudfront.ListDistributionsOutput{
+				DistributionList: &types.DistributionList{
+					Items: []types.DistributionSummary{distAssociatedInCustomCacheBehavior},
+				},
+			}, nil),
+	)
+	controller := listdist.NewController(&cf.StaticCFProvider{Client: client})
+	if _, err := collect(controller.ListDistributions(ctx, new(bytes.Buffer), listdist.NewCriteria())); err != nil {
+		t.Fatal(err)
+	}
+
+	var gotSpans []string
+	for _, span := range spanRecorder.Ended() {
+		gotSpans = append(gotSpans, span.Name())
+	}
+	wantSpans := []string{"listdist.FetchPage", "listdist.FetchPage", "listdist.ListDistributions"}
+	if diff := cmp.Diff(wantSpans, gotSpans); diff != "" {
+		t.Errorf("spans (-want, +got):\n%s", diff)
+	}
+
+	var rm metricdata.ResourceMetrics
+	if err := metricReader.Collect(ctx, &rm); err != nil {
+		t.Fatal(err)
+	}
+	var pages int64
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if sum, ok := m.Data.(metricdata.Sum[int64]); ok && m.Name == "frontier.cloudfront.pages_fetched" {
+				for _, dp := range sum.DataPoints {
+					pages += dp.Value
+				}
+			}
+		}
+	}
+	if pages != 2 {
+		t.Errorf("pages fetched: want 2, got %d", pages)
+	}
+}
+
 func returnApiError() func(m *cfmock.MockCloudFrontClient) {
 	return func(m *cfmock.MockCloudFrontClient) {
 		m.EXPECT().
@@ -148,6 +254,17 @@ var (
 
 func ref[T any](v T) *T { return &v }
 
+func collect[T any](xs iter.Seq2[T, error]) ([]T, error) {
+	var ret []T
+	for x, err := range xs {
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, x)
+	}
+	return ret, nil
+}
+
 type apiError struct{}
 
 func (apiError) Error() string { return "api error" }
@@ -156,3 +273,47 @@ func (apiError) Is(other error) bool {
 	var x apiError
 	return errors.As(other, &x)
 }
+
+func TestController_ListDistributions_replay(t *testing.T) {
+	testCases := []struct {
+		name             string
+		cassettePath     string
+		criteria         *listdist.Criteria
+		wantAssociations []frontier.FunctionAssociation
+	}{
+		{
+			name:             "all",
+			cassettePath:     "../../testdata/cassettes/list_distributions.json",
+			criteria:         listdist.NewCriteria(),
+			wantAssociations: allAssociations,
+		},
+		{
+			name:             "filtered",
+			cassettePath:     "../../testdata/cassettes/list_distributions.json",
+			criteria:         listdist.NewCriteria(listdist.EqualDistributionDomainName("dist-1.test")),
+			wantAssociations: []frontier.FunctionAssociation{testexpectations.FunctionAssociatedInDefaultCacheBehavior},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			if deadline, ok := t.Deadline(); ok {
+				ctx, cancel = context.WithDeadline(ctx, deadline)
+			}
+			defer cancel()
+
+			cassette, err := cf.LoadCassette(tc.cassettePath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			controller := listdist.NewController(cf.NewReplayProvider(cassette))
+			got, err := collect(controller.ListDistributions(ctx, new(bytes.Buffer), tc.criteria))
+			if err != nil {
+				t.Fatalf("ListDistributions: %+v", err)
+			}
+			if diff := cmp.Diff(tc.wantAssociations, got); diff != "" {
+				t.Errorf("associations (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -133,13 +133,13 @@ func (criteria *Criteria) unsafeAdd(criterion Criterion) {
 	criteria.dirty[criterion.Key()] = criterion
 }
 
-func (criteria *Criteria) filtered(associations iter.Seq[frontier.FunctionAssociation]) iter.Seq[frontier.FunctionAssociation] {
-	return func(yield func(frontier.FunctionAssociation) bool) {
-		for association := range associations {
-			if !criteria.Satisfy(association) {
+func (criteria *Criteria) filtered(associations iter.Seq2[frontier.FunctionAssociation, error]) iter.Seq2[frontier.FunctionAssociation, error] {
+	return func(yield func(frontier.FunctionAssociation, error) bool) {
+		for association, err := range associations {
+			if err == nil && !criteria.Satisfy(association) {
 				continue
 			}
-			if !yield(association) {
+			if !yield(association, err) {
 				return
 			}
 		}
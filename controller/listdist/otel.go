@@ -0,0 +1,45 @@
+package listdist
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "github.com/aereal/frontier/controller/listdist"
+
+const (
+	attrCacheUsed      = attribute.Key("frontier.listdist.cache.used")
+	attrCacheHit       = attribute.Key("frontier.listdist.cache.hit")
+	attrPage           = attribute.Key("frontier.listdist.page")
+	attrDistributions  = attribute.Key("frontier.listdist.distributions")
+	attrOperation      = attribute.Key("frontier.operation")
+	operationListDists = "ListDistributions"
+)
+
+func startSpan(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) { //nolint:ireturn
+	return otel.GetTracerProvider().Tracer(instrumentationName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+func recordPageFetched(ctx context.Context, operation string) {
+	c, err := otel.GetMeterProvider().Meter(instrumentationName).Int64Counter("frontier.cloudfront.pages_fetched",
+		metric.WithUnit("{page}"),
+		metric.WithDescription("number of the pages fetched from CloudFront list APIs"))
+	if err != nil {
+		otel.Handle(err)
+		return
+	}
+	c.Add(ctx, 1, metric.WithAttributes(attrOperation.String(operation)))
+}
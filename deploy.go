@@ -3,67 +3,444 @@ package frontier
 import (
 	"context"
 	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
 
-	"github.com/aereal/frontier/internal/cf"
+	"github.com/aereal/frontier/cf"
+	"github.com/aereal/frontier/lock"
+	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/cloudfront"
 	"github.com/aws/aws-sdk-go-v2/service/cloudfront/types"
 )
 
+type ConflictError struct {
+	FunctionName   string
+	ExpectedSHA256 string
+	ActualSHA256   string
+}
+
+func (e *ConflictError) Error() string {
+	return fmt.Sprintf("function %s has been modified by someone else: expected code sha256 is %s but actual is %s", e.FunctionName, e.ExpectedSHA256, e.ActualSHA256)
+}
+
+func (e *ConflictError) Is(other error) bool {
+	otherErr := new(ConflictError)
+	if !errors.As(other, &otherErr) {
+		return false
+	}
+	return *otherErr == *e
+}
+
+type NewDeployerOption interface {
+	applyNewDeployerOption(d *Deployer)
+}
+
+func WithLocker(locker lock.Locker) NewDeployerOption { return &optWithLocker{locker: locker} } //nolint:ireturn
+
+type optWithLocker struct{ locker lock.Locker }
+
+var _ NewDeployerOption = (*optWithLocker)(nil)
+
+func (o *optWithLocker) applyNewDeployerOption(d *Deployer) { d.locker = o.locker }
+
+func LockTTL(ttl time.Duration) NewDeployerOption { return &optLockTTL{ttl: ttl} } //nolint:ireturn
+
+type optLockTTL struct{ ttl time.Duration }
+
+var _ NewDeployerOption = (*optLockTTL)(nil)
+
+func (o *optLockTTL) applyNewDeployerOption(d *Deployer) { d.lockTTL = o.ttl }
+
+type DeployOption interface {
+	applyDeployOption(cfg *configDeploy)
+}
+
+type configDeploy struct {
+	lockWait time.Duration
+}
+
+func LockWait(wait time.Duration) DeployOption { return &optLockWait{wait: wait} } //nolint:ireturn
+
+type optLockWait struct{ wait time.Duration }
+
+var _ DeployOption = (*optLockWait)(nil)
+
+func (o *optLockWait) applyDeployOption(cfg *configDeploy) { cfg.lockWait = o.wait }
+
+type DeployAction string
+
+const (
+	DeployActionCreated DeployAction = "created"
+	DeployActionUpdated DeployAction = "updated"
+	// DeployActionUnchanged means the function already has the code and the config, so nothing is updated nor published.
+	DeployActionUnchanged DeployAction = "unchanged"
+)
+
+type DeployResult struct {
+	FunctionName string
+	FunctionARN  string
+	Action       DeployAction
+	ETagBefore   string
+	ETagAfter    string
+	CodeSHA256   string
+	Published    bool
+	Stage        types.FunctionStage
+	Duration     time.Duration
+}
+
+var ErrLockerNotConfigured = errors.New("deployment lock is not configured")
+
 type Deployer struct {
 	clientProvider cf.Provider
+	locker         lock.Locker
+	retrier        retrier
+	lockTTL        time.Duration
 }
 
-func NewDeployer(clientProvider cf.Provider) *Deployer {
-	d := &Deployer{clientProvider: clientProvider}
+func NewDeployer(clientProvider cf.Provider, opts ...NewDeployerOption) *Deployer {
+	d := &Deployer{
+		clientProvider: clientProvider,
+		retrier:        defaultRetrier(),
+		lockTTL:        10 * time.Minute,
+	}
+	for _, o := range opts {
+		o.applyNewDeployerOption(d)
+	}
 	return d
 }
 
-func (d *Deployer) Deploy(ctx context.Context, configPath string, publish bool) error {
-	fn, err := ParseConfigFromPath(configPath)
+func (d *Deployer) Deploy(ctx context.Context, configPath string, publish bool, opts ...DeployOption) (_ *DeployResult, err error) {
+	startedAt := time.Now()
+	ctx, span := startSpan(ctx, "frontier.Deploy", attrConfigPath.String(configPath), attrDeployPublish.Bool(publish))
+	defer func() { endSpan(span, err) }()
+	var cfg configDeploy
+	for _, o := range opts {
+		o.applyDeployOption(&cfg)
+	}
+	fn, err := parseConfig(ctx, configPath)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	span.SetAttributes(attrFunctionName.String(fn.Name))
+	release, err := d.lock(ctx, fn.Name, cfg.lockWait)
+	if err != nil {
+		return nil, err
 	}
+	defer release()
+	hc := &hookContext{configPath: configPath, result: &DeployResult{FunctionName: fn.Name}}
+	result, err := d.deploy(ctx, fn, publish, hc)
+	recordDeployDuration(ctx, time.Since(startedAt).Seconds(),
+		attrFunctionName.String(fn.Name), attrDeployAction.String(string(hc.result.Action)), attrDeployPublish.Bool(publish), outcomeOf(err))
+	if hookErr := new(HookError); errors.As(err, &hookErr) && hookErr.Phase == HookPhasePostDeploy {
+		// the function is already deployed, so the failure of postDeploy cannot abort it and onFailure does not run
+		result.Duration = time.Since(startedAt)
+		return result, err
+	}
+	if err != nil {
+		hc.deployErr = err
+		if hookErr := runHooks(ctx, fn.Hooks, HookPhaseOnFailure, hc); hookErr != nil {
+			slog.WarnContext(ctx, "failed to run onFailure hooks", slog.String("function", fn.Name), slog.String("error", hookErr.Error()))
+		}
+		return nil, err
+	}
+	result.Duration = time.Since(startedAt)
+	return result, nil
+}
+
+func (d *Deployer) deploy(ctx context.Context, fn *Function, publish bool, hc *hookContext) (*DeployResult, error) {
+	if err := runHooks(ctx, fn.Hooks, HookPhasePreDeploy, hc); err != nil {
+		return nil, err
+	}
+	if err := fn.validateLimits(); err != nil {
+		return nil, err
+	}
+	body, err := readCode(ctx, fn)
+	if err != nil {
+		return nil, err
+	}
+	recordCodeSize(ctx, len(body), attrFunctionName.String(fn.Name), attrOperation.String("deploy"))
+	if err := fn.checkCodeSize(len(body)); err != nil {
+		return nil, err
+	}
+	// the compute utilization is measured on the DEVELOPMENT stage, so that the exceeding code is not published
+	testsBeforePublish := fn.maxComputeUtilization() > 0
+	if err := d.upsert(ctx, fn, body, publish && !testsBeforePublish, hc.result); err != nil {
+		return nil, err
+	}
+	if testsBeforePublish && hc.result.Action == DeployActionUnchanged && publish {
+		// upsert has compared only the DEVELOPMENT stage because it does not publish
+		if err := d.checkLiveUnchanged(ctx, fn, hc.result); err != nil {
+			return nil, err
+		}
+	}
+	if testsBeforePublish && hc.result.Action != DeployActionUnchanged {
+		if err := d.checkComputeUtilization(ctx, fn, publish, hc.result); err != nil {
+			return nil, err
+		}
+	}
+	if err := runHooks(ctx, fn.Hooks, HookPhasePostDeploy, hc); err != nil {
+		return hc.result, err
+	}
+	return hc.result, nil
+}
+
+// lock acquires the deployment lock of the function and returns the function to release it.
+func (d *Deployer) lock(ctx context.Context, name string, wait time.Duration) (func(), error) {
+	if d.locker == nil {
+		return func() {}, nil
+	}
+	lease, err := lock.Wait(ctx, d.locker, name, d.lockTTL, wait, time.Second)
+	if err != nil {
+		return nil, err
+	}
+	return func() {
+		if err := d.locker.Unlock(context.WithoutCancel(ctx), lease); err != nil {
+			slog.WarnContext(ctx, "failed to release the deployment lock", slog.String("function", name), slog.String("error", err.Error()))
+		}
+	}, nil
+}
+
+// upsert creates or updates the function with the code, and publishes it if publish is true.
+func (d *Deployer) upsert(ctx context.Context, fn *Function, body []byte, publish bool, result *DeployResult) error {
+	bodySHA256 := codeSHA256(body)
+	result.CodeSHA256 = bodySHA256
+	result.Stage = types.FunctionStageDevelopment
 
 	client, err := d.clientProvider.ProvideCloudFrontClient(ctx)
 	if err != nil {
 		return err
 	}
-	var etag *string
-	existing, err := client.GetFunction(ctx, &cloudfront.GetFunctionInput{Name: &fn.Name})
+	existing, err := d.decideAction(ctx, client, fn.Name, result)
 	if err != nil {
-		var notFoundErr *types.NoSuchFunctionExists
-		if !errors.As(err, &notFoundErr) {
-			return err
-		}
-		input, err := fn.toCreateInput()
+		return err
+	}
+	if result.Action == DeployActionUpdated && codeSHA256(existing.FunctionCode) == bodySHA256 {
+		devUnchanged, err := d.checkUnchanged(ctx, client, fn, existing, publish, result)
 		if err != nil {
 			return err
 		}
-		out, err := client.CreateFunction(ctx, input)
+		if result.Action == DeployActionUnchanged {
+			return nil
+		}
+		if devUnchanged {
+			// only the LIVE stage is behind, so the DEVELOPMENT stage is published as it is
+			etag, err := d.publish(ctx, client, fn.Name, existing.ETag, bodySHA256, result)
+			if err != nil {
+				return err
+			}
+			result.ETagAfter = aws.ToString(etag)
+			return nil
+		}
+	}
+	var etag *string
+	if result.Action == DeployActionCreated {
+		input := fn.toCreateInput(body)
+		err := d.retrier.do(ctx, func() error {
+			out, err := client.CreateFunction(ctx, input)
+			if err != nil {
+				return err
+			}
+			etag = out.ETag
+			result.FunctionARN = functionARNOf(out.FunctionSummary)
+			return nil
+		})
 		if err != nil {
 			return err
 		}
-		etag = out.ETag
 	} else {
-		input, err := fn.toUpdateInput(existing.ETag)
-		if err != nil {
+		result.ETagBefore = aws.ToString(existing.ETag)
+		baseSHA256 := codeSHA256(existing.FunctionCode)
+		etag = existing.ETag
+		err := d.retrier.do(ctx, func() error {
+			out, err := client.UpdateFunction(ctx, fn.toUpdateInput(body, etag))
+			if err == nil {
+				etag = out.ETag
+				result.FunctionARN = functionARNOf(out.FunctionSummary)
+				return nil
+			}
+			if !isETagMismatch(err) {
+				return err
+			}
+			current, getErr := client.GetFunction(ctx, &cloudfront.GetFunctionInput{Name: &fn.Name})
+			if getErr != nil {
+				return getErr
+			}
+			// another deployment of the same code is not a conflict
+			if currentSHA256 := codeSHA256(current.FunctionCode); currentSHA256 != baseSHA256 && currentSHA256 != bodySHA256 {
+				return &ConflictError{FunctionName: fn.Name, ExpectedSHA256: baseSHA256, ActualSHA256: currentSHA256}
+			}
+			etag = current.ETag
 			return err
-		}
-		out, err := client.UpdateFunction(ctx, input)
+		})
 		if err != nil {
 			return err
 		}
-		etag = out.ETag
 	}
 
 	if publish && etag != nil {
+		if etag, err = d.publish(ctx, client, fn.Name, etag, bodySHA256, result); err != nil {
+			return err
+		}
+	}
+	result.ETagAfter = aws.ToString(etag)
+	return nil
+}
+
+// checkUnchanged tells whether the DEVELOPMENT stage has the same config as the function; the caller has compared the code.
+// The action of the result is set to DeployActionUnchanged unless publish is true and the LIVE stage differs.
+func (d *Deployer) checkUnchanged(ctx context.Context, client cf.CloudFrontClient, fn *Function, existing *cloudfront.GetFunctionOutput, publish bool, result *DeployResult) (bool, error) {
+	var describeOut *cloudfront.DescribeFunctionOutput
+	err := d.retrier.do(ctx, func() error {
+		var err error
+		describeOut, err = client.DescribeFunction(ctx, &cloudfront.DescribeFunctionInput{Name: &fn.Name, Stage: types.FunctionStageDevelopment})
+		return err
+	})
+	if err != nil {
+		return false, err
+	}
+	dev := (&remoteFunction{Code: existing.FunctionCode, ETag: existing.ETag, Summary: describeOut.FunctionSummary}).state()
+	if !fn.localState(result.CodeSHA256).sameAs(dev) {
+		return false, nil
+	}
+	result.Action = DeployActionUnchanged
+	result.FunctionARN = functionARNOf(describeOut.FunctionSummary)
+	result.ETagBefore = dev.ETag
+	result.ETagAfter = dev.ETag
+	if !publish {
+		return true, nil
+	}
+	return true, d.checkLiveUnchanged(ctx, fn, result)
+}
+
+// checkLiveUnchanged reverts the action of the result to DeployActionUpdated if the LIVE stage differs from the function,
+// so that the unchanged DEVELOPMENT stage is published.
+func (d *Deployer) checkLiveUnchanged(ctx context.Context, fn *Function, result *DeployResult) error {
+	client, err := d.clientProvider.ProvideCloudFrontClient(ctx)
+	if err != nil {
+		return err
+	}
+	live, err := fetchFunctionState(ctx, client, fn.Name, types.FunctionStageLive)
+	if err != nil {
+		return err
+	}
+	if !fn.localState(result.CodeSHA256).sameAs(live) {
+		result.Action = DeployActionUpdated
+		return nil
+	}
+	result.Stage = types.FunctionStageLive
+	return nil
+}
+
+// checkComputeUtilization tests the DEVELOPMENT stage with the test events of the function, and publishes it if publish is true and the limit is not exceeded.
+func (d *Deployer) checkComputeUtilization(ctx context.Context, fn *Function, publish bool, result *DeployResult) error {
+	client, err := d.clientProvider.ProvideCloudFrontClient(ctx)
+	if err != nil {
+		return err
+	}
+	tests, err := runFunctionTests(ctx, client, fn.Name, result.ETagAfter, types.FunctionStageDevelopment, fn.TestEvents)
+	if err != nil {
+		return err
+	}
+	if err := fn.checkComputeUtilization(tests); err != nil {
+		return err
+	}
+	if !publish {
+		return nil
+	}
+	etag, err := d.publish(ctx, client, fn.Name, aws.String(result.ETagAfter), result.CodeSHA256, result)
+	if err != nil {
+		return err
+	}
+	result.ETagAfter = aws.ToString(etag)
+	return nil
+}
+
+func readCode(ctx context.Context, fn *Function) (_ []byte, err error) {
+	// the callers such as stats do not validate the whole config, so code.path is checked before the span refers to it
+	if fn.Code == nil || fn.Code.Path == "" {
+		return nil, &InvalidConfigError{Field: "code.path", Reason: "is required"}
+	}
+	ctx, span := startSpan(ctx, "frontier.ReadCode", attrFunctionName.String(fn.Name), attrCodePath.String(fn.Code.Path))
+	defer func() { endSpan(span, err) }()
+	body, err := os.ReadFile(fn.Code.Path)
+	if err != nil {
+		return nil, err
+	}
+	span.SetAttributes(attrCodeSize.Int(len(body)), attrCodeSHA256.String(codeSHA256(body)))
+	return body, nil
+}
+
+// decideAction sets the action to the result and returns the existing function if it is updated.
+func (d *Deployer) decideAction(ctx context.Context, client cf.CloudFrontClient, name string, result *DeployResult) (_ *cloudfront.GetFunctionOutput, err error) {
+	ctx, span := startSpan(ctx, "frontier.DecideDeployAction", attrFunctionName.String(name))
+	defer func() { endSpan(span, err) }()
+	var existing *cloudfront.GetFunctionOutput
+	err = d.retrier.do(ctx, func() error {
+		existing, err = client.GetFunction(ctx, &cloudfront.GetFunctionInput{Name: &name})
+		return err
+	})
+	var notFoundErr *types.NoSuchFunctionExists
+	switch {
+	case errors.As(err, &notFoundErr):
+		result.Action = DeployActionCreated
+	case err != nil:
+		return nil, err
+	default:
+		result.Action = DeployActionUpdated
+		span.SetAttributes(attrETag.String(aws.ToString(existing.ETag)))
+	}
+	span.SetAttributes(attrDeployAction.String(string(result.Action)))
+	return existing, nil
+}
+
+func (d *Deployer) publish(ctx context.Context, client cf.CloudFrontClient, name string, etag *string, bodySHA256 string, result *DeployResult) (_ *string, err error) {
+	ctx, span := startSpan(ctx, "frontier.Publish", attrFunctionName.String(name), attrETag.String(aws.ToString(etag)))
+	defer func() { endSpan(span, err) }()
+	err = d.retrier.do(ctx, func() error {
 		input := &cloudfront.PublishFunctionInput{
-			Name:    &fn.Name,
+			Name:    &name,
 			IfMatch: etag,
 		}
-		if _, err := client.PublishFunction(ctx, input); err != nil {
+		out, err := client.PublishFunction(ctx, input)
+		if err == nil {
+			if arn := functionARNOf(out.FunctionSummary); arn != "" {
+				result.FunctionARN = arn
+			}
+			return nil
+		}
+		if !isETagMismatch(err) {
 			return err
 		}
+		current, getErr := client.GetFunction(ctx, &cloudfront.GetFunctionInput{Name: &name})
+		if getErr != nil {
+			return getErr
+		}
+		if currentSHA256 := codeSHA256(current.FunctionCode); currentSHA256 != bodySHA256 {
+			return &ConflictError{FunctionName: name, ExpectedSHA256: bodySHA256, ActualSHA256: currentSHA256}
+		}
+		etag = current.ETag
+		return err
+	})
+	if err != nil {
+		return nil, err
 	}
-	return nil
+	result.Published = true
+	result.Stage = types.FunctionStageLive
+	return etag, nil
+}
+
+func functionARNOf(summary *types.FunctionSummary) string {
+	if summary == nil || summary.FunctionMetadata == nil {
+		return ""
+	}
+	return aws.ToString(summary.FunctionMetadata.FunctionARN)
+}
+
+func (d *Deployer) Unlock(ctx context.Context, functionName string) error {
+	if d.locker == nil {
+		return ErrLockerNotConfigured
+	}
+	return d.locker.ForceUnlock(ctx, functionName)
 }
@@ -3,16 +3,29 @@ package frontier_test
 import (
 	"context"
 	_ "embed"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/aereal/frontier"
-	"github.com/aereal/frontier/internal/cf"
+	"github.com/aereal/frontier/cf"
+	"github.com/aereal/frontier/internal/cfemu"
 	"github.com/aereal/frontier/internal/cfmock"
+	"github.com/aereal/frontier/lock"
 	"github.com/aws/aws-sdk-go-v2/service/cloudfront"
 	"github.com/aws/aws-sdk-go-v2/service/cloudfront/types"
+	"github.com/aws/smithy-go"
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 	"go.uber.org/mock/gomock"
 )
 
@@ -74,6 +87,7 @@ func TestDeployer_ok_existing(t *testing.T) {
 						Runtime: types.FunctionRuntimeCloudfrontJs10,
 					},
 					FunctionMetadata: &types.FunctionMetadata{
+						FunctionARN:      ref("arn:aws:cloudfront::123456789012:function/test-func"),
 						CreatedTime:      ref(now),
 						LastModifiedTime: ref(now),
 						Stage:            types.FunctionStageLive,
@@ -84,8 +98,22 @@ func TestDeployer_ok_existing(t *testing.T) {
 		Times(1)
 
 	deployer := frontier.NewDeployer(&cf.StaticCFProvider{Client: client})
-	if err := deployer.Deploy(ctx, "./testdata/config.yml", true); err != nil {
-		t.Errorf("deployer.Deploy: %+v", err)
+	got, err := deployer.Deploy(ctx, "./testdata/config.yml", true)
+	if err != nil {
+		t.Fatalf("deployer.Deploy: %+v", err)
+	}
+	want := &frontier.DeployResult{
+		FunctionName: "test-func",
+		FunctionARN:  "arn:aws:cloudfront::123456789012:function/test-func",
+		Action:       frontier.DeployActionUpdated,
+		ETagBefore:   "0xdeadbeaf",
+		ETagAfter:    "updated-0xdeadbeaf",
+		CodeSHA256:   sha256Hex(functionCode),
+		Published:    true,
+		Stage:        types.FunctionStageLive,
+	}
+	if diff := cmp.Diff(want, got, cmpopts.IgnoreFields(frontier.DeployResult{}, "Duration")); diff != "" {
+		t.Errorf("DeployResult (-want, +got):\n%s", diff)
 	}
 }
 
@@ -134,6 +162,7 @@ func TestDeployer_ok_create(t *testing.T) {
 						Runtime: types.FunctionRuntimeCloudfrontJs10,
 					},
 					FunctionMetadata: &types.FunctionMetadata{
+						FunctionARN:      ref("arn:aws:cloudfront::123456789012:function/test-func"),
 						CreatedTime:      ref(now),
 						LastModifiedTime: ref(now),
 						Stage:            types.FunctionStageLive,
@@ -144,9 +173,472 @@ func TestDeployer_ok_create(t *testing.T) {
 		Times(1)
 
 	deployer := frontier.NewDeployer(&cf.StaticCFProvider{Client: client})
-	if err := deployer.Deploy(ctx, "./testdata/config.yml", true); err != nil {
-		t.Errorf("deployer.Deploy: %+v", err)
+	got, err := deployer.Deploy(ctx, "./testdata/config.yml", true)
+	if err != nil {
+		t.Fatalf("deployer.Deploy: %+v", err)
+	}
+	want := &frontier.DeployResult{
+		FunctionName: "test-func",
+		FunctionARN:  "arn:aws:cloudfront::123456789012:function/test-func",
+		Action:       frontier.DeployActionCreated,
+		ETagBefore:   "",
+		ETagAfter:    "created-0xdeadbeaf",
+		CodeSHA256:   sha256Hex(functionCode),
+		Published:    true,
+		Stage:        types.FunctionStageLive,
+	}
+	if diff := cmp.Diff(want, got, cmpopts.IgnoreFields(frontier.DeployResult{}, "Duration")); diff != "" {
+		t.Errorf("DeployResult (-want, +got):\n%s", diff)
+	}
+}
+
+func TestDeployer_retry(t *testing.T) {
+	otherCode := []byte("function handler(event) { return event.request; }\n")
+	testCases := []struct {
+		name    string
+		mock    func(c *cfmock.MockCloudFrontClient)
+		wantErr error
+	}{
+		{
+			name: "update retried with the new ETag",
+			mock: func(c *cfmock.MockCloudFrontClient) {
+				gomock.InOrder(
+					c.EXPECT().GetFunction(gomock.Any(), gomock.Any()).
+						Return(&cloudfront.GetFunctionOutput{ETag: ref("etag-1"), FunctionCode: otherCode}, nil),
+					c.EXPECT().UpdateFunction(gomock.Any(), updateInputWithETag("etag-1")).
+						Return(nil, &types.PreconditionFailed{Message: ref("precondition failed")}),
+					c.EXPECT().GetFunction(gomock.Any(), gomock.Any()).
+						Return(&cloudfront.GetFunctionOutput{ETag: ref("etag-2"), FunctionCode: otherCode}, nil),
+					c.EXPECT().UpdateFunction(gomock.Any(), updateInputWithETag("etag-2")).
+						Return(&cloudfront.UpdateFunctionOutput{ETag: ref("etag-3")}, nil),
+					c.EXPECT().PublishFunction(gomock.Any(), &cloudfront.PublishFunctionInput{Name: ref("test-func"), IfMatch: ref("etag-3")}).
+						Return(&cloudfront.PublishFunctionOutput{}, nil),
+				)
+			},
+		},
+		{
+			name: "update conflicted",
+			mock: func(c *cfmock.MockCloudFrontClient) {
+				gomock.InOrder(
+					c.EXPECT().GetFunction(gomock.Any(), gomock.Any()).
+						Return(&cloudfront.GetFunctionOutput{ETag: ref("etag-1")}, nil),
+					c.EXPECT().UpdateFunction(gomock.Any(), updateInputWithETag("etag-1")).
+						Return(nil, &types.InvalidIfMatchVersion{Message: ref("invalid if-match")}),
+					c.EXPECT().GetFunction(gomock.Any(), gomock.Any()).
+						Return(&cloudfront.GetFunctionOutput{ETag: ref("etag-2"), FunctionCode: otherCode}, nil),
+				)
+			},
+			wantErr: &frontier.ConflictError{
+				FunctionName:   "test-func",
+				ExpectedSHA256: sha256Hex(nil),
+				ActualSHA256:   sha256Hex(otherCode),
+			},
+		},
+		{
+			name: "publish conflicted",
+			mock: func(c *cfmock.MockCloudFrontClient) {
+				gomock.InOrder(
+					c.EXPECT().GetFunction(gomock.Any(), gomock.Any()).
+						Return(&cloudfront.GetFunctionOutput{ETag: ref("etag-1")}, nil),
+					c.EXPECT().UpdateFunction(gomock.Any(), updateInputWithETag("etag-1")).
+						Return(&cloudfront.UpdateFunctionOutput{ETag: ref("etag-2")}, nil),
+					c.EXPECT().PublishFunction(gomock.Any(), gomock.Any()).
+						Return(nil, &types.PreconditionFailed{Message: ref("precondition failed")}),
+					c.EXPECT().GetFunction(gomock.Any(), gomock.Any()).
+						Return(&cloudfront.GetFunctionOutput{ETag: ref("etag-3"), FunctionCode: otherCode}, nil),
+				)
+			},
+			wantErr: &frontier.ConflictError{
+				FunctionName:   "test-func",
+				ExpectedSHA256: sha256Hex(functionCode),
+				ActualSHA256:   sha256Hex(otherCode),
+			},
+		},
+		{
+			name: "throttled",
+			mock: func(c *cfmock.MockCloudFrontClient) {
+				gomock.InOrder(
+					c.EXPECT().GetFunction(gomock.Any(), gomock.Any()).
+						Return(nil, errThrottled),
+					c.EXPECT().GetFunction(gomock.Any(), gomock.Any()).
+						Return(&cloudfront.GetFunctionOutput{ETag: ref("etag-1")}, nil),
+					c.EXPECT().UpdateFunction(gomock.Any(), updateInputWithETag("etag-1")).
+						Return(&cloudfront.UpdateFunctionOutput{ETag: ref("etag-2")}, nil),
+					c.EXPECT().PublishFunction(gomock.Any(), gomock.Any()).
+						Return(nil, errThrottled),
+					c.EXPECT().PublishFunction(gomock.Any(), gomock.Any()).
+						Return(&cloudfront.PublishFunctionOutput{}, nil),
+				)
+			},
+		},
+		{
+			name: "gave up retrying",
+			mock: func(c *cfmock.MockCloudFrontClient) {
+				c.EXPECT().GetFunction(gomock.Any(), gomock.Any()).
+					Return(nil, errThrottled).
+					Times(3)
+			},
+			wantErr: errThrottled,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			if deadline, ok := t.Deadline(); ok {
+				ctx, cancel = context.WithDeadline(ctx, deadline)
+			}
+			defer cancel()
+
+			ctrl := gomock.NewController(t)
+			client := cfmock.NewMockCloudFrontClient(ctrl)
+			tc.mock(client)
+			deployer := frontier.NewDeployer(&cf.StaticCFProvider{Client: client}, frontier.RetryMaxAttempts(3), frontier.RetryBackoff(0, 0))
+			_, gotErr := deployer.Deploy(ctx, "./testdata/config.yml", true)
+			if diff := cmp.Diff(tc.wantErr, gotErr, cmpopts.EquateErrors()); diff != "" {
+				t.Errorf("error (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+var errThrottled = &smithy.GenericAPIError{Code: "Throttling", Message: "Rate exceeded"}
+
+func updateInputWithETag(etag string) *cloudfront.UpdateFunctionInput {
+	return &cloudfront.UpdateFunctionInput{
+		Name:         ref("test-func"),
+		FunctionCode: functionCode,
+		IfMatch:      &etag,
+		FunctionConfig: &types.FunctionConfig{
+			Comment: ref("blah blah"),
+			Runtime: types.FunctionRuntimeCloudfrontJs10,
+		},
+	}
+}
+
+func TestDeployer_locked(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	if deadline, ok := t.Deadline(); ok {
+		ctx, cancel = context.WithDeadline(ctx, deadline)
+	}
+	defer cancel()
+
+	dir := t.TempDir()
+	if _, err := lock.NewFileLocker(dir, lock.Owner("other")).TryLock(ctx, "test-func", time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	ctrl := gomock.NewController(t)
+	client := cfmock.NewMockCloudFrontClient(ctrl)
+	deployer := frontier.NewDeployer(&cf.StaticCFProvider{Client: client}, frontier.WithLocker(lock.NewFileLocker(dir)))
+	_, err := deployer.Deploy(ctx, "./testdata/config.yml", true)
+	var heldErr *lock.HeldError
+	if !errors.As(err, &heldErr) {
+		t.Fatalf("want HeldError, got %T %v", err, err)
+	}
+	if heldErr.Lease.Owner != "other" {
+		t.Errorf("owner: want other, got %s", heldErr.Lease.Owner)
+	}
+
+	if err := deployer.Unlock(ctx, "test-func"); err != nil {
+		t.Fatalf("Unlock: %+v", err)
+	}
+	client.EXPECT().GetFunction(gomock.Any(), gomock.Any()).Return(&cloudfront.GetFunctionOutput{ETag: ref("etag-1")}, nil).Times(1)
+	client.EXPECT().UpdateFunction(gomock.Any(), gomock.Any()).Return(&cloudfront.UpdateFunctionOutput{ETag: ref("etag-2")}, nil).Times(1)
+	if _, err := deployer.Deploy(ctx, "./testdata/config.yml", false); err != nil {
+		t.Fatalf("Deploy: %+v", err)
+	}
+	if err := deployer.Unlock(ctx, "test-func"); !errors.Is(err, lock.ErrNotLocked) {
+		t.Errorf("lock must be released after the deployment: %v", err)
+	}
+}
+
+func TestDeployer_telemetry(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	if deadline, ok := t.Deadline(); ok {
+		ctx, cancel = context.WithDeadline(ctx, deadline)
+	}
+	defer cancel()
+
+	spanRecorder := tracetest.NewSpanRecorder()
+	prevTP := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spanRecorder)))
+	defer otel.SetTracerProvider(prevTP)
+	metricReader := sdkmetric.NewManualReader()
+	prevMP := otel.GetMeterProvider()
+	otel.SetMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(metricReader)))
+	defer otel.SetMeterProvider(prevMP)
+
+	ctrl := gomock.NewController(t)
+	client := cfmock.NewMockCloudFrontClient(ctrl)
+	gomock.InOrder(
+		client.EXPECT().GetFunction(gomock.Any(), gomock.Any()).
+			Return(nil, &types.NoSuchFunctionExists{Message: ref("not found")}),
+		client.EXPECT().CreateFunction(gomock.Any(), gomock.Any()).
+			Return(&cloudfront.CreateFunctionOutput{ETag: ref("etag-1")}, nil),
+		client.EXPECT().PublishFunction(gomock.Any(), gomock.Any()).
+			Return(&cloudfront.PublishFunctionOutput{}, nil),
+	)
+	deployer := frontier.NewDeployer(&cf.StaticCFProvider{Client: client})
+	if _, err := deployer.Deploy(ctx, "./testdata/config.yml", true); err != nil {
+		t.Fatal(err)
+	}
+
+	gotSpans := map[string]map[attribute.Key]attribute.Value{}
+	for _, span := range spanRecorder.Ended() {
+		attrs := map[attribute.Key]attribute.Value{}
+		for _, kv := range span.Attributes() {
+			attrs[kv.Key] = kv.Value
+		}
+		gotSpans[span.Name()] = attrs
+	}
+	wantSpans := map[string]map[attribute.Key]attribute.Value{
+		"frontier.Deploy": {
+			"frontier.config.path":    attribute.StringValue("./testdata/config.yml"),
+			"frontier.deploy.publish": attribute.BoolValue(true),
+			"frontier.function.name":  attribute.StringValue("test-func"),
+		},
+		"frontier.ParseConfig": {
+			"frontier.config.path": attribute.StringValue("./testdata/config.yml"),
+		},
+		"frontier.ReadCode": {
+			"frontier.function.name": attribute.StringValue("test-func"),
+			"frontier.code.path":     attribute.StringValue("./testdata/fn.js"),
+			"frontier.code.size":     attribute.IntValue(len(functionCode)),
+			"frontier.code.sha256":   attribute.StringValue(sha256Hex(functionCode)),
+		},
+		"frontier.DecideDeployAction": {
+			"frontier.function.name": attribute.StringValue("test-func"),
+			"frontier.deploy.action": attribute.StringValue("created"),
+		},
+		"frontier.Publish": {
+			"frontier.function.name": attribute.StringValue("test-func"),
+			"frontier.function.etag": attribute.StringValue("etag-1"),
+		},
+	}
+	if diff := cmp.Diff(wantSpans, gotSpans, cmp.AllowUnexported(attribute.Value{})); diff != "" {
+		t.Errorf("spans (-want, +got):\n%s", diff)
+	}
+
+	var rm metricdata.ResourceMetrics
+	if err := metricReader.Collect(ctx, &rm); err != nil {
+		t.Fatal(err)
+	}
+	gotMetrics := map[string]uint64{}
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			switch data := m.Data.(type) {
+			case metricdata.Histogram[float64]:
+				for _, dp := range data.DataPoints {
+					gotMetrics[m.Name] += dp.Count
+				}
+			case metricdata.Histogram[int64]:
+				for _, dp := range data.DataPoints {
+					gotMetrics[m.Name] += dp.Count
+				}
+			}
+		}
+	}
+	wantMetrics := map[string]uint64{
+		"frontier.deploy.duration":    1,
+		"frontier.function.code.size": 1,
+	}
+	if diff := cmp.Diff(wantMetrics, gotMetrics); diff != "" {
+		t.Errorf("metrics (-want, +got):\n%s", diff)
+	}
+}
+
+func TestDeployer_hooks(t *testing.T) {
+	codePath, err := filepath.Abs("./testdata/fn.js")
+	if err != nil {
+		t.Fatal(err)
+	}
+	testCases := []struct {
+		name       string
+		hooks      string
+		mock       func(c *cfmock.MockCloudFrontClient)
+		wantErr    error
+		wantOutput string
+		wantSpans  []string
+		wantETag   string
+	}{
+		{
+			name:  "ok",
+			hooks: "  preDeploy:\n    - test -f function.yml && echo \"pre $FRONTIER_FUNCTION_NAME\" >> \"$OUT\"\n  postDeploy:\n    - echo \"post $FRONTIER_ACTION $FRONTIER_FUNCTION_ARN $FRONTIER_ETAG_BEFORE $FRONTIER_ETAG $FRONTIER_PUBLISHED\" >> \"$OUT\"\n  onFailure:\n    - echo failure >> \"$OUT\"\n",
+			mock: func(c *cfmock.MockCloudFrontClient) {
+				gomock.InOrder(
+					c.EXPECT().GetFunction(gomock.Any(), gomock.Any()).
+						Return(&cloudfront.GetFunctionOutput{ETag: ref("etag-1")}, nil),
+					c.EXPECT().UpdateFunction(gomock.Any(), gomock.Any()).
+						Return(&cloudfront.UpdateFunctionOutput{ETag: ref("etag-2"), FunctionSummary: &types.FunctionSummary{FunctionMetadata: &types.FunctionMetadata{FunctionARN: ref("arn:aws:cloudfront::123456789012:function/test-func")}}}, nil),
+				)
+			},
+			wantOutput: "pre test-func\npost updated arn:aws:cloudfront::123456789012:function/test-func etag-1 etag-2 false\n",
+			wantSpans:  []string{"frontier.hook.preDeploy", "frontier.hook.postDeploy"},
+			wantETag:   "etag-2",
+		},
+		{
+			name:       "preDeploy failed",
+			hooks:      "  preDeploy:\n    - exit 3\n    - echo never >> \"$OUT\"\n  onFailure:\n    - echo \"failure $FRONTIER_ERROR\" >> \"$OUT\"\n",
+			mock:       func(_ *cfmock.MockCloudFrontClient) {},
+			wantErr:    &frontier.HookError{Phase: frontier.HookPhasePreDeploy, Command: "exit 3"},
+			wantOutput: "failure preDeploy hook `exit 3` failed: exit status 3\n",
+			wantSpans:  []string{"frontier.hook.preDeploy", "frontier.hook.onFailure"},
+		},
+		{
+			name:  "postDeploy failed",
+			hooks: "  postDeploy:\n    - echo oops >&2; false\n  onFailure:\n    - echo \"failure $FRONTIER_HOOK $FRONTIER_ETAG\" >> \"$OUT\"\n",
+			mock: func(c *cfmock.MockCloudFrontClient) {
+				gomock.InOrder(
+					c.EXPECT().GetFunction(gomock.Any(), gomock.Any()).
+						Return(&cloudfront.GetFunctionOutput{ETag: ref("etag-1")}, nil),
+					c.EXPECT().UpdateFunction(gomock.Any(), gomock.Any()).
+						Return(&cloudfront.UpdateFunctionOutput{ETag: ref("etag-2")}, nil),
+				)
+			},
+			wantErr:   &frontier.HookError{Phase: frontier.HookPhasePostDeploy, Command: "echo oops >&2; false"},
+			wantSpans: []string{"frontier.hook.postDeploy"},
+			wantETag:  "etag-2",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			if deadline, ok := t.Deadline(); ok {
+				ctx, cancel = context.WithDeadline(ctx, deadline)
+			}
+			defer cancel()
+
+			spanRecorder := tracetest.NewSpanRecorder()
+			prevTP := otel.GetTracerProvider()
+			otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spanRecorder)))
+			defer otel.SetTracerProvider(prevTP)
+
+			dir := t.TempDir()
+			outPath := filepath.Join(dir, "out")
+			t.Setenv("OUT", outPath)
+			configPath := filepath.Join(dir, "function.yml")
+			config := "name: test-func\ncode:\n  path: " + codePath + "\nconfig:\n  comment: blah blah\n  runtime: cloudfront-js-1.0\nhooks:\n" + tc.hooks
+			if err := os.WriteFile(configPath, []byte(config), 0600); err != nil {
+				t.Fatal(err)
+			}
+
+			ctrl := gomock.NewController(t)
+			client := cfmock.NewMockCloudFrontClient(ctrl)
+			tc.mock(client)
+			deployer := frontier.NewDeployer(&cf.StaticCFProvider{Client: client})
+			result, gotErr := deployer.Deploy(ctx, configPath, false)
+			if diff := cmp.Diff(tc.wantErr, gotErr, cmpopts.EquateErrors()); diff != "" {
+				t.Errorf("error (-want, +got):\n%s", diff)
+			}
+			var gotETag string
+			if result != nil {
+				gotETag = result.ETagAfter
+			}
+			if gotETag != tc.wantETag {
+				t.Errorf("ETag of the result: want %q, got %q", tc.wantETag, gotETag)
+			}
+			gotOutput, _ := os.ReadFile(outPath)
+			if diff := cmp.Diff(tc.wantOutput, string(gotOutput)); diff != "" {
+				t.Errorf("hook output (-want, +got):\n%s", diff)
+			}
+			var gotSpans []string
+			for _, span := range spanRecorder.Ended() {
+				if strings.HasPrefix(span.Name(), "frontier.hook.") {
+					gotSpans = append(gotSpans, span.Name())
+				}
+			}
+			if diff := cmp.Diff(tc.wantSpans, gotSpans); diff != "" {
+				t.Errorf("spans (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestDeployer_importedConfigIsUnchanged(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	if deadline, ok := t.Deadline(); ok {
+		ctx, cancel = context.WithDeadline(ctx, deadline)
+	}
+	defer cancel()
+
+	srv := cfemu.NewServer()
+	defer srv.Close()
+	client := &countingClient{CloudFrontClient: srv.CloudFrontClient()}
+	provider := &cf.StaticCFProvider{Client: client}
+	deployer := frontier.NewDeployer(provider)
+
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "function.yml")
+	config := "name: test-fn\ncode:\n  path: " + filepath.Join(dir, "fn.js") + "\nconfig:\n  comment: blah blah\n  runtime: cloudfront-js-2.0\n  keyValueStoreAssociations:\n    - arn: arn:aws:cloudfront::123456789012:key-value-store/kvs-1\n"
+	if err := os.WriteFile(configPath, []byte(config), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "fn.js"), functionCode, 0600); err != nil {
+		t.Fatal(err)
 	}
+	if _, err := deployer.Deploy(ctx, configPath, true); err != nil {
+		t.Fatalf("Deploy: %+v", err)
+	}
+
+	importedConfigPath := filepath.Join(dir, "imported.yml")
+	configFile, err := os.Create(importedConfigPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer configFile.Close()
+	codeFile, err := os.Create(filepath.Join(dir, "imported.js"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer codeFile.Close()
+	if _, err := frontier.NewImporter(provider).Import(ctx, "test-fn", configFile, &frontier.WritableFile{Writer: codeFile, FilePath: codeFile.Name()}, frontier.ImportStage(types.FunctionStageLive)); err != nil {
+		t.Fatalf("Import: %+v", err)
+	}
+
+	for _, publish := range []bool{false, true} {
+		client.updates, client.publishes = 0, 0
+		result, err := deployer.Deploy(ctx, importedConfigPath, publish)
+		if err != nil {
+			t.Fatalf("Deploy(publish=%v): %+v", publish, err)
+		}
+		if result.Action != frontier.DeployActionUnchanged {
+			t.Errorf("Deploy(publish=%v): want action %q, got %q", publish, frontier.DeployActionUnchanged, result.Action)
+		}
+		if client.updates != 0 || client.publishes != 0 {
+			t.Errorf("Deploy(publish=%v): want no update nor publish, got %d updates and %d publishes", publish, client.updates, client.publishes)
+		}
+	}
+
+	// the DEVELOPMENT stage ahead of LIVE is published without the update of the same code
+	if err := os.WriteFile(filepath.Join(dir, "fn.js"), append(functionCode, []byte("\n// changed\n")...), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := deployer.Deploy(ctx, configPath, false); err != nil {
+		t.Fatalf("Deploy: %+v", err)
+	}
+	client.updates, client.publishes = 0, 0
+	result, err := deployer.Deploy(ctx, configPath, true)
+	if err != nil {
+		t.Fatalf("Deploy: %+v", err)
+	}
+	if client.updates != 0 || client.publishes != 1 || !result.Published {
+		t.Errorf("want only the publish, got %d updates and %d publishes: %#v", client.updates, client.publishes, result)
+	}
+}
+
+type countingClient struct {
+	cf.CloudFrontClient
+	updates   int
+	publishes int
+}
+
+func (c *countingClient) UpdateFunction(ctx context.Context, params *cloudfront.UpdateFunctionInput, optFns ...func(*cloudfront.Options)) (*cloudfront.UpdateFunctionOutput, error) {
+	c.updates++
+	return c.CloudFrontClient.UpdateFunction(ctx, params, optFns...)
+}
+
+func (c *countingClient) PublishFunction(ctx context.Context, params *cloudfront.PublishFunctionInput, optFns ...func(*cloudfront.Options)) (*cloudfront.PublishFunctionOutput, error) {
+	c.publishes++
+	return c.CloudFrontClient.PublishFunction(ctx, params, optFns...)
 }
 
 func ref[T any](v T) *T {
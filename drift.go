@@ -0,0 +1,166 @@
+package frontier
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/aereal/frontier/cf"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudfront/types"
+)
+
+type DriftStatus string
+
+const (
+	DriftStatusInSync       DriftStatus = "in-sync"
+	DriftStatusDevAhead     DriftStatus = "dev-ahead"
+	DriftStatusLiveModified DriftStatus = "live-modified"
+	DriftStatusMissing      DriftStatus = "missing"
+)
+
+type FunctionState struct {
+	CodeSHA256        string
+	Comment           string
+	Runtime           string
+	KeyValueStoreARNs []string `json:",omitempty"`
+	ETag              string
+}
+
+func (s *FunctionState) sameAs(other *FunctionState) bool {
+	if s == nil || other == nil {
+		return false
+	}
+	return s.CodeSHA256 == other.CodeSHA256 && s.Comment == other.Comment && s.Runtime == other.Runtime &&
+		slices.Equal(s.KeyValueStoreARNs, other.KeyValueStoreARNs)
+}
+
+type DriftReport struct {
+	FunctionName string
+	ConfigPath   string
+	Status       DriftStatus
+	Local        *FunctionState
+	Development  *FunctionState
+	Live         *FunctionState
+	// Error tells why the function under the directory is not checked; the other functions are checked regardless.
+	Error string `json:",omitempty"`
+}
+
+func NewDriftDetector(clientProvider cf.Provider) *DriftDetector {
+	return &DriftDetector{clientProvider: clientProvider}
+}
+
+type DriftDetector struct {
+	clientProvider cf.Provider
+}
+
+type DriftOption interface {
+	applyDriftOption(cfg *configDrift)
+}
+
+type configDrift struct {
+	codePathRelativeToConfig bool
+}
+
+// DriftCodePathRelativeToConfig resolves the relative code.path against the directory of the config instead of the current directory,
+// so that the configs found under the directories can be checked from anywhere.
+func DriftCodePathRelativeToConfig(relative bool) DriftOption { //nolint:ireturn
+	return &optDriftCodePathRelativeToConfig{relative: relative}
+}
+
+type optDriftCodePathRelativeToConfig struct{ relative bool }
+
+var _ DriftOption = (*optDriftCodePathRelativeToConfig)(nil)
+
+func (o *optDriftCodePathRelativeToConfig) applyDriftOption(cfg *configDrift) {
+	cfg.codePathRelativeToConfig = o.relative
+}
+
+func (d *DriftDetector) DetectDrift(ctx context.Context, configPath string, opts ...DriftOption) (*DriftReport, error) {
+	var cfg configDrift
+	for _, o := range opts {
+		o.applyDriftOption(&cfg)
+	}
+	fn, err := ParseConfigFromPath(configPath)
+	if err != nil {
+		return nil, err
+	}
+	if fn.Code == nil || fn.Code.Path == "" {
+		return nil, &InvalidConfigError{Field: "code.path", Reason: "is required"}
+	}
+	codePath := fn.Code.Path
+	if cfg.codePathRelativeToConfig && !filepath.IsAbs(codePath) {
+		codePath = filepath.Join(filepath.Dir(configPath), codePath)
+	}
+	code, err := os.ReadFile(codePath)
+	if err != nil {
+		return nil, err
+	}
+	report := &DriftReport{
+		FunctionName: fn.Name,
+		ConfigPath:   configPath,
+		Local:        fn.localState(codeSHA256(code)),
+	}
+
+	client, err := d.clientProvider.ProvideCloudFrontClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+	report.Development, err = fetchFunctionState(ctx, client, fn.Name, types.FunctionStageDevelopment)
+	if err != nil {
+		return nil, err
+	}
+	if report.Development == nil {
+		report.Status = DriftStatusMissing
+		return report, nil
+	}
+	report.Live, err = fetchFunctionState(ctx, client, fn.Name, types.FunctionStageLive)
+	if err != nil {
+		return nil, err
+	}
+	// each stage is compared separately so that the unpublished changes on DEVELOPMENT never hide the changes on LIVE, and vice versa.
+	switch {
+	case report.Live != nil && !report.Local.sameAs(report.Live):
+		report.Status = DriftStatusLiveModified
+	case !report.Development.sameAs(report.Live):
+		report.Status = DriftStatusDevAhead
+	default:
+		report.Status = DriftStatusInSync
+	}
+	return report, nil
+}
+
+func fetchFunctionState(ctx context.Context, client cf.CloudFrontClient, name string, stage types.FunctionStage) (*FunctionState, error) {
+	remote, err := fetchRemoteFunction(ctx, client, name, stage)
+	if err != nil {
+		var noSuchFn *types.NoSuchFunctionExists
+		if errors.As(err, &noSuchFn) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return remote.state(), nil
+}
+
+func (fn *Function) localState(codeSHA256 string) *FunctionState {
+	state := &FunctionState{CodeSHA256: codeSHA256}
+	if fn.Config != nil {
+		state.Comment = fn.Config.Comment
+		state.Runtime = string(fn.Config.Runtime)
+		state.KeyValueStoreARNs = fn.Config.keyValueStoreARNs()
+	}
+	return state
+}
+
+func (r *remoteFunction) state() *FunctionState {
+	state := &FunctionState{CodeSHA256: codeSHA256(r.Code), ETag: aws.ToString(r.ETag)}
+	if r.Summary != nil && r.Summary.FunctionConfig != nil {
+		cfg := functionConfigFromSDK(r.Summary.FunctionConfig)
+		state.Comment = cfg.Comment
+		state.Runtime = string(cfg.Runtime)
+		state.KeyValueStoreARNs = cfg.keyValueStoreARNs()
+	}
+	return state
+}
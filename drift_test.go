@@ -0,0 +1,263 @@
+package frontier_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/aereal/frontier"
+	"github.com/aereal/frontier/cf"
+	"github.com/aereal/frontier/internal/cfmock"
+	"github.com/aws/aws-sdk-go-v2/service/cloudfront"
+	"github.com/aws/aws-sdk-go-v2/service/cloudfront/types"
+	"github.com/google/go-cmp/cmp"
+	"go.uber.org/mock/gomock"
+)
+
+func TestDriftDetector_DetectDrift(t *testing.T) {
+	localState := &frontier.FunctionState{
+		CodeSHA256: sha256Hex(functionCode),
+		Comment:    "blah blah",
+		Runtime:    "cloudfront-js-1.0",
+	}
+	modifiedCode := []byte("function handler(event) { return event.request; }\n")
+	testCases := []struct {
+		name       string
+		mock       func(c *cfmock.MockCloudFrontClient)
+		wantReport *frontier.DriftReport
+		wantErr    error
+	}{
+		{
+			name: "in sync",
+			mock: func(c *cfmock.MockCloudFrontClient) {
+				returnStage(c, types.FunctionStageDevelopment, functionCode, "blah blah")
+				returnStage(c, types.FunctionStageLive, functionCode, "blah blah")
+			},
+			wantReport: &frontier.DriftReport{
+				FunctionName: "test-func",
+				ConfigPath:   "./testdata/config.yml",
+				Status:       frontier.DriftStatusInSync,
+				Local:        localState,
+				Development:  withETag(localState, "etag-DEVELOPMENT"),
+				Live:         withETag(localState, "etag-LIVE"),
+			},
+		},
+		{
+			name: "live modified and development reset",
+			mock: func(c *cfmock.MockCloudFrontClient) {
+				returnStage(c, types.FunctionStageDevelopment, functionCode, "blah blah")
+				returnStage(c, types.FunctionStageLive, modifiedCode, "blah blah")
+			},
+			wantReport: &frontier.DriftReport{
+				FunctionName: "test-func",
+				ConfigPath:   "./testdata/config.yml",
+				Status:       frontier.DriftStatusLiveModified,
+				Local:        localState,
+				Development:  withETag(localState, "etag-DEVELOPMENT"),
+				Live:         &frontier.FunctionState{CodeSHA256: sha256Hex(modifiedCode), Comment: "blah blah", Runtime: "cloudfront-js-1.0", ETag: "etag-LIVE"},
+			},
+		},
+		{
+			name: "development modified but not published",
+			mock: func(c *cfmock.MockCloudFrontClient) {
+				returnStage(c, types.FunctionStageDevelopment, modifiedCode, "blah blah")
+				returnStage(c, types.FunctionStageLive, functionCode, "blah blah")
+			},
+			wantReport: &frontier.DriftReport{
+				FunctionName: "test-func",
+				ConfigPath:   "./testdata/config.yml",
+				Status:       frontier.DriftStatusDevAhead,
+				Local:        localState,
+				Development:  &frontier.FunctionState{CodeSHA256: sha256Hex(modifiedCode), Comment: "blah blah", Runtime: "cloudfront-js-1.0", ETag: "etag-DEVELOPMENT"},
+				Live:         withETag(localState, "etag-LIVE"),
+			},
+		},
+		{
+			name: "never published",
+			mock: func(c *cfmock.MockCloudFrontClient) {
+				returnStage(c, types.FunctionStageDevelopment, functionCode, "blah blah")
+				returnNoSuchFunction(c, types.FunctionStageLive)
+			},
+			wantReport: &frontier.DriftReport{
+				FunctionName: "test-func",
+				ConfigPath:   "./testdata/config.yml",
+				Status:       frontier.DriftStatusDevAhead,
+				Local:        localState,
+				Development:  withETag(localState, "etag-DEVELOPMENT"),
+			},
+		},
+		{
+			name: "comment modified",
+			mock: func(c *cfmock.MockCloudFrontClient) {
+				returnStage(c, types.FunctionStageDevelopment, functionCode, "hot fix")
+				returnStage(c, types.FunctionStageLive, functionCode, "hot fix")
+			},
+			wantReport: &frontier.DriftReport{
+				FunctionName: "test-func",
+				ConfigPath:   "./testdata/config.yml",
+				Status:       frontier.DriftStatusLiveModified,
+				Local:        localState,
+				Development:  &frontier.FunctionState{CodeSHA256: localState.CodeSHA256, Comment: "hot fix", Runtime: "cloudfront-js-1.0", ETag: "etag-DEVELOPMENT"},
+				Live:         &frontier.FunctionState{CodeSHA256: localState.CodeSHA256, Comment: "hot fix", Runtime: "cloudfront-js-1.0", ETag: "etag-LIVE"},
+			},
+		},
+		{
+			name: "missing",
+			mock: func(c *cfmock.MockCloudFrontClient) {
+				returnNoSuchFunction(c, types.FunctionStageDevelopment)
+			},
+			wantReport: &frontier.DriftReport{
+				FunctionName: "test-func",
+				ConfigPath:   "./testdata/config.yml",
+				Status:       frontier.DriftStatusMissing,
+				Local:        localState,
+			},
+		},
+		{
+			name:    "failed to call GetFunction()",
+			wantErr: errOops,
+			mock: func(c *cfmock.MockCloudFrontClient) {
+				c.EXPECT().
+					GetFunction(gomock.Any(), gomock.Any()).
+					Return(nil, errOops).
+					Times(1)
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			if deadline, ok := t.Deadline(); ok {
+				ctx, cancel = context.WithDeadline(ctx, deadline)
+			}
+			defer cancel()
+
+			ctrl := gomock.NewController(t)
+			client := cfmock.NewMockCloudFrontClient(ctrl)
+			tc.mock(client)
+			detector := frontier.NewDriftDetector(&cf.StaticCFProvider{Client: client})
+			gotReport, gotErr := detector.DetectDrift(ctx, "./testdata/config.yml")
+			if !errors.Is(gotErr, tc.wantErr) {
+				t.Errorf("error:\n\twant: %s (%T)\n\t got: %s (%T)", tc.wantErr, tc.wantErr, gotErr, gotErr)
+			}
+			if diff := cmp.Diff(tc.wantReport, gotReport); diff != "" {
+				t.Errorf("report (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestDriftDetector_DetectDrift_codePathRelativeToConfig(t *testing.T) {
+	const configPath = "testdata/functions/function.yml"
+	localState := &frontier.FunctionState{
+		CodeSHA256: sha256Hex(functionCode),
+		Comment:    "blah blah",
+		Runtime:    "cloudfront-js-1.0",
+	}
+	testCases := []struct {
+		name       string
+		opts       []frontier.DriftOption
+		mock       func(c *cfmock.MockCloudFrontClient)
+		wantReport *frontier.DriftReport
+		wantErr    error
+	}{
+		{
+			name: "relative to the config",
+			opts: []frontier.DriftOption{frontier.DriftCodePathRelativeToConfig(true)},
+			mock: func(c *cfmock.MockCloudFrontClient) {
+				returnStage(c, types.FunctionStageDevelopment, functionCode, "blah blah")
+				returnStage(c, types.FunctionStageLive, functionCode, "blah blah")
+			},
+			wantReport: &frontier.DriftReport{
+				FunctionName: "test-func",
+				ConfigPath:   configPath,
+				Status:       frontier.DriftStatusInSync,
+				Local:        localState,
+				Development:  withETag(localState, "etag-DEVELOPMENT"),
+				Live:         withETag(localState, "etag-LIVE"),
+			},
+		},
+		{
+			name:    "relative to the current directory",
+			mock:    func(*cfmock.MockCloudFrontClient) {},
+			wantErr: fs.ErrNotExist,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			if deadline, ok := t.Deadline(); ok {
+				ctx, cancel = context.WithDeadline(ctx, deadline)
+			}
+			defer cancel()
+
+			ctrl := gomock.NewController(t)
+			client := cfmock.NewMockCloudFrontClient(ctrl)
+			tc.mock(client)
+			detector := frontier.NewDriftDetector(&cf.StaticCFProvider{Client: client})
+			gotReport, gotErr := detector.DetectDrift(ctx, configPath, tc.opts...)
+			if !errors.Is(gotErr, tc.wantErr) {
+				t.Errorf("error:\n\twant: %s (%T)\n\t got: %s (%T)", tc.wantErr, tc.wantErr, gotErr, gotErr)
+			}
+			if diff := cmp.Diff(tc.wantReport, gotReport); diff != "" {
+				t.Errorf("report (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func returnStage(c *cfmock.MockCloudFrontClient, stage types.FunctionStage, code []byte, comment string) {
+	c.EXPECT().
+		GetFunction(gomock.Any(), &cloudfront.GetFunctionInput{Name: ref("test-func"), Stage: stage}).
+		Return(&cloudfront.GetFunctionOutput{FunctionCode: code, ETag: ref("etag-" + string(stage))}, nil).
+		Times(1)
+	c.EXPECT().
+		DescribeFunction(gomock.Any(), &cloudfront.DescribeFunctionInput{Name: ref("test-func"), Stage: stage}).
+		Return(&cloudfront.DescribeFunctionOutput{
+			FunctionSummary: &types.FunctionSummary{
+				Name: ref("test-func"),
+				FunctionConfig: &types.FunctionConfig{
+					Comment: ref(comment),
+					Runtime: types.FunctionRuntimeCloudfrontJs10,
+				},
+			},
+		}, nil).
+		Times(1)
+}
+
+func returnNoSuchFunction(c *cfmock.MockCloudFrontClient, stage types.FunctionStage) {
+	c.EXPECT().
+		GetFunction(gomock.Any(), &cloudfront.GetFunctionInput{Name: ref("test-func"), Stage: stage}).
+		Return(nil, errNoSuchFn).
+		Times(1)
+}
+
+func withETag(s *frontier.FunctionState, etag string) *frontier.FunctionState {
+	ret := *s
+	ret.ETag = etag
+	return &ret
+}
+
+func sha256Hex(b []byte) string {
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+func TestDriftDetector_DetectDrift_withoutCode(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "function.yml")
+	if err := os.WriteFile(configPath, []byte("name: test-func\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	ctrl := gomock.NewController(t)
+	detector := frontier.NewDriftDetector(&cf.StaticCFProvider{Client: cfmock.NewMockCloudFrontClient(ctrl)})
+	_, err := detector.DetectDrift(context.Background(), configPath)
+	want := &frontier.InvalidConfigError{Field: "code.path", Reason: "is required"}
+	if !errors.Is(err, want) {
+		t.Errorf("error:\n\twant: %s (%T)\n\t got: %s (%T)", want, want, err, err)
+	}
+}
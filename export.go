@@ -0,0 +1,244 @@
+package frontier
+
+import (
+	"context"
+	"encoding"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+type InvalidExportFormatError struct {
+	V string
+}
+
+func (e *InvalidExportFormatError) Error() string {
+	return fmt.Sprintf("invalid export format: %q", e.V)
+}
+
+func (e *InvalidExportFormatError) Is(other error) bool {
+	otherErr := new(InvalidExportFormatError)
+	if !errors.As(other, &otherErr) {
+		return false
+	}
+	return otherErr.V == e.V
+}
+
+type ExportFormat int
+
+var (
+	_ encoding.TextMarshaler   = (*ExportFormat)(nil)
+	_ encoding.TextUnmarshaler = (*ExportFormat)(nil)
+)
+
+const (
+	ExportFormatCloudFormation ExportFormat = iota
+	ExportFormatTerraform
+)
+
+var (
+	ef2str = map[ExportFormat]string{
+		ExportFormatCloudFormation: "cloudformation",
+		ExportFormatTerraform:      "terraform",
+	}
+	str2ef = map[string]ExportFormat{
+		"cloudformation": ExportFormatCloudFormation,
+		"cdk":            ExportFormatCloudFormation,
+		"terraform":      ExportFormatTerraform,
+	}
+	definedExportFormats = slices.Sorted(maps.Keys(ef2str))
+)
+
+func AvailableExportFormatValues() []ExportFormat {
+	return definedExportFormats
+}
+
+func (ef ExportFormat) MarshalText() ([]byte, error) {
+	s, ok := ef2str[ef]
+	if !ok {
+		return nil, &InvalidExportFormatError{V: ef.String()}
+	}
+	return []byte(s), nil
+}
+
+func (ef *ExportFormat) UnmarshalText(b []byte) error {
+	input := string(b)
+	var ok bool
+	*ef, ok = str2ef[input]
+	if !ok {
+		return &InvalidExportFormatError{V: input}
+	}
+	return nil
+}
+
+func (ef ExportFormat) String() string {
+	s, ok := ef2str[ef]
+	if !ok {
+		return fmt.Sprintf("ExportFormat(%d)", ef)
+	}
+	return s
+}
+
+func NewExporter() *Exporter {
+	return &Exporter{}
+}
+
+type Exporter struct{}
+
+func (e *Exporter) Export(ctx context.Context, configPath string, format ExportFormat, output io.Writer) error {
+	fn, err := ParseConfigFromPath(configPath)
+	if err != nil {
+		return err
+	}
+	if fn.Code == nil || fn.Code.Path == "" {
+		return &InvalidConfigError{Field: "code.path", Reason: "is required"}
+	}
+	code, err := os.ReadFile(fn.Code.Path)
+	if err != nil {
+		return err
+	}
+	switch format {
+	case ExportFormatCloudFormation:
+		return writeCloudFormationTemplate(fn, code, output)
+	case ExportFormatTerraform:
+		return writeTerraformConfig(fn, code, output)
+	default:
+		return &InvalidExportFormatError{V: format.String()}
+	}
+}
+
+type cfnTemplate struct {
+	AWSTemplateFormatVersion string                 `json:"AWSTemplateFormatVersion"`
+	Resources                map[string]cfnResource `json:"Resources"`
+}
+
+type cfnResource struct {
+	Type           string                `json:"Type"`
+	DeletionPolicy string                `json:"DeletionPolicy,omitempty"`
+	Properties     cfnFunctionProperties `json:"Properties"`
+}
+
+type cfnFunctionProperties struct {
+	Name           string            `json:"Name"`
+	AutoPublish    bool              `json:"AutoPublish"`
+	FunctionCode   string            `json:"FunctionCode"`
+	FunctionConfig cfnFunctionConfig `json:"FunctionConfig"`
+}
+
+type cfnFunctionConfig struct {
+	Comment                   string                        `json:"Comment"`
+	Runtime                   string                        `json:"Runtime"`
+	KeyValueStoreAssociations []cfnKeyValueStoreAssociation `json:"KeyValueStoreAssociations,omitempty"`
+}
+
+type cfnKeyValueStoreAssociation struct {
+	KeyValueStoreARN string `json:"KeyValueStoreARN"`
+}
+
+func writeCloudFormationTemplate(fn *Function, code []byte, output io.Writer) error {
+	props := cfnFunctionProperties{
+		Name:         fn.Name,
+		AutoPublish:  true,
+		FunctionCode: string(code),
+	}
+	if fn.Config != nil {
+		props.FunctionConfig.Comment = fn.Config.Comment
+		props.FunctionConfig.Runtime = string(fn.Config.Runtime)
+		for _, arn := range fn.Config.keyValueStoreARNs() {
+			props.FunctionConfig.KeyValueStoreAssociations = append(props.FunctionConfig.KeyValueStoreAssociations, cfnKeyValueStoreAssociation{KeyValueStoreARN: arn})
+		}
+	}
+	tmpl := cfnTemplate{
+		AWSTemplateFormatVersion: "2010-09-09",
+		Resources: map[string]cfnResource{
+			cfnLogicalID(fn.Name): {
+				Type: "AWS::CloudFront::Function",
+				// retained resources can be imported to or removed from the stack without deleting the function
+				DeletionPolicy: "Retain",
+				Properties:     props,
+			},
+		},
+	}
+	enc := json.NewEncoder(output)
+	enc.SetIndent("", "  ")
+	return enc.Encode(tmpl)
+}
+
+var (
+	nonAlnum        = regexp.MustCompile(`[^A-Za-z0-9]+`)
+	nonTFIdentifier = regexp.MustCompile(`[^A-Za-z0-9_-]`)
+)
+
+func cfnLogicalID(name string) string {
+	b := new(strings.Builder)
+	for _, part := range nonAlnum.Split(name, -1) {
+		if part == "" {
+			continue
+		}
+		b.WriteString(strings.ToUpper(part[:1]))
+		b.WriteString(part[1:])
+	}
+	b.WriteString("Function")
+	return b.String()
+}
+
+func tfIdentifier(name string) string {
+	id := nonTFIdentifier.ReplaceAllString(name, "_")
+	if id == "" || (id[0] >= '0' && id[0] <= '9') || id[0] == '-' {
+		id = "_" + id
+	}
+	return id
+}
+
+func writeTerraformConfig(fn *Function, code []byte, output io.Writer) error {
+	var comment, runtime string
+	if fn.Config != nil {
+		comment = fn.Config.Comment
+		runtime = string(fn.Config.Runtime)
+	}
+	id := tfIdentifier(fn.Name)
+	body := string(code)
+	if !strings.HasSuffix(body, "\n") {
+		body += "\n"
+	}
+	delim := "EOT"
+	for strings.Contains(body, delim) {
+		delim += "_"
+	}
+	b := new(strings.Builder)
+	fmt.Fprintf(b, "resource \"aws_cloudfront_function\" %s {\n", hclString(id))
+	fmt.Fprintf(b, "  name    = %s\n", hclString(fn.Name))
+	fmt.Fprintf(b, "  runtime = %s\n", hclString(runtime))
+	fmt.Fprintf(b, "  comment = %s\n", hclString(comment))
+	if arns := fn.Config.keyValueStoreARNs(); len(arns) > 0 {
+		quoted := make([]string, 0, len(arns))
+		for _, arn := range arns {
+			quoted = append(quoted, hclString(arn))
+		}
+		fmt.Fprintf(b, "  key_value_store_associations = [%s]\n", strings.Join(quoted, ", "))
+	}
+	fmt.Fprintf(b, "  publish = true\n")
+	fmt.Fprintf(b, "  code    = <<%s\n%s%s\n", delim, hclTemplateEscaper.Replace(body), delim)
+	fmt.Fprintf(b, "}\n\n")
+	fmt.Fprintf(b, "import {\n")
+	fmt.Fprintf(b, "  to = aws_cloudfront_function.%s\n", id)
+	fmt.Fprintf(b, "  id = %s\n", hclString(fn.Name))
+	fmt.Fprintf(b, "}\n")
+	_, err := io.WriteString(output, b.String())
+	return err
+}
+
+var (
+	hclTemplateEscaper = strings.NewReplacer("${", "$${", "%{", "%%{")
+	hclStringEscaper   = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\r", `\r`, "\t", `\t`)
+)
+
+func hclString(s string) string {
+	return `"` + hclTemplateEscaper.Replace(hclStringEscaper.Replace(s)) + `"`
+}
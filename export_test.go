@@ -0,0 +1,137 @@
+package frontier_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/aereal/frontier"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestExporter_Export(t *testing.T) {
+	testCases := []struct {
+		name       string
+		configPath string
+		format     frontier.ExportFormat
+		wantOutput string
+		wantErr    error
+	}{
+		{
+			name:   "cloudformation",
+			format: frontier.ExportFormatCloudFormation,
+			wantOutput: `{
+  "AWSTemplateFormatVersion": "2010-09-09",
+  "Resources": {
+    "TestFuncFunction": {
+      "Type": "AWS::CloudFront::Function",
+      "DeletionPolicy": "Retain",
+      "Properties": {
+        "Name": "test-func",
+        "AutoPublish": true,
+        "FunctionCode": ` + jsonString(t, string(functionCode)) + `,
+        "FunctionConfig": {
+          "Comment": "blah blah",
+          "Runtime": "cloudfront-js-1.0"
+        }
+      }
+    }
+  }
+}
+`,
+		},
+		{
+			name:   "terraform",
+			format: frontier.ExportFormatTerraform,
+			wantOutput: `resource "aws_cloudfront_function" "test-func" {
+  name    = "test-func"
+  runtime = "cloudfront-js-1.0"
+  comment = "blah blah"
+  publish = true
+  code    = <<EOT
+` + string(functionCode) + `EOT
+}
+
+import {
+  to = aws_cloudfront_function.test-func
+  id = "test-func"
+}
+`,
+		},
+		{
+			name:    "unknown format",
+			format:  frontier.ExportFormat(-1),
+			wantErr: &frontier.InvalidExportFormatError{V: "ExportFormat(-1)"},
+		},
+		{
+			name:       "without code",
+			configPath: "./testdata/config-without-code.yml",
+			format:     frontier.ExportFormatCloudFormation,
+			wantErr:    &frontier.InvalidConfigError{Field: "code.path", Reason: "is required"},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			if deadline, ok := t.Deadline(); ok {
+				ctx, cancel = context.WithDeadline(ctx, deadline)
+			}
+			defer cancel()
+
+			configPath := tc.configPath
+			if configPath == "" {
+				configPath = "./testdata/config.yml"
+			}
+			buf := new(bytes.Buffer)
+			gotErr := frontier.NewExporter().Export(ctx, configPath, tc.format, buf)
+			if !errors.Is(gotErr, tc.wantErr) {
+				t.Errorf("error:\n\twant: %T %s\n\t got: %T %s", tc.wantErr, tc.wantErr, gotErr, gotErr)
+			}
+			if gotErr != nil {
+				return
+			}
+			if diff := cmp.Diff(tc.wantOutput, buf.String()); diff != "" {
+				t.Errorf("output (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestExportFormat_unmarshal(t *testing.T) {
+	testCases := []struct {
+		input   string
+		want    frontier.ExportFormat
+		wantErr error
+	}{
+		{input: "cloudformation", want: frontier.ExportFormatCloudFormation},
+		{input: "cdk", want: frontier.ExportFormatCloudFormation},
+		{input: "terraform", want: frontier.ExportFormatTerraform},
+		{input: "pulumi", wantErr: &frontier.InvalidExportFormatError{V: "pulumi"}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			var got frontier.ExportFormat
+			gotErr := got.UnmarshalText([]byte(tc.input))
+			if !errors.Is(gotErr, tc.wantErr) {
+				t.Errorf("error:\n\twant: %T %s\n\t got: %T %s", tc.wantErr, tc.wantErr, gotErr, gotErr)
+			}
+			if gotErr != nil {
+				return
+			}
+			if got != tc.want {
+				t.Errorf("want=%s got=%s", tc.want, got)
+			}
+		})
+	}
+}
+
+func jsonString(t *testing.T, s string) string {
+	t.Helper()
+	b, err := json.Marshal(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
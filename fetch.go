@@ -0,0 +1,57 @@
+package frontier
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"github.com/aereal/frontier/cf"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudfront"
+	"github.com/aws/aws-sdk-go-v2/service/cloudfront/types"
+)
+
+type remoteFunction struct {
+	Code    []byte
+	ETag    *string
+	Summary *types.FunctionSummary
+}
+
+func fetchRemoteFunction(ctx context.Context, client cf.CloudFrontClient, name string, stage types.FunctionStage) (_ *remoteFunction, err error) {
+	ctx, span := startSpan(ctx, "frontier.FetchRemoteFunction", attrFunctionName.String(name), attrFunctionStage.String(string(stage)))
+	defer func() { endSpan(span, err) }()
+	getInput := &cloudfront.GetFunctionInput{
+		Name:  &name,
+		Stage: stage,
+	}
+	getOut, err := client.GetFunction(ctx, getInput)
+	if err != nil {
+		noSuchFn := new(types.NoSuchFunctionExists)
+		if errors.As(err, &noSuchFn) {
+			return nil, noSuchFn
+		}
+		return nil, fmt.Errorf("GetFunction: %w", err)
+	}
+
+	describeInput := &cloudfront.DescribeFunctionInput{
+		Name:  &name,
+		Stage: stage,
+	}
+	describeOut, err := client.DescribeFunction(ctx, describeInput)
+	if err != nil {
+		return nil, fmt.Errorf("DescribeFunction: %w", err)
+	}
+	span.SetAttributes(attrETag.String(aws.ToString(getOut.ETag)))
+	return &remoteFunction{
+		Code:    getOut.FunctionCode,
+		ETag:    getOut.ETag,
+		Summary: describeOut.FunctionSummary,
+	}, nil
+}
+
+func codeSHA256(code []byte) string {
+	sum := sha256.Sum256(code)
+	return hex.EncodeToString(sum[:])
+}
@@ -6,7 +6,7 @@ import (
 	"fmt"
 	"reflect"
 
-	"github.com/aereal/frontier/internal/cf"
+	"github.com/aereal/frontier/cf"
 	"github.com/aws/aws-sdk-go-v2/service/cloudfront"
 )
 
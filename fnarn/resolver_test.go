@@ -6,9 +6,9 @@ import (
 	"reflect"
 	"testing"
 
-	"github.com/aereal/frontier/internal/cf"
+	"github.com/aereal/frontier/cf"
+	"github.com/aereal/frontier/fnarn"
 	"github.com/aereal/frontier/internal/cfmock"
-	"github.com/aereal/frontier/internal/fnarn"
 	"github.com/aws/aws-sdk-go-v2/service/cloudfront"
 	"github.com/aws/aws-sdk-go-v2/service/cloudfront/types"
 	"github.com/google/go-cmp/cmp"
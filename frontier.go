@@ -1,11 +1,15 @@
 package frontier
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"io"
 	"os"
+	"slices"
 
+	"github.com/aereal/frontier/lint"
+	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/cloudfront"
 	"github.com/aws/aws-sdk-go-v2/service/cloudfront/types"
 	"gopkg.in/yaml.v3"
@@ -20,15 +24,48 @@ func (MissingFunctionNameError) Is(err error) bool {
 	return errors.As(err, &fnrErr)
 }
 
+type InvalidConfigError struct {
+	Field  string
+	Reason string
+}
+
+func (e *InvalidConfigError) Error() string { return fmt.Sprintf("%s: %s", e.Field, e.Reason) }
+
+func (e *InvalidConfigError) Is(other error) bool {
+	otherErr := new(InvalidConfigError)
+	if !errors.As(other, &otherErr) {
+		return false
+	}
+	return *otherErr == *e
+}
+
+// ConfigError tells the config file cannot be read or decoded.
+type ConfigError struct {
+	Path string
+	Err  error
+}
+
+func (e *ConfigError) Error() string { return e.Err.Error() }
+
+func (e *ConfigError) Unwrap() error { return e.Err }
+
+func (e *ConfigError) Is(other error) bool {
+	otherErr := new(ConfigError)
+	if !errors.As(other, &otherErr) {
+		return false
+	}
+	return otherErr.Path == e.Path
+}
+
 func ParseConfigFromPath(configPath string) (*Function, error) {
 	f, err := os.Open(configPath)
 	if err != nil {
-		return nil, fmt.Errorf("os.Open: %w", err)
+		return nil, &ConfigError{Path: configPath, Err: fmt.Errorf("os.Open: %w", err)}
 	}
 	defer f.Close()
 	fn := new(Function)
 	if err := yaml.NewDecoder(f).Decode(fn); err != nil {
-		return nil, fmt.Errorf("yaml.Decoder.Decode: %w", err)
+		return nil, &ConfigError{Path: configPath, Err: fmt.Errorf("yaml.Decoder.Decode: %w", err)}
 	}
 	if fn.Name == "" {
 		return nil, MissingFunctionNameError{}
@@ -36,9 +73,33 @@ func ParseConfigFromPath(configPath string) (*Function, error) {
 	return fn, nil
 }
 
-func writeFunctionToStream(fn *Function, out io.Writer) error {
+func parseConfig(ctx context.Context, configPath string) (_ *Function, err error) {
+	_, span := startSpan(ctx, "frontier.ParseConfig", attrConfigPath.String(configPath))
+	defer func() { endSpan(span, err) }()
+	return ParseConfigFromPath(configPath)
+}
+
+const defaultIndent = 2
+
+type IndentOption interface {
+	NewImporterOption
+	NewRendererOption
+}
+
+// Indent sets the number of spaces to indent the function config written in YAML.
+func Indent(indent int) IndentOption { return &optIndent{indent: indent} } //nolint:ireturn
+
+type optIndent struct{ indent int }
+
+var _ IndentOption = (*optIndent)(nil)
+
+func (o *optIndent) applyNewImporterOption(i *Importer) { i.indent = o.indent }
+
+func (o *optIndent) applyNewRendererOption(r *Renderer) { r.indent = o.indent }
+
+func writeFunctionToStream(fn *Function, out io.Writer, indent int) error {
 	enc := yaml.NewEncoder(out)
-	enc.SetIndent(2)
+	enc.SetIndent(indent)
 	if err := enc.Encode(fn); err != nil {
 		return err
 	}
@@ -52,46 +113,104 @@ type Function struct {
 	Name   string          `yaml:"name"`
 	Code   *FunctionCode   `yaml:"code"`
 	Config *FunctionConfig `yaml:"config"`
+	Hooks  *FunctionHooks  `yaml:"hooks,omitempty"`
+	// Watch lists the files (or glob patterns) that `frontier watch` watches in addition to the config and the code, such as bundler inputs.
+	Watch []string `yaml:"watch,omitempty"`
+	// Associations records the distributions the function is associated with at the import.
+	// frontier does not change the associations on deploy.
+	Associations []DistributionAssociation `yaml:"associations,omitempty"`
+	// TestEvents lists the event object files that TestFunction runs with to measure the compute utilization.
+	TestEvents []string        `yaml:"testEvents,omitempty"`
+	Limits     *FunctionLimits `yaml:"limits,omitempty"`
+	Lint       *lint.Config    `yaml:"lint,omitempty"`
 }
 
 type FunctionCode struct {
 	Path string `yaml:"path"`
 }
 
-func (f *Function) toCreateInput() (*cloudfront.CreateFunctionInput, error) {
-	body, err := os.ReadFile(f.Code.Path)
-	if err != nil {
-		return nil, err
+func (fn *Function) validate() error {
+	if fn.Code == nil || fn.Code.Path == "" {
+		return &InvalidConfigError{Field: "code.path", Reason: "is required"}
 	}
-	return &cloudfront.CreateFunctionInput{
-		Name:         &f.Name,
-		FunctionCode: body,
-		FunctionConfig: &types.FunctionConfig{
-			Comment: &f.Config.Comment,
-			Runtime: f.Config.Runtime,
-		},
-	}, nil
+	if _, err := os.Stat(fn.Code.Path); err != nil {
+		return &InvalidConfigError{Field: "code.path", Reason: err.Error()}
+	}
+	if fn.Config == nil {
+		return &InvalidConfigError{Field: "config", Reason: "is required"}
+	}
+	if !slices.Contains(fn.Config.Runtime.Values(), fn.Config.Runtime) {
+		return &InvalidConfigError{Field: "config.runtime", Reason: fmt.Sprintf("unknown runtime %q", fn.Config.Runtime)}
+	}
+	return fn.validateLimits()
 }
 
-func (fn *Function) toUpdateInput(etag *string) (*cloudfront.UpdateFunctionInput, error) {
-	body, err := os.ReadFile(fn.Code.Path)
-	if err != nil {
-		return nil, err
+func (f *Function) toCreateInput(body []byte) *cloudfront.CreateFunctionInput {
+	return &cloudfront.CreateFunctionInput{
+		Name:           &f.Name,
+		FunctionCode:   body,
+		FunctionConfig: f.Config.toSDK(),
 	}
+}
+
+func (fn *Function) toUpdateInput(body []byte, etag *string) *cloudfront.UpdateFunctionInput {
 	return &cloudfront.UpdateFunctionInput{
-		Name:         &fn.Name,
-		FunctionCode: body,
-		IfMatch:      etag,
-		FunctionConfig: &types.FunctionConfig{
-			Comment: &fn.Config.Comment,
-			Runtime: fn.Config.Runtime,
-		},
-	}, nil
+		Name:           &fn.Name,
+		FunctionCode:   body,
+		IfMatch:        etag,
+		FunctionConfig: fn.Config.toSDK(),
+	}
 }
 
 type FunctionConfig struct {
-	Comment string                `yaml:"comment"`
-	Runtime types.FunctionRuntime `yaml:"runtime"`
+	Comment                   string                     `yaml:"comment"`
+	Runtime                   types.FunctionRuntime      `yaml:"runtime"`
+	KeyValueStoreAssociations []KeyValueStoreAssociation `yaml:"keyValueStoreAssociations,omitempty"`
+}
+
+type KeyValueStoreAssociation struct {
+	ARN string `yaml:"arn"`
+}
+
+func (c *FunctionConfig) toSDK() *types.FunctionConfig {
+	cfg := &types.FunctionConfig{
+		Comment: &c.Comment,
+		Runtime: c.Runtime,
+	}
+	if len(c.KeyValueStoreAssociations) > 0 {
+		items := make([]types.KeyValueStoreAssociation, 0, len(c.KeyValueStoreAssociations))
+		for _, kvs := range c.KeyValueStoreAssociations {
+			items = append(items, types.KeyValueStoreAssociation{KeyValueStoreARN: aws.String(kvs.ARN)})
+		}
+		cfg.KeyValueStoreAssociations = &types.KeyValueStoreAssociations{Quantity: aws.Int32(int32(len(items))), Items: items} //nolint:gosec
+	}
+	return cfg
+}
+
+func functionConfigFromSDK(in *types.FunctionConfig) *FunctionConfig {
+	cfg := new(FunctionConfig)
+	if in == nil {
+		return cfg
+	}
+	cfg.Comment = aws.ToString(in.Comment)
+	cfg.Runtime = in.Runtime
+	if kvs := in.KeyValueStoreAssociations; kvs != nil {
+		for _, item := range kvs.Items {
+			cfg.KeyValueStoreAssociations = append(cfg.KeyValueStoreAssociations, KeyValueStoreAssociation{ARN: aws.ToString(item.KeyValueStoreARN)})
+		}
+	}
+	return cfg
+}
+
+func (c *FunctionConfig) keyValueStoreARNs() []string {
+	if c == nil {
+		return nil
+	}
+	var arns []string
+	for _, kvs := range c.KeyValueStoreAssociations {
+		arns = append(arns, kvs.ARN)
+	}
+	return arns
 }
 
 type AssociatedDistribution struct {
@@ -119,3 +238,24 @@ type FunctionAssociation struct {
 	EventType     string
 	Function      AssociatedFunction
 }
+
+// DistributionAssociation is the [FunctionAssociation] written in the function config.
+type DistributionAssociation struct {
+	DistributionID         string `yaml:"distributionId"`
+	DomainName             string `yaml:"domainName,omitempty"`
+	EventType              string `yaml:"eventType"`
+	TargetOriginID         string `yaml:"targetOriginId,omitempty"`
+	CachePolicyID          string `yaml:"cachePolicyId,omitempty"`
+	IsDefaultCacheBehavior bool   `yaml:"defaultCacheBehavior,omitempty"`
+}
+
+func distributionAssociationOf(a FunctionAssociation) DistributionAssociation {
+	return DistributionAssociation{
+		DistributionID:         a.Distribution.ID,
+		DomainName:             a.Distribution.DomainName,
+		EventType:              a.EventType,
+		TargetOriginID:         a.CacheBehavior.TargetOriginID,
+		CachePolicyID:          a.CacheBehavior.CachePolicyID,
+		IsDefaultCacheBehavior: a.CacheBehavior.IsDefault,
+	}
+}
@@ -0,0 +1,167 @@
+package frontier
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strconv"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+type HookPhase string
+
+const (
+	HookPhasePreDeploy  HookPhase = "preDeploy"
+	HookPhasePostDeploy HookPhase = "postDeploy"
+	HookPhaseOnFailure  HookPhase = "onFailure"
+)
+
+// FunctionHooks are shell commands run around the deployment.
+// Each command is run by `sh -c` in the directory of the config with FRONTIER_* environment variables describing the deployment.
+//
+// The failure of PostDeploy does not run OnFailure because the function is already deployed.
+type FunctionHooks struct {
+	PreDeploy  []string `yaml:"preDeploy,omitempty"`
+	PostDeploy []string `yaml:"postDeploy,omitempty"`
+	OnFailure  []string `yaml:"onFailure,omitempty"`
+}
+
+func (h *FunctionHooks) commands(phase HookPhase) []string {
+	if h == nil {
+		return nil
+	}
+	switch phase {
+	case HookPhasePreDeploy:
+		return h.PreDeploy
+	case HookPhasePostDeploy:
+		return h.PostDeploy
+	case HookPhaseOnFailure:
+		return h.OnFailure
+	default:
+		return nil
+	}
+}
+
+type HookError struct {
+	Phase   HookPhase
+	Command string
+	Err     error
+}
+
+func (e *HookError) Error() string {
+	return fmt.Sprintf("%s hook `%s` failed: %s", e.Phase, e.Command, e.Err)
+}
+
+func (e *HookError) Unwrap() error { return e.Err }
+
+func (e *HookError) Is(other error) bool {
+	otherErr := new(HookError)
+	if !errors.As(other, &otherErr) {
+		return false
+	}
+	return otherErr.Phase == e.Phase && otherErr.Command == e.Command
+}
+
+type hookContext struct {
+	configPath string
+	result     *DeployResult
+	deployErr  error
+}
+
+func (hc *hookContext) environ(phase HookPhase) []string {
+	configPath := hc.configPath
+	// the hooks run in the directory of the config, so the path relative to the current directory is useless
+	if abs, err := filepath.Abs(configPath); err == nil {
+		configPath = abs
+	}
+	env := []string{
+		"FRONTIER_HOOK=" + string(phase),
+		"FRONTIER_CONFIG_PATH=" + configPath,
+	}
+	if r := hc.result; r != nil {
+		env = append(env,
+			"FRONTIER_FUNCTION_NAME="+r.FunctionName,
+			"FRONTIER_FUNCTION_ARN="+r.FunctionARN,
+			"FRONTIER_ACTION="+string(r.Action),
+			"FRONTIER_ETAG_BEFORE="+r.ETagBefore,
+			"FRONTIER_ETAG="+r.ETagAfter,
+			"FRONTIER_CODE_SHA256="+r.CodeSHA256,
+			"FRONTIER_PUBLISHED="+strconv.FormatBool(r.Published),
+			"FRONTIER_STAGE="+string(r.Stage),
+		)
+	}
+	if hc.deployErr != nil {
+		env = append(env, "FRONTIER_ERROR="+hc.deployErr.Error())
+	}
+	return env
+}
+
+func runHooks(ctx context.Context, hooks *FunctionHooks, phase HookPhase, hc *hookContext) error {
+	env := append(os.Environ(), hc.environ(phase)...)
+	dir := filepath.Dir(hc.configPath)
+	for _, command := range hooks.commands(phase) {
+		if err := runHook(ctx, phase, command, dir, env); err != nil {
+			return &HookError{Phase: phase, Command: command, Err: err}
+		}
+	}
+	return nil
+}
+
+func runHook(ctx context.Context, phase HookPhase, command, dir string, env []string) (err error) {
+	ctx, span := startSpan(ctx, "frontier.hook."+string(phase),
+		attribute.String("frontier.hook.phase", string(phase)),
+		attribute.String("frontier.hook.command", command))
+	defer func() { endSpan(span, err) }()
+
+	logger := slog.Default().With(slog.String("hook", string(phase)), slog.String("command", command))
+	stdout := &logWriter{ctx: ctx, logger: logger, level: slog.LevelInfo}
+	stderr := &logWriter{ctx: ctx, logger: logger, level: slog.LevelWarn}
+	cmd := exec.CommandContext(ctx, "sh", "-c", command)
+	cmd.Dir = dir
+	cmd.Env = slices.Concat(env, traceEnviron(ctx))
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	err = cmd.Run()
+	stdout.flush()
+	stderr.flush()
+	if state := cmd.ProcessState; state != nil {
+		span.SetAttributes(attribute.Int("process.exit.code", state.ExitCode()))
+	}
+	return err
+}
+
+// logWriter emits each line written by the hook as a log record.
+type logWriter struct {
+	ctx    context.Context //nolint:containedctx
+	logger *slog.Logger
+	level  slog.Level
+	buf    []byte
+}
+
+func (w *logWriter) Write(p []byte) (int, error) {
+	w.buf = append(w.buf, p...)
+	for {
+		idx := bytes.IndexByte(w.buf, '\n')
+		if idx < 0 {
+			break
+		}
+		w.logger.Log(w.ctx, w.level, string(w.buf[:idx]))
+		w.buf = w.buf[idx+1:]
+	}
+	return len(p), nil
+}
+
+func (w *logWriter) flush() {
+	if len(w.buf) == 0 {
+		return
+	}
+	w.logger.Log(w.ctx, w.level, string(w.buf))
+	w.buf = nil
+}
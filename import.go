@@ -2,68 +2,152 @@ package frontier
 
 import (
 	"context"
-	"errors"
-	"fmt"
 	"io"
+	"iter"
 
-	"github.com/aereal/frontier/internal/cf"
-	"github.com/aws/aws-sdk-go-v2/service/cloudfront"
+	"github.com/aereal/frontier/cf"
+	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/cloudfront/types"
 )
 
-func NewImporter(clientProvider cf.Provider) *Importer {
-	return &Importer{
+type NewImporterOption interface {
+	applyNewImporterOption(i *Importer)
+}
+
+func NewImporter(clientProvider cf.Provider, opts ...NewImporterOption) *Importer {
+	i := &Importer{
 		clientProvider: clientProvider,
+		retrier:        defaultRetrier(),
+		indent:         defaultIndent,
+	}
+	for _, o := range opts {
+		o.applyNewImporterOption(i)
 	}
+	return i
 }
 
 type Importer struct {
 	clientProvider cf.Provider
+	retrier        retrier
+	indent         int
 }
 
-func (i *Importer) Import(ctx context.Context, functionName string, configStream io.Writer, functionStream *WritableFile) error {
-	client, err := i.clientProvider.ProvideCloudFrontClient(ctx)
+type ImportResult struct {
+	FunctionName string
+	FunctionARN  string
+	Stage        types.FunctionStage
+	ETag         string
+	CodeSHA256   string
+	CodePath     string
+}
+
+type ImportOption interface {
+	applyImportOption(cfg *configImport)
+}
+
+type configImport struct {
+	stage             types.FunctionStage
+	associationLister AssociationLister
+}
+
+// ImportStage sets the stage of the function to import; DEVELOPMENT is imported by default.
+func ImportStage(stage types.FunctionStage) ImportOption { return &optImportStage{stage: stage} } //nolint:ireturn
+
+type optImportStage struct{ stage types.FunctionStage }
+
+var _ ImportOption = (*optImportStage)(nil)
+
+func (o *optImportStage) applyImportOption(cfg *configImport) { cfg.stage = o.stage }
+
+// AssociationLister lists the distributions associated with the function.
+type AssociationLister interface {
+	ListAssociations(ctx context.Context, functionARN string) iter.Seq2[FunctionAssociation, error]
+}
+
+// ImportAssociations makes the importer write the distributions associated with the function to the config.
+func ImportAssociations(lister AssociationLister) ImportOption { //nolint:ireturn
+	return &optImportAssociations{lister: lister}
+}
+
+type optImportAssociations struct{ lister AssociationLister }
+
+var _ ImportOption = (*optImportAssociations)(nil)
+
+func (o *optImportAssociations) applyImportOption(cfg *configImport) {
+	cfg.associationLister = o.lister
+}
+
+func (i *Importer) Import(ctx context.Context, functionName string, configStream io.Writer, functionStream *WritableFile, opts ...ImportOption) (_ *ImportResult, err error) {
+	var cfg configImport
+	for _, o := range opts {
+		o.applyImportOption(&cfg)
+	}
+	ctx, span := startSpan(ctx, "frontier.Import", attrFunctionName.String(functionName), attrFunctionStage.String(string(cfg.stage)), attrCodePath.String(functionStream.FilePath))
+	defer func() { endSpan(span, err) }()
+	fn, remote, err := i.fetchFunction(ctx, functionName, cfg.stage)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	getInput := &cloudfront.GetFunctionInput{
-		Name: &functionName,
+
+	span.SetAttributes(attrCodeSize.Int(len(remote.Code)), attrCodeSHA256.String(codeSHA256(remote.Code)))
+	recordCodeSize(ctx, len(remote.Code), attrFunctionName.String(functionName), attrOperation.String("import"))
+	fn.Code.Path = functionStream.FilePath
+	result := &ImportResult{
+		FunctionName: fn.Name,
+		ETag:         aws.ToString(remote.ETag),
+		CodeSHA256:   codeSHA256(remote.Code),
+		CodePath:     functionStream.FilePath,
+		Stage:        cfg.stage,
 	}
-	getOut, err := client.GetFunction(ctx, getInput)
-	if err != nil {
-		noSuchFn := new(types.NoSuchFunctionExists)
-		if errors.As(err, &noSuchFn) {
-			return noSuchFn
+	if summary := remote.Summary; summary != nil {
+		result.FunctionARN = functionARNOf(summary)
+		if md := summary.FunctionMetadata; md != nil && md.Stage != "" {
+			result.Stage = md.Stage
 		}
-		return fmt.Errorf("GetFunction: %w", err)
 	}
-
-	describeInput := &cloudfront.DescribeFunctionInput{
-		Name: &functionName,
+	if cfg.associationLister != nil && result.FunctionARN != "" {
+		for association, err := range cfg.associationLister.ListAssociations(ctx, result.FunctionARN) {
+			if err != nil {
+				return nil, err
+			}
+			fn.Associations = append(fn.Associations, distributionAssociationOf(association))
+		}
 	}
-	describeOut, err := client.DescribeFunction(ctx, describeInput)
-	if err != nil {
-		return fmt.Errorf("DescribeFunction: %w", err)
+	if _, err := functionStream.Write(remote.Code); err != nil {
+		return nil, err
 	}
+	if err := writeFunctionToStream(fn, configStream, i.indent); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
 
-	if _, err := functionStream.Write(getOut.FunctionCode); err != nil {
-		return err
+// fetchFunction fetches the function of the stage and builds its config without the code path.
+func (i *Importer) fetchFunction(ctx context.Context, functionName string, stage types.FunctionStage) (*Function, *remoteFunction, error) {
+	client, err := i.clientProvider.ProvideCloudFrontClient(ctx)
+	if err != nil {
+		return nil, nil, err
 	}
-	fnCfg := &FunctionConfig{
-		Comment: *describeOut.FunctionSummary.FunctionConfig.Comment,
-		Runtime: describeOut.FunctionSummary.FunctionConfig.Runtime,
+	var remote *remoteFunction
+	err = i.retrier.do(ctx, func() error {
+		remote, err = fetchRemoteFunction(ctx, client, functionName, stage)
+		return err
+	})
+	if err != nil {
+		return nil, nil, err
 	}
 	fn := &Function{
-		Name:   *describeOut.FunctionSummary.Name,
-		Config: fnCfg,
-		Code: &FunctionCode{
-			Path: functionStream.FilePath,
-		},
+		Name:   functionName,
+		Config: new(FunctionConfig),
+		Code:   new(FunctionCode),
 	}
-	if err := writeFunctionToStream(fn, configStream); err != nil {
-		return err
+	if summary := remote.Summary; summary != nil {
+		if summary.Name != nil {
+			fn.Name = *summary.Name
+		}
+		fn.Config = functionConfigFromSDK(summary.FunctionConfig)
 	}
-	return nil
+	return fn, remote, nil
 }
 
 type WritableFile struct {
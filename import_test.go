@@ -4,10 +4,11 @@ import (
 	"bytes"
 	"context"
 	"errors"
+	"iter"
 	"testing"
 
 	"github.com/aereal/frontier"
-	"github.com/aereal/frontier/internal/cf"
+	"github.com/aereal/frontier/cf"
 	"github.com/aereal/frontier/internal/cfmock"
 	"github.com/aws/aws-sdk-go-v2/service/cloudfront"
 	"github.com/aws/aws-sdk-go-v2/service/cloudfront/types"
@@ -20,8 +21,11 @@ func TestImporter_Import(t *testing.T) {
 	testCases := []struct {
 		name         string
 		mock         func(c *cfmock.MockCloudFrontClient)
+		opts         []frontier.NewImporterOption
+		importOpts   []frontier.ImportOption
 		wantConfig   string
 		wantFunction string
+		wantResult   *frontier.ImportResult
 		wantErr      error
 	}{
 		{
@@ -32,6 +36,109 @@ func TestImporter_Import(t *testing.T) {
 			},
 			wantFunction: identityFunction,
 			wantConfig:   okFunctionConfig,
+			wantResult:   okImportResult,
+		},
+		{
+			name: "indented",
+			mock: func(c *cfmock.MockCloudFrontClient) {
+				okGetFunction(c)
+				okDescribeFunction(c)
+			},
+			opts:         []frontier.NewImporterOption{frontier.Indent(4)},
+			wantFunction: identityFunction,
+			wantConfig:   "name: test-fn\ncode:\n    path: test-fn.js\nconfig:\n    comment: blah blah\n    runtime: cloudfront-js-2.0\n",
+			wantResult:   okImportResult,
+		},
+		{
+			name: "LIVE stage",
+			mock: func(c *cfmock.MockCloudFrontClient) {
+				c.EXPECT().
+					GetFunction(gomock.Any(), &cloudfront.GetFunctionInput{Name: ref("test-fn"), Stage: types.FunctionStageLive}).
+					Return(okGetFunctionOutput, nil).
+					Times(1)
+				c.EXPECT().
+					DescribeFunction(gomock.Any(), &cloudfront.DescribeFunctionInput{Name: ref("test-fn"), Stage: types.FunctionStageLive}).
+					Return(okDescribeFunctionOutput, nil).
+					Times(1)
+			},
+			importOpts:   []frontier.ImportOption{frontier.ImportStage(types.FunctionStageLive)},
+			wantFunction: identityFunction,
+			wantConfig:   okFunctionConfig,
+			wantResult:   okImportResult,
+		},
+		{
+			name: "missing optional fields",
+			mock: func(c *cfmock.MockCloudFrontClient) {
+				okGetFunction(c)
+				c.EXPECT().
+					DescribeFunction(gomock.Any(), gomock.Any()).
+					Return(&cloudfront.DescribeFunctionOutput{FunctionSummary: &types.FunctionSummary{FunctionConfig: &types.FunctionConfig{Runtime: types.FunctionRuntimeCloudfrontJs20}}}, nil).
+					Times(1)
+			},
+			wantFunction: identityFunction,
+			wantConfig:   "name: test-fn\ncode:\n  path: test-fn.js\nconfig:\n  comment: \"\"\n  runtime: cloudfront-js-2.0\n",
+			wantResult: &frontier.ImportResult{
+				FunctionName: "test-fn",
+				ETag:         "0xdeadbeaf",
+				CodeSHA256:   sha256Hex([]byte(identityFunction)),
+				CodePath:     "test-fn.js",
+			},
+		},
+		{
+			name: "key value stores and associations",
+			mock: func(c *cfmock.MockCloudFrontClient) {
+				okGetFunction(c)
+				out := *okDescribeFunctionOutput
+				summary := *out.FunctionSummary
+				summary.FunctionConfig = &types.FunctionConfig{
+					Comment: ref("blah blah"),
+					Runtime: types.FunctionRuntimeCloudfrontJs20,
+					KeyValueStoreAssociations: &types.KeyValueStoreAssociations{
+						Quantity: ref(int32(1)),
+						Items:    []types.KeyValueStoreAssociation{{KeyValueStoreARN: ref("arn:aws:cloudfront::123456789012:key-value-store/kvs-1")}},
+					},
+				}
+				out.FunctionSummary = &summary
+				c.EXPECT().
+					DescribeFunction(gomock.Any(), gomock.Any()).
+					Return(&out, nil).
+					Times(1)
+			},
+			importOpts: []frontier.ImportOption{frontier.ImportAssociations(staticAssociationLister{
+				"arn:aws:cloudfront::123456789012:function/test-fn": {
+					{
+						Distribution:  frontier.AssociatedDistribution{ID: "dist-1", DomainName: "dist-1.test"},
+						CacheBehavior: frontier.CacheBehavior{TargetOriginID: "origin-1", IsDefault: true},
+						EventType:     "viewer-request",
+					},
+				},
+			})},
+			wantFunction: identityFunction,
+			wantConfig: okFunctionConfig + `  keyValueStoreAssociations:
+    - arn: arn:aws:cloudfront::123456789012:key-value-store/kvs-1
+associations:
+  - distributionId: dist-1
+    domainName: dist-1.test
+    eventType: viewer-request
+    targetOriginId: origin-1
+    defaultCacheBehavior: true
+`,
+			wantResult: okImportResult,
+		},
+		{
+			name: "retry on throttling",
+			mock: func(c *cfmock.MockCloudFrontClient) {
+				c.EXPECT().
+					GetFunction(gomock.Any(), gomock.Any()).
+					Return(nil, errThrottled).
+					Times(1)
+				okGetFunction(c)
+				okDescribeFunction(c)
+			},
+			opts:         []frontier.NewImporterOption{frontier.RetryBackoff(0, 0)},
+			wantFunction: identityFunction,
+			wantConfig:   okFunctionConfig,
+			wantResult:   okImportResult,
 		},
 		{
 			name:    "failed to call GetFunction()",
@@ -88,11 +195,14 @@ func TestImporter_Import(t *testing.T) {
 				FilePath: "test-fn.js",
 				Writer:   fnOut,
 			}
-			importer := frontier.NewImporter(&cf.StaticCFProvider{Client: client})
-			gotErr := importer.Import(ctx, "test-fn", configOut, wf)
+			importer := frontier.NewImporter(&cf.StaticCFProvider{Client: client}, tc.opts...)
+			gotResult, gotErr := importer.Import(ctx, "test-fn", configOut, wf, tc.importOpts...)
 			if !errors.Is(gotErr, tc.wantErr) {
 				t.Errorf("error:\n\twant: %s (%T)\n\t got: %s (%T)", tc.wantErr, tc.wantErr, gotErr, gotErr)
 			}
+			if diff := cmp.Diff(tc.wantResult, gotResult); diff != "" {
+				t.Errorf("result (-want, +got):\n%s", diff)
+			}
 			gotConfig := configOut.String()
 			if diff := cmp.Diff(tc.wantConfig, gotConfig); diff != "" {
 				t.Errorf("config file (-want, +got):\n%s", diff)
@@ -142,6 +252,27 @@ function handler(event) { return event.response }
 	}
 )
 
+var okImportResult = &frontier.ImportResult{
+	FunctionName: "test-fn",
+	FunctionARN:  "arn:aws:cloudfront::123456789012:function/test-fn",
+	Stage:        types.FunctionStageLive,
+	ETag:         "0xdeadbeaf",
+	CodeSHA256:   sha256Hex([]byte(identityFunction)),
+	CodePath:     "test-fn.js",
+}
+
+type staticAssociationLister map[string][]frontier.FunctionAssociation
+
+func (l staticAssociationLister) ListAssociations(_ context.Context, functionARN string) iter.Seq2[frontier.FunctionAssociation, error] {
+	return func(yield func(frontier.FunctionAssociation, error) bool) {
+		for _, a := range l[functionARN] {
+			if !yield(a, nil) {
+				return
+			}
+		}
+	}
+}
+
 func okGetFunction(c *cfmock.MockCloudFrontClient) {
 	c.EXPECT().
 		GetFunction(gomock.Any(), gomock.Any()).
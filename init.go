@@ -0,0 +1,245 @@
+package frontier
+
+import (
+	"context"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/aereal/frontier/lint"
+	"github.com/aws/aws-sdk-go-v2/service/cloudfront/types"
+)
+
+//go:embed templates
+var templatesFS embed.FS
+
+// Template is the name of the built-in function template that `frontier init` scaffolds.
+type Template string
+
+const (
+	TemplateRedirect        Template = "redirect"
+	TemplateCORSHeaders     Template = "cors-headers"
+	TemplateSecurityHeaders Template = "security-headers"
+	TemplateBasicAuth       Template = "basic-auth"
+	TemplateSPARewrite      Template = "spa-rewrite"
+	TemplateABCookie        Template = "ab-cookie"
+)
+
+type templateSpec struct {
+	comment   string
+	eventType lint.EventType
+}
+
+var (
+	templateSpecs = map[Template]templateSpec{
+		TemplateRedirect:        {comment: "redirect the old paths to the new locations", eventType: lint.EventTypeViewerRequest},
+		TemplateCORSHeaders:     {comment: "add the CORS headers for the allowed origins", eventType: lint.EventTypeViewerResponse},
+		TemplateSecurityHeaders: {comment: "add the security headers", eventType: lint.EventTypeViewerResponse},
+		TemplateBasicAuth:       {comment: "require the basic authentication", eventType: lint.EventTypeViewerRequest},
+		TemplateSPARewrite:      {comment: "rewrite the routes of the single page application to /index.html", eventType: lint.EventTypeViewerRequest},
+		TemplateABCookie:        {comment: "route the viewers to the A/B variants by the cookie", eventType: lint.EventTypeViewerRequest},
+	}
+	definedTemplates = slices.Sorted(maps.Keys(templateSpecs))
+)
+
+func AvailableTemplates() []Template {
+	return definedTemplates
+}
+
+func (t Template) String() string { return string(t) }
+
+type UnknownTemplateError struct {
+	Name string
+}
+
+func (e *UnknownTemplateError) Error() string {
+	return fmt.Sprintf("unknown template: %q", e.Name)
+}
+
+func (e *UnknownTemplateError) Is(other error) bool {
+	otherErr := new(UnknownTemplateError)
+	if !errors.As(other, &otherErr) {
+		return false
+	}
+	return otherErr.Name == e.Name
+}
+
+const (
+	defaultInitRuntime = types.FunctionRuntimeCloudfrontJs20
+	initConfigFile     = "function.yml"
+	initCodeFile       = "fn.js"
+	initEventsDir      = "events"
+	initExpectedDir    = "expected"
+)
+
+type InitOption interface {
+	applyInitOption(cfg *configInit)
+}
+
+type configInit struct {
+	functionName string
+	runtime      types.FunctionRuntime
+	overwrite    bool
+}
+
+// InitFunctionName sets the name of the function; the name of the template is used by default.
+func InitFunctionName(name string) InitOption { return &optInitFunctionName{name: name} } //nolint:ireturn
+
+type optInitFunctionName struct{ name string }
+
+var _ InitOption = (*optInitFunctionName)(nil)
+
+func (o *optInitFunctionName) applyInitOption(cfg *configInit) { cfg.functionName = o.name }
+
+// InitRuntime sets the runtime of the function; cloudfront-js-2.0 is used by default.
+func InitRuntime(runtime types.FunctionRuntime) InitOption { return &optInitRuntime{runtime: runtime} } //nolint:ireturn
+
+type optInitRuntime struct{ runtime types.FunctionRuntime }
+
+var _ InitOption = (*optInitRuntime)(nil)
+
+func (o *optInitRuntime) applyInitOption(cfg *configInit) { cfg.runtime = o.runtime }
+
+// InitOverwrite makes the initializer overwrite the existing files.
+func InitOverwrite(overwrite bool) InitOption { return &optInitOverwrite{overwrite: overwrite} } //nolint:ireturn
+
+type optInitOverwrite struct{ overwrite bool }
+
+var _ InitOption = (*optInitOverwrite)(nil)
+
+func (o *optInitOverwrite) applyInitOption(cfg *configInit) { cfg.overwrite = o.overwrite }
+
+type InitResult struct {
+	FunctionName string
+	Template     Template
+	ConfigPath   string
+	CodePath     string
+	// TestEvents are the sample event objects, which are also written to testEvents of the config.
+	TestEvents []string
+	// ExpectedOutputs are the outputs of the function that TestFunction should return for TestEvents in the same order.
+	ExpectedOutputs []string
+}
+
+func NewInitializer() *Initializer {
+	return &Initializer{}
+}
+
+// Initializer scaffolds the function config, the code, the sample events and their expected outputs from the built-in templates.
+type Initializer struct{}
+
+// Init writes the files of the template to the directory.
+//
+// code.path and testEvents of the config are joined with the directory,
+// because the other commands read them relative to the current directory.
+func (i *Initializer) Init(ctx context.Context, dir string, template Template, opts ...InitOption) (_ *InitResult, err error) {
+	cfg := configInit{functionName: string(template), runtime: defaultInitRuntime}
+	for _, o := range opts {
+		o.applyInitOption(&cfg)
+	}
+	configPath := filepath.Join(dir, initConfigFile)
+	_, span := startSpan(ctx, "frontier.Init", attrFunctionName.String(cfg.functionName), attrConfigPath.String(configPath))
+	defer func() { endSpan(span, err) }()
+	spec, ok := templateSpecs[template]
+	if !ok {
+		return nil, &UnknownTemplateError{Name: string(template)}
+	}
+	if !slices.Contains(cfg.runtime.Values(), cfg.runtime) {
+		return nil, &InvalidConfigError{Field: "config.runtime", Reason: fmt.Sprintf("unknown runtime %q", cfg.runtime)}
+	}
+	files, err := templateFiles(template)
+	if err != nil {
+		return nil, err
+	}
+	if !cfg.overwrite {
+		for _, name := range slices.Concat([]string{initConfigFile}, slices.Sorted(maps.Keys(files))) {
+			dest := filepath.Join(dir, filepath.FromSlash(name))
+			_, err := os.Stat(dest)
+			if err == nil {
+				return nil, &fs.PathError{Op: "init", Path: dest, Err: fs.ErrExist}
+			}
+			if !errors.Is(err, fs.ErrNotExist) {
+				return nil, err
+			}
+		}
+	}
+	result := &InitResult{
+		FunctionName: cfg.functionName,
+		Template:     template,
+		ConfigPath:   configPath,
+		CodePath:     filepath.Join(dir, initCodeFile),
+	}
+	for _, name := range slices.Sorted(maps.Keys(files)) {
+		dest := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+			return nil, err
+		}
+		if err := os.WriteFile(dest, files[name], 0644); err != nil {
+			return nil, err
+		}
+		switch path.Dir(name) {
+		case initEventsDir:
+			result.TestEvents = append(result.TestEvents, dest)
+		case initExpectedDir:
+			result.ExpectedOutputs = append(result.ExpectedOutputs, dest)
+		}
+	}
+	fn := &Function{
+		Name:       cfg.functionName,
+		Code:       &FunctionCode{Path: result.CodePath},
+		Config:     &FunctionConfig{Comment: spec.comment, Runtime: cfg.runtime},
+		TestEvents: result.TestEvents,
+		Lint:       &lint.Config{EventType: spec.eventType},
+	}
+	if err := writeConfigFile(configPath, fn); err != nil {
+		return nil, err
+	}
+	parsed, err := ParseConfigFromPath(configPath)
+	if err != nil {
+		return nil, err
+	}
+	if err := parsed.validate(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// templateFiles reads the files of the template keyed by the slash-separated path relative to the template.
+func templateFiles(template Template) (map[string][]byte, error) {
+	root := path.Join("templates", string(template))
+	files := map[string][]byte{}
+	err := fs.WalkDir(templatesFS, root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		body, err := templatesFS.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		files[strings.TrimPrefix(p, root+"/")] = body
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return files, nil
+}
+
+func writeConfigFile(configPath string, fn *Function) (err error) {
+	f, err := os.Create(configPath)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+	}()
+	return writeFunctionToStream(fn, f, defaultIndent)
+}
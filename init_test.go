@@ -0,0 +1,201 @@
+package frontier_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/aereal/frontier"
+	"github.com/aereal/frontier/lint"
+	"github.com/aws/aws-sdk-go-v2/service/cloudfront/types"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func TestInitializer_Init_templates(t *testing.T) {
+	eventTypes := map[frontier.Template]lint.EventType{
+		frontier.TemplateRedirect:        lint.EventTypeViewerRequest,
+		frontier.TemplateCORSHeaders:     lint.EventTypeViewerResponse,
+		frontier.TemplateSecurityHeaders: lint.EventTypeViewerResponse,
+		frontier.TemplateBasicAuth:       lint.EventTypeViewerRequest,
+		frontier.TemplateSPARewrite:      lint.EventTypeViewerRequest,
+		frontier.TemplateABCookie:        lint.EventTypeViewerRequest,
+	}
+	if len(frontier.AvailableTemplates()) != len(eventTypes) {
+		t.Errorf("available templates: %v", frontier.AvailableTemplates())
+	}
+	for _, template := range frontier.AvailableTemplates() {
+		t.Run(string(template), func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			if deadline, ok := t.Deadline(); ok {
+				ctx, cancel = context.WithDeadline(ctx, deadline)
+			}
+			defer cancel()
+
+			dir := t.TempDir()
+			result, err := frontier.NewInitializer().Init(ctx, dir, template)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result.ConfigPath != filepath.Join(dir, "function.yml") || result.CodePath != filepath.Join(dir, "fn.js") {
+				t.Errorf("unexpected paths: %#v", result)
+			}
+			if len(result.TestEvents) == 0 || len(result.TestEvents) != len(result.ExpectedOutputs) {
+				t.Fatalf("events and expected outputs: %v, %v", result.TestEvents, result.ExpectedOutputs)
+			}
+
+			fn, err := frontier.ParseConfigFromPath(result.ConfigPath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := &frontier.Function{
+				Name:       string(template),
+				Code:       &frontier.FunctionCode{Path: result.CodePath},
+				Config:     &frontier.FunctionConfig{Comment: fn.Config.Comment, Runtime: types.FunctionRuntimeCloudfrontJs20},
+				TestEvents: result.TestEvents,
+				Lint:       &lint.Config{EventType: eventTypes[template]},
+			}
+			if diff := cmp.Diff(want, fn); diff != "" {
+				t.Errorf("config (-want, +got):\n%s", diff)
+			}
+
+			for i, eventPath := range result.TestEvents {
+				if filepath.Base(eventPath) != filepath.Base(result.ExpectedOutputs[i]) {
+					t.Errorf("event %s does not match the expected output %s", eventPath, result.ExpectedOutputs[i])
+				}
+				var event struct {
+					Context struct {
+						EventType lint.EventType `json:"eventType"`
+					} `json:"context"`
+				}
+				decodeJSONFile(t, eventPath, &event)
+				if event.Context.EventType != eventTypes[template] {
+					t.Errorf("event type of %s: %q", eventPath, event.Context.EventType)
+				}
+				var output map[string]any
+				decodeJSONFile(t, result.ExpectedOutputs[i], &output)
+			}
+
+			report, err := frontier.NewLinter().Lint(ctx, result.ConfigPath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(report.Findings) > 0 {
+				t.Errorf("findings: %#v", report.Findings)
+			}
+		})
+	}
+}
+
+func TestInitializer_Init(t *testing.T) {
+	testCases := []struct {
+		name     string
+		template frontier.Template
+		opts     []frontier.InitOption
+		existing string
+		want     *frontier.Function
+		wantErr  error
+	}{
+		{
+			name:     "name and runtime",
+			template: frontier.TemplateSPARewrite,
+			opts:     []frontier.InitOption{frontier.InitFunctionName("my-spa"), frontier.InitRuntime(types.FunctionRuntimeCloudfrontJs10)},
+			want: &frontier.Function{
+				Name:       "my-spa",
+				Code:       &frontier.FunctionCode{Path: "fn.js"},
+				Config:     &frontier.FunctionConfig{Comment: "rewrite the routes of the single page application to /index.html", Runtime: types.FunctionRuntimeCloudfrontJs10},
+				TestEvents: []string{"events/asset.json", "events/route.json"},
+				Lint:       &lint.Config{EventType: lint.EventTypeViewerRequest},
+			},
+		},
+		{
+			name:     "existing files",
+			template: frontier.TemplateRedirect,
+			existing: "fn.js",
+			wantErr:  fs.ErrExist,
+		},
+		{
+			name:     "existing files overwritten",
+			template: frontier.TemplateRedirect,
+			opts:     []frontier.InitOption{frontier.InitOverwrite(true)},
+			existing: "function.yml",
+			want: &frontier.Function{
+				Name:       "redirect",
+				Code:       &frontier.FunctionCode{Path: "fn.js"},
+				Config:     &frontier.FunctionConfig{Comment: "redirect the old paths to the new locations", Runtime: types.FunctionRuntimeCloudfrontJs20},
+				TestEvents: []string{"events/passthrough.json", "events/redirected.json"},
+				Lint:       &lint.Config{EventType: lint.EventTypeViewerRequest},
+			},
+		},
+		{
+			name:     "unknown template",
+			template: "no-such-template",
+			wantErr:  &frontier.UnknownTemplateError{Name: "no-such-template"},
+		},
+		{
+			name:     "unknown runtime",
+			template: frontier.TemplateRedirect,
+			opts:     []frontier.InitOption{frontier.InitRuntime("cloudfront-js-0.1")},
+			wantErr:  &frontier.InvalidConfigError{Field: "config.runtime", Reason: `unknown runtime "cloudfront-js-0.1"`},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			if deadline, ok := t.Deadline(); ok {
+				ctx, cancel = context.WithDeadline(ctx, deadline)
+			}
+			defer cancel()
+
+			dir := t.TempDir()
+			if tc.existing != "" {
+				if err := os.WriteFile(filepath.Join(dir, tc.existing), []byte("existing"), 0600); err != nil {
+					t.Fatal(err)
+				}
+			}
+			result, err := frontier.NewInitializer().Init(ctx, dir, tc.template, tc.opts...)
+			if !errors.Is(err, tc.wantErr) {
+				t.Errorf("error:\n\twant: %v\n\t got: %v", tc.wantErr, err)
+			}
+			if tc.want == nil {
+				if tc.existing != "" {
+					if got, _ := os.ReadFile(filepath.Join(dir, tc.existing)); string(got) != "existing" {
+						t.Errorf("existing file is overwritten: %q", got)
+					}
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := frontier.ParseConfigFromPath(result.ConfigPath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			// the paths in the temporary directory are replaced to compare
+			got.Code.Path, _ = filepath.Rel(dir, got.Code.Path)
+			for i, p := range got.TestEvents {
+				rel, _ := filepath.Rel(dir, p)
+				got.TestEvents[i] = filepath.ToSlash(rel)
+			}
+			if diff := cmp.Diff(tc.want, got, cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("config (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func decodeJSONFile(t *testing.T, path string, v any) {
+	t.Helper()
+	body, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(body, v); err != nil {
+		t.Fatalf("%s: %s", path, err)
+	}
+}
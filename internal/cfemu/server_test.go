@@ -0,0 +1,180 @@
+package cfemu_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aereal/frontier/internal/cfemu"
+	"github.com/aws/aws-sdk-go-v2/service/cloudfront"
+	"github.com/aws/aws-sdk-go-v2/service/cloudfront/types"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestServer_functions(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	if deadline, ok := t.Deadline(); ok {
+		ctx, cancel = context.WithDeadline(ctx, deadline)
+	}
+	defer cancel()
+
+	now := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	srv := cfemu.NewServer(cfemu.Clock(func() time.Time { return now }))
+	defer srv.Close()
+	client := srv.CloudFrontClient()
+
+	created, err := client.CreateFunction(ctx, &cloudfront.CreateFunctionInput{
+		Name:         ref("test-fn"),
+		FunctionCode: []byte("function handler(event) { return event.request }"),
+		FunctionConfig: &types.FunctionConfig{
+			Comment: ref("v1"),
+			Runtime: types.FunctionRuntimeCloudfrontJs20,
+			KeyValueStoreAssociations: &types.KeyValueStoreAssociations{
+				Quantity: ref(int32(1)),
+				Items:    []types.KeyValueStoreAssociation{{KeyValueStoreARN: ref("arn:aws:cloudfront::123456789012:key-value-store/kvs-1")}},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("CreateFunction: %+v", err)
+	}
+	wantSummary := &types.FunctionSummary{
+		Name:   ref("test-fn"),
+		Status: ref("UNPUBLISHED"),
+		FunctionConfig: &types.FunctionConfig{
+			Comment: ref("v1"),
+			Runtime: types.FunctionRuntimeCloudfrontJs20,
+			KeyValueStoreAssociations: &types.KeyValueStoreAssociations{
+				Quantity: ref(int32(1)),
+				Items:    []types.KeyValueStoreAssociation{{KeyValueStoreARN: ref("arn:aws:cloudfront::123456789012:key-value-store/kvs-1")}},
+			},
+		},
+		FunctionMetadata: &types.FunctionMetadata{
+			FunctionARN:      ref("arn:aws:cloudfront::123456789012:function/test-fn"),
+			Stage:            types.FunctionStageDevelopment,
+			CreatedTime:      &now,
+			LastModifiedTime: &now,
+		},
+	}
+	if diff := cmp.Diff(wantSummary, created.FunctionSummary, cmpOpts...); diff != "" {
+		t.Errorf("CreateFunction summary (-want, +got):\n%s", diff)
+	}
+	if _, err := client.CreateFunction(ctx, &cloudfront.CreateFunctionInput{Name: ref("test-fn"), FunctionCode: []byte("x"), FunctionConfig: &types.FunctionConfig{Comment: ref(""), Runtime: types.FunctionRuntimeCloudfrontJs20}}); !errors.As(err, new(*types.FunctionAlreadyExists)) {
+		t.Errorf("CreateFunction twice: want FunctionAlreadyExists, got %v", err)
+	}
+
+	if _, err := client.GetFunction(ctx, &cloudfront.GetFunctionInput{Name: ref("test-fn"), Stage: types.FunctionStageLive}); !errors.As(err, new(*types.NoSuchFunctionExists)) {
+		t.Errorf("GetFunction(LIVE) before publish: want NoSuchFunctionExists, got %v", err)
+	}
+	if _, err := client.UpdateFunction(ctx, &cloudfront.UpdateFunctionInput{Name: ref("test-fn"), IfMatch: ref("stale"), FunctionCode: []byte("v2"), FunctionConfig: &types.FunctionConfig{Comment: ref("v2"), Runtime: types.FunctionRuntimeCloudfrontJs20}}); !errors.As(err, new(*types.PreconditionFailed)) {
+		t.Errorf("UpdateFunction with stale ETag: want PreconditionFailed, got %v", err)
+	}
+	updated, err := client.UpdateFunction(ctx, &cloudfront.UpdateFunctionInput{Name: ref("test-fn"), IfMatch: created.ETag, FunctionCode: []byte("v2"), FunctionConfig: &types.FunctionConfig{Comment: ref("v2"), Runtime: types.FunctionRuntimeCloudfrontJs20}})
+	if err != nil {
+		t.Fatalf("UpdateFunction: %+v", err)
+	}
+	if updated.ETag == nil || *updated.ETag == *created.ETag {
+		t.Errorf("UpdateFunction must return new ETag: %v", updated.ETag)
+	}
+	if _, err := client.PublishFunction(ctx, &cloudfront.PublishFunctionInput{Name: ref("test-fn"), IfMatch: created.ETag}); !errors.As(err, new(*types.PreconditionFailed)) {
+		t.Errorf("PublishFunction with stale ETag: want PreconditionFailed, got %v", err)
+	}
+	published, err := client.PublishFunction(ctx, &cloudfront.PublishFunctionInput{Name: ref("test-fn"), IfMatch: updated.ETag})
+	if err != nil {
+		t.Fatalf("PublishFunction: %+v", err)
+	}
+	if got := published.FunctionSummary.FunctionMetadata.Stage; got != types.FunctionStageLive {
+		t.Errorf("PublishFunction stage: want LIVE, got %s", got)
+	}
+
+	live, err := client.GetFunction(ctx, &cloudfront.GetFunctionInput{Name: ref("test-fn"), Stage: types.FunctionStageLive})
+	if err != nil {
+		t.Fatalf("GetFunction(LIVE): %+v", err)
+	}
+	if got := string(live.FunctionCode); got != "v2" {
+		t.Errorf("live code: want v2, got %q", got)
+	}
+	described, err := client.DescribeFunction(ctx, &cloudfront.DescribeFunctionInput{Name: ref("test-fn"), Stage: types.FunctionStageLive})
+	if err != nil {
+		t.Fatalf("DescribeFunction(LIVE): %+v", err)
+	}
+	if diff := cmp.Diff(live.ETag, described.ETag); diff != "" {
+		t.Errorf("ETag of LIVE stage (-GetFunction, +DescribeFunction):\n%s", diff)
+	}
+	if got := *described.FunctionSummary.FunctionConfig.Comment; got != "v2" {
+		t.Errorf("live comment: want v2, got %q", got)
+	}
+}
+
+func TestServer_ListDistributions(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	if deadline, ok := t.Deadline(); ok {
+		ctx, cancel = context.WithDeadline(ctx, deadline)
+	}
+	defer cancel()
+
+	dists := []types.DistributionSummary{distribution("dist-1"), distribution("dist-2"), distribution("dist-3")}
+	srv := cfemu.NewServer(cfemu.Distributions(dists...))
+	defer srv.Close()
+	client := srv.CloudFrontClient()
+
+	var got []types.DistributionSummary
+	paginator := cloudfront.NewListDistributionsPaginator(client, &cloudfront.ListDistributionsInput{MaxItems: ref(int32(2))})
+	pages := 0
+	for paginator.HasMorePages() {
+		out, err := paginator.NextPage(ctx)
+		if err != nil {
+			t.Fatalf("ListDistributions: %+v", err)
+		}
+		pages++
+		got = append(got, out.DistributionList.Items...)
+	}
+	if pages != 2 {
+		t.Errorf("pages: want 2, got %d", pages)
+	}
+	if diff := cmp.Diff(dists, got, cmpOpts...); diff != "" {
+		t.Errorf("distributions (-want, +got):\n%s", diff)
+	}
+}
+
+var cmpOpts = []cmp.Option{
+	cmp.FilterPath(func(p cmp.Path) bool {
+		sf, ok := p.Last().(cmp.StructField)
+		return ok && sf.Name() == "noSmithyDocumentSerde"
+	}, cmp.Ignore()),
+}
+
+func distribution(id string) types.DistributionSummary {
+	return types.DistributionSummary{
+		Id:         ref(id),
+		ARN:        ref("arn:aws:cloudfront::123456789012:distribution/" + id),
+		DomainName: ref(id + ".test"),
+		Comment:    ref(""),
+		Enabled:    ref(true),
+		Staging:    ref(false),
+		Status:     ref("Deployed"),
+		DefaultCacheBehavior: &types.DefaultCacheBehavior{
+			TargetOriginId: ref("origin_1"),
+			CachePolicyId:  ref("default_policy_1"),
+			FunctionAssociations: &types.FunctionAssociations{
+				Quantity: ref(int32(1)),
+				Items: []types.FunctionAssociation{
+					{FunctionARN: ref("arn:aws:cloudfront::123456789012:function/test-fn"), EventType: types.EventTypeViewerRequest},
+				},
+			},
+		},
+		CacheBehaviors: &types.CacheBehaviors{
+			Quantity: ref(int32(1)),
+			Items: []types.CacheBehavior{
+				{
+					PathPattern:    ref("/assets/*"),
+					TargetOriginId: ref("origin_2"),
+					CachePolicyId:  ref("policy_1"),
+				},
+			},
+		},
+	}
+}
+
+func ref[T any](v T) *T { return &v }
@@ -0,0 +1,158 @@
+package cfemu
+
+import (
+	"encoding/xml"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudfront/types"
+)
+
+type xmlCreateFunctionRequest struct {
+	XMLName        xml.Name          `xml:"CreateFunctionRequest"`
+	Name           string            `xml:"Name"`
+	FunctionCode   string            `xml:"FunctionCode"`
+	FunctionConfig xmlFunctionConfig `xml:"FunctionConfig"`
+}
+
+type xmlUpdateFunctionRequest struct {
+	XMLName        xml.Name          `xml:"UpdateFunctionRequest"`
+	FunctionCode   string            `xml:"FunctionCode"`
+	FunctionConfig xmlFunctionConfig `xml:"FunctionConfig"`
+}
+
+type xmlFunctionConfig struct {
+	Comment                   string                        `xml:"Comment"`
+	Runtime                   string                        `xml:"Runtime"`
+	KeyValueStoreAssociations *xmlKeyValueStoreAssociations `xml:"KeyValueStoreAssociations,omitempty"`
+}
+
+type xmlKeyValueStoreAssociations struct {
+	Quantity int                           `xml:"Quantity"`
+	Items    []xmlKeyValueStoreAssociation `xml:"Items>KeyValueStoreAssociation"`
+}
+
+type xmlKeyValueStoreAssociation struct {
+	KeyValueStoreARN string `xml:"KeyValueStoreARN"`
+}
+
+type xmlFunctionSummary struct {
+	XMLName          xml.Name            `xml:"FunctionSummary"`
+	Xmlns            string              `xml:"xmlns,attr"`
+	Name             string              `xml:"Name"`
+	Status           string              `xml:"Status"`
+	FunctionConfig   xmlFunctionConfig   `xml:"FunctionConfig"`
+	FunctionMetadata xmlFunctionMetadata `xml:"FunctionMetadata"`
+}
+
+type xmlFunctionMetadata struct {
+	FunctionARN      string `xml:"FunctionARN"`
+	Stage            string `xml:"Stage"`
+	CreatedTime      string `xml:"CreatedTime"`
+	LastModifiedTime string `xml:"LastModifiedTime"`
+}
+
+type xmlDistributionList struct {
+	XMLName     xml.Name                 `xml:"DistributionList"`
+	Xmlns       string                   `xml:"xmlns,attr"`
+	Marker      string                   `xml:"Marker"`
+	NextMarker  string                   `xml:"NextMarker,omitempty"`
+	MaxItems    int                      `xml:"MaxItems"`
+	IsTruncated bool                     `xml:"IsTruncated"`
+	Quantity    int                      `xml:"Quantity"`
+	Items       []xmlDistributionSummary `xml:"Items>DistributionSummary"`
+}
+
+type xmlDistributionSummary struct {
+	ID                   string            `xml:"Id"`
+	ARN                  string            `xml:"ARN"`
+	Status               string            `xml:"Status"`
+	LastModifiedTime     string            `xml:"LastModifiedTime,omitempty"`
+	DomainName           string            `xml:"DomainName"`
+	DefaultCacheBehavior *xmlCacheBehavior `xml:"DefaultCacheBehavior,omitempty"`
+	CacheBehaviors       xmlCacheBehaviors `xml:"CacheBehaviors"`
+	Comment              string            `xml:"Comment"`
+	Enabled              bool              `xml:"Enabled"`
+	Staging              bool              `xml:"Staging"`
+}
+
+type xmlCacheBehaviors struct {
+	Quantity int                `xml:"Quantity"`
+	Items    []xmlCacheBehavior `xml:"Items>CacheBehavior"`
+}
+
+type xmlCacheBehavior struct {
+	PathPattern          string                   `xml:"PathPattern,omitempty"`
+	TargetOriginID       string                   `xml:"TargetOriginId"`
+	CachePolicyID        string                   `xml:"CachePolicyId,omitempty"`
+	FunctionAssociations *xmlFunctionAssociations `xml:"FunctionAssociations,omitempty"`
+}
+
+type xmlFunctionAssociations struct {
+	Quantity int                      `xml:"Quantity"`
+	Items    []xmlFunctionAssociation `xml:"Items>FunctionAssociation"`
+}
+
+type xmlFunctionAssociation struct {
+	FunctionARN string `xml:"FunctionARN"`
+	EventType   string `xml:"EventType"`
+}
+
+type xmlErrorResponse struct {
+	XMLName   xml.Name `xml:"ErrorResponse"`
+	Error     xmlError `xml:"Error"`
+	RequestID string   `xml:"RequestId"`
+}
+
+type xmlError struct {
+	Type    string `xml:"Type"`
+	Code    string `xml:"Code"`
+	Message string `xml:"Message"`
+}
+
+func toXMLDistributionSummary(dist types.DistributionSummary) xmlDistributionSummary {
+	ret := xmlDistributionSummary{
+		ID:         aws.ToString(dist.Id),
+		ARN:        aws.ToString(dist.ARN),
+		Status:     aws.ToString(dist.Status),
+		DomainName: aws.ToString(dist.DomainName),
+		Comment:    aws.ToString(dist.Comment),
+		Enabled:    aws.ToBool(dist.Enabled),
+		Staging:    aws.ToBool(dist.Staging),
+	}
+	if dist.LastModifiedTime != nil {
+		ret.LastModifiedTime = formatTime(*dist.LastModifiedTime)
+	}
+	if cb := dist.DefaultCacheBehavior; cb != nil {
+		ret.DefaultCacheBehavior = &xmlCacheBehavior{
+			TargetOriginID:       aws.ToString(cb.TargetOriginId),
+			CachePolicyID:        aws.ToString(cb.CachePolicyId),
+			FunctionAssociations: toXMLFunctionAssociations(cb.FunctionAssociations),
+		}
+	}
+	if dist.CacheBehaviors != nil {
+		for _, cb := range dist.CacheBehaviors.Items {
+			ret.CacheBehaviors.Items = append(ret.CacheBehaviors.Items, xmlCacheBehavior{
+				PathPattern:          aws.ToString(cb.PathPattern),
+				TargetOriginID:       aws.ToString(cb.TargetOriginId),
+				CachePolicyID:        aws.ToString(cb.CachePolicyId),
+				FunctionAssociations: toXMLFunctionAssociations(cb.FunctionAssociations),
+			})
+		}
+	}
+	ret.CacheBehaviors.Quantity = len(ret.CacheBehaviors.Items)
+	return ret
+}
+
+func toXMLFunctionAssociations(associations *types.FunctionAssociations) *xmlFunctionAssociations {
+	if associations == nil {
+		return nil
+	}
+	ret := &xmlFunctionAssociations{Quantity: len(associations.Items)}
+	for _, a := range associations.Items {
+		ret.Items = append(ret.Items, xmlFunctionAssociation{
+			FunctionARN: aws.ToString(a.FunctionARN),
+			EventType:   string(a.EventType),
+		})
+	}
+	return ret
+}
@@ -36,6 +36,94 @@ func (m *MockCloudFrontClient) EXPECT() *MockCloudFrontClientMockRecorder {
 	return m.recorder
 }
 
+// CopyDistribution mocks base method.
+func (m *MockCloudFrontClient) CopyDistribution(ctx context.Context, params *cloudfront.CopyDistributionInput, optFns ...func(*cloudfront.Options)) (*cloudfront.CopyDistributionOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "CopyDistribution", varargs...)
+	ret0, _ := ret[0].(*cloudfront.CopyDistributionOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CopyDistribution indicates an expected call of CopyDistribution.
+func (mr *MockCloudFrontClientMockRecorder) CopyDistribution(ctx, params any, optFns ...any) *MockCloudFrontClientCopyDistributionCall {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, params}, optFns...)
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CopyDistribution", reflect.TypeOf((*MockCloudFrontClient)(nil).CopyDistribution), varargs...)
+	return &MockCloudFrontClientCopyDistributionCall{Call: call}
+}
+
+// MockCloudFrontClientCopyDistributionCall wrap *gomock.Call
+type MockCloudFrontClientCopyDistributionCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockCloudFrontClientCopyDistributionCall) Return(arg0 *cloudfront.CopyDistributionOutput, arg1 error) *MockCloudFrontClientCopyDistributionCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockCloudFrontClientCopyDistributionCall) Do(f func(context.Context, *cloudfront.CopyDistributionInput, ...func(*cloudfront.Options)) (*cloudfront.CopyDistributionOutput, error)) *MockCloudFrontClientCopyDistributionCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockCloudFrontClientCopyDistributionCall) DoAndReturn(f func(context.Context, *cloudfront.CopyDistributionInput, ...func(*cloudfront.Options)) (*cloudfront.CopyDistributionOutput, error)) *MockCloudFrontClientCopyDistributionCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// CreateContinuousDeploymentPolicy mocks base method.
+func (m *MockCloudFrontClient) CreateContinuousDeploymentPolicy(ctx context.Context, params *cloudfront.CreateContinuousDeploymentPolicyInput, optFns ...func(*cloudfront.Options)) (*cloudfront.CreateContinuousDeploymentPolicyOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "CreateContinuousDeploymentPolicy", varargs...)
+	ret0, _ := ret[0].(*cloudfront.CreateContinuousDeploymentPolicyOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateContinuousDeploymentPolicy indicates an expected call of CreateContinuousDeploymentPolicy.
+func (mr *MockCloudFrontClientMockRecorder) CreateContinuousDeploymentPolicy(ctx, params any, optFns ...any) *MockCloudFrontClientCreateContinuousDeploymentPolicyCall {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, params}, optFns...)
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateContinuousDeploymentPolicy", reflect.TypeOf((*MockCloudFrontClient)(nil).CreateContinuousDeploymentPolicy), varargs...)
+	return &MockCloudFrontClientCreateContinuousDeploymentPolicyCall{Call: call}
+}
+
+// MockCloudFrontClientCreateContinuousDeploymentPolicyCall wrap *gomock.Call
+type MockCloudFrontClientCreateContinuousDeploymentPolicyCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockCloudFrontClientCreateContinuousDeploymentPolicyCall) Return(arg0 *cloudfront.CreateContinuousDeploymentPolicyOutput, arg1 error) *MockCloudFrontClientCreateContinuousDeploymentPolicyCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockCloudFrontClientCreateContinuousDeploymentPolicyCall) Do(f func(context.Context, *cloudfront.CreateContinuousDeploymentPolicyInput, ...func(*cloudfront.Options)) (*cloudfront.CreateContinuousDeploymentPolicyOutput, error)) *MockCloudFrontClientCreateContinuousDeploymentPolicyCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockCloudFrontClientCreateContinuousDeploymentPolicyCall) DoAndReturn(f func(context.Context, *cloudfront.CreateContinuousDeploymentPolicyInput, ...func(*cloudfront.Options)) (*cloudfront.CreateContinuousDeploymentPolicyOutput, error)) *MockCloudFrontClientCreateContinuousDeploymentPolicyCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
 // CreateFunction mocks base method.
 func (m *MockCloudFrontClient) CreateFunction(ctx context.Context, params *cloudfront.CreateFunctionInput, optFns ...func(*cloudfront.Options)) (*cloudfront.CreateFunctionOutput, error) {
 	m.ctrl.T.Helper()
@@ -124,6 +212,94 @@ func (c *MockCloudFrontClientDescribeFunctionCall) DoAndReturn(f func(context.Co
 	return c
 }
 
+// GetContinuousDeploymentPolicy mocks base method.
+func (m *MockCloudFrontClient) GetContinuousDeploymentPolicy(ctx context.Context, params *cloudfront.GetContinuousDeploymentPolicyInput, optFns ...func(*cloudfront.Options)) (*cloudfront.GetContinuousDeploymentPolicyOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetContinuousDeploymentPolicy", varargs...)
+	ret0, _ := ret[0].(*cloudfront.GetContinuousDeploymentPolicyOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetContinuousDeploymentPolicy indicates an expected call of GetContinuousDeploymentPolicy.
+func (mr *MockCloudFrontClientMockRecorder) GetContinuousDeploymentPolicy(ctx, params any, optFns ...any) *MockCloudFrontClientGetContinuousDeploymentPolicyCall {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, params}, optFns...)
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetContinuousDeploymentPolicy", reflect.TypeOf((*MockCloudFrontClient)(nil).GetContinuousDeploymentPolicy), varargs...)
+	return &MockCloudFrontClientGetContinuousDeploymentPolicyCall{Call: call}
+}
+
+// MockCloudFrontClientGetContinuousDeploymentPolicyCall wrap *gomock.Call
+type MockCloudFrontClientGetContinuousDeploymentPolicyCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockCloudFrontClientGetContinuousDeploymentPolicyCall) Return(arg0 *cloudfront.GetContinuousDeploymentPolicyOutput, arg1 error) *MockCloudFrontClientGetContinuousDeploymentPolicyCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockCloudFrontClientGetContinuousDeploymentPolicyCall) Do(f func(context.Context, *cloudfront.GetContinuousDeploymentPolicyInput, ...func(*cloudfront.Options)) (*cloudfront.GetContinuousDeploymentPolicyOutput, error)) *MockCloudFrontClientGetContinuousDeploymentPolicyCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockCloudFrontClientGetContinuousDeploymentPolicyCall) DoAndReturn(f func(context.Context, *cloudfront.GetContinuousDeploymentPolicyInput, ...func(*cloudfront.Options)) (*cloudfront.GetContinuousDeploymentPolicyOutput, error)) *MockCloudFrontClientGetContinuousDeploymentPolicyCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// GetDistributionConfig mocks base method.
+func (m *MockCloudFrontClient) GetDistributionConfig(ctx context.Context, params *cloudfront.GetDistributionConfigInput, optFns ...func(*cloudfront.Options)) (*cloudfront.GetDistributionConfigOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetDistributionConfig", varargs...)
+	ret0, _ := ret[0].(*cloudfront.GetDistributionConfigOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDistributionConfig indicates an expected call of GetDistributionConfig.
+func (mr *MockCloudFrontClientMockRecorder) GetDistributionConfig(ctx, params any, optFns ...any) *MockCloudFrontClientGetDistributionConfigCall {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, params}, optFns...)
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDistributionConfig", reflect.TypeOf((*MockCloudFrontClient)(nil).GetDistributionConfig), varargs...)
+	return &MockCloudFrontClientGetDistributionConfigCall{Call: call}
+}
+
+// MockCloudFrontClientGetDistributionConfigCall wrap *gomock.Call
+type MockCloudFrontClientGetDistributionConfigCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockCloudFrontClientGetDistributionConfigCall) Return(arg0 *cloudfront.GetDistributionConfigOutput, arg1 error) *MockCloudFrontClientGetDistributionConfigCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockCloudFrontClientGetDistributionConfigCall) Do(f func(context.Context, *cloudfront.GetDistributionConfigInput, ...func(*cloudfront.Options)) (*cloudfront.GetDistributionConfigOutput, error)) *MockCloudFrontClientGetDistributionConfigCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockCloudFrontClientGetDistributionConfigCall) DoAndReturn(f func(context.Context, *cloudfront.GetDistributionConfigInput, ...func(*cloudfront.Options)) (*cloudfront.GetDistributionConfigOutput, error)) *MockCloudFrontClientGetDistributionConfigCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
 // GetFunction mocks base method.
 func (m *MockCloudFrontClient) GetFunction(ctx context.Context, params *cloudfront.GetFunctionInput, optFns ...func(*cloudfront.Options)) (*cloudfront.GetFunctionOutput, error) {
 	m.ctrl.T.Helper()
@@ -256,6 +432,182 @@ func (c *MockCloudFrontClientPublishFunctionCall) DoAndReturn(f func(context.Con
 	return c
 }
 
+// TestFunction mocks base method.
+func (m *MockCloudFrontClient) TestFunction(ctx context.Context, params *cloudfront.TestFunctionInput, optFns ...func(*cloudfront.Options)) (*cloudfront.TestFunctionOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "TestFunction", varargs...)
+	ret0, _ := ret[0].(*cloudfront.TestFunctionOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TestFunction indicates an expected call of TestFunction.
+func (mr *MockCloudFrontClientMockRecorder) TestFunction(ctx, params any, optFns ...any) *MockCloudFrontClientTestFunctionCall {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, params}, optFns...)
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TestFunction", reflect.TypeOf((*MockCloudFrontClient)(nil).TestFunction), varargs...)
+	return &MockCloudFrontClientTestFunctionCall{Call: call}
+}
+
+// MockCloudFrontClientTestFunctionCall wrap *gomock.Call
+type MockCloudFrontClientTestFunctionCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockCloudFrontClientTestFunctionCall) Return(arg0 *cloudfront.TestFunctionOutput, arg1 error) *MockCloudFrontClientTestFunctionCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockCloudFrontClientTestFunctionCall) Do(f func(context.Context, *cloudfront.TestFunctionInput, ...func(*cloudfront.Options)) (*cloudfront.TestFunctionOutput, error)) *MockCloudFrontClientTestFunctionCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockCloudFrontClientTestFunctionCall) DoAndReturn(f func(context.Context, *cloudfront.TestFunctionInput, ...func(*cloudfront.Options)) (*cloudfront.TestFunctionOutput, error)) *MockCloudFrontClientTestFunctionCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// UpdateContinuousDeploymentPolicy mocks base method.
+func (m *MockCloudFrontClient) UpdateContinuousDeploymentPolicy(ctx context.Context, params *cloudfront.UpdateContinuousDeploymentPolicyInput, optFns ...func(*cloudfront.Options)) (*cloudfront.UpdateContinuousDeploymentPolicyOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "UpdateContinuousDeploymentPolicy", varargs...)
+	ret0, _ := ret[0].(*cloudfront.UpdateContinuousDeploymentPolicyOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateContinuousDeploymentPolicy indicates an expected call of UpdateContinuousDeploymentPolicy.
+func (mr *MockCloudFrontClientMockRecorder) UpdateContinuousDeploymentPolicy(ctx, params any, optFns ...any) *MockCloudFrontClientUpdateContinuousDeploymentPolicyCall {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, params}, optFns...)
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateContinuousDeploymentPolicy", reflect.TypeOf((*MockCloudFrontClient)(nil).UpdateContinuousDeploymentPolicy), varargs...)
+	return &MockCloudFrontClientUpdateContinuousDeploymentPolicyCall{Call: call}
+}
+
+// MockCloudFrontClientUpdateContinuousDeploymentPolicyCall wrap *gomock.Call
+type MockCloudFrontClientUpdateContinuousDeploymentPolicyCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockCloudFrontClientUpdateContinuousDeploymentPolicyCall) Return(arg0 *cloudfront.UpdateContinuousDeploymentPolicyOutput, arg1 error) *MockCloudFrontClientUpdateContinuousDeploymentPolicyCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockCloudFrontClientUpdateContinuousDeploymentPolicyCall) Do(f func(context.Context, *cloudfront.UpdateContinuousDeploymentPolicyInput, ...func(*cloudfront.Options)) (*cloudfront.UpdateContinuousDeploymentPolicyOutput, error)) *MockCloudFrontClientUpdateContinuousDeploymentPolicyCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockCloudFrontClientUpdateContinuousDeploymentPolicyCall) DoAndReturn(f func(context.Context, *cloudfront.UpdateContinuousDeploymentPolicyInput, ...func(*cloudfront.Options)) (*cloudfront.UpdateContinuousDeploymentPolicyOutput, error)) *MockCloudFrontClientUpdateContinuousDeploymentPolicyCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// UpdateDistribution mocks base method.
+func (m *MockCloudFrontClient) UpdateDistribution(ctx context.Context, params *cloudfront.UpdateDistributionInput, optFns ...func(*cloudfront.Options)) (*cloudfront.UpdateDistributionOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "UpdateDistribution", varargs...)
+	ret0, _ := ret[0].(*cloudfront.UpdateDistributionOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateDistribution indicates an expected call of UpdateDistribution.
+func (mr *MockCloudFrontClientMockRecorder) UpdateDistribution(ctx, params any, optFns ...any) *MockCloudFrontClientUpdateDistributionCall {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, params}, optFns...)
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateDistribution", reflect.TypeOf((*MockCloudFrontClient)(nil).UpdateDistribution), varargs...)
+	return &MockCloudFrontClientUpdateDistributionCall{Call: call}
+}
+
+// MockCloudFrontClientUpdateDistributionCall wrap *gomock.Call
+type MockCloudFrontClientUpdateDistributionCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockCloudFrontClientUpdateDistributionCall) Return(arg0 *cloudfront.UpdateDistributionOutput, arg1 error) *MockCloudFrontClientUpdateDistributionCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockCloudFrontClientUpdateDistributionCall) Do(f func(context.Context, *cloudfront.UpdateDistributionInput, ...func(*cloudfront.Options)) (*cloudfront.UpdateDistributionOutput, error)) *MockCloudFrontClientUpdateDistributionCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockCloudFrontClientUpdateDistributionCall) DoAndReturn(f func(context.Context, *cloudfront.UpdateDistributionInput, ...func(*cloudfront.Options)) (*cloudfront.UpdateDistributionOutput, error)) *MockCloudFrontClientUpdateDistributionCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// UpdateDistributionWithStagingConfig mocks base method.
+func (m *MockCloudFrontClient) UpdateDistributionWithStagingConfig(ctx context.Context, params *cloudfront.UpdateDistributionWithStagingConfigInput, optFns ...func(*cloudfront.Options)) (*cloudfront.UpdateDistributionWithStagingConfigOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "UpdateDistributionWithStagingConfig", varargs...)
+	ret0, _ := ret[0].(*cloudfront.UpdateDistributionWithStagingConfigOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateDistributionWithStagingConfig indicates an expected call of UpdateDistributionWithStagingConfig.
+func (mr *MockCloudFrontClientMockRecorder) UpdateDistributionWithStagingConfig(ctx, params any, optFns ...any) *MockCloudFrontClientUpdateDistributionWithStagingConfigCall {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, params}, optFns...)
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateDistributionWithStagingConfig", reflect.TypeOf((*MockCloudFrontClient)(nil).UpdateDistributionWithStagingConfig), varargs...)
+	return &MockCloudFrontClientUpdateDistributionWithStagingConfigCall{Call: call}
+}
+
+// MockCloudFrontClientUpdateDistributionWithStagingConfigCall wrap *gomock.Call
+type MockCloudFrontClientUpdateDistributionWithStagingConfigCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockCloudFrontClientUpdateDistributionWithStagingConfigCall) Return(arg0 *cloudfront.UpdateDistributionWithStagingConfigOutput, arg1 error) *MockCloudFrontClientUpdateDistributionWithStagingConfigCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockCloudFrontClientUpdateDistributionWithStagingConfigCall) Do(f func(context.Context, *cloudfront.UpdateDistributionWithStagingConfigInput, ...func(*cloudfront.Options)) (*cloudfront.UpdateDistributionWithStagingConfigOutput, error)) *MockCloudFrontClientUpdateDistributionWithStagingConfigCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockCloudFrontClientUpdateDistributionWithStagingConfigCall) DoAndReturn(f func(context.Context, *cloudfront.UpdateDistributionWithStagingConfigInput, ...func(*cloudfront.Options)) (*cloudfront.UpdateDistributionWithStagingConfigOutput, error)) *MockCloudFrontClientUpdateDistributionWithStagingConfigCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
 // UpdateFunction mocks base method.
 func (m *MockCloudFrontClient) UpdateFunction(ctx context.Context, params *cloudfront.UpdateFunctionInput, optFns ...func(*cloudfront.Options)) (*cloudfront.UpdateFunctionOutput, error) {
 	m.ctrl.T.Helper()
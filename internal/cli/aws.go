@@ -0,0 +1,80 @@
+package cli
+
+import (
+	"github.com/aereal/frontier/cf"
+	"github.com/urfave/cli/v3"
+)
+
+type SDKConfigurer interface {
+	ConfigureSDK(opts cf.SDKOptions)
+}
+
+const (
+	categoryAWS                   = "AWS"
+	flagNameAWSProfile            = "profile"
+	flagNameAWSRegion             = "region"
+	flagNameAssumeRoleARN         = "assume-role-arn"
+	flagNameAssumeRoleExternalID  = "assume-role-external-id"
+	flagNameAssumeRoleSessionName = "assume-role-session-name"
+	flagNameEndpointURL           = "endpoint-url"
+	flagNameRecordCassette        = "record-cassette"
+)
+
+func newAWSFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:     flagNameAWSProfile,
+			Usage:    "the shared config profile to use",
+			Sources:  cli.EnvVars("FRONTIER_PROFILE", "AWS_PROFILE"),
+			Category: categoryAWS,
+		},
+		&cli.StringFlag{
+			Name:     flagNameAWSRegion,
+			Usage:    "the region to send requests to",
+			Sources:  cli.EnvVars("FRONTIER_REGION", "AWS_REGION"),
+			Category: categoryAWS,
+		},
+		&cli.StringFlag{
+			Name:     flagNameAssumeRoleARN,
+			Usage:    "the ARN of the role to assume before calling APIs",
+			Sources:  cli.EnvVars("FRONTIER_ASSUME_ROLE_ARN"),
+			Category: categoryAWS,
+		},
+		&cli.StringFlag{
+			Name:     flagNameAssumeRoleExternalID,
+			Usage:    "the external ID passed on assuming the role",
+			Sources:  cli.EnvVars("FRONTIER_ASSUME_ROLE_EXTERNAL_ID"),
+			Category: categoryAWS,
+		},
+		&cli.StringFlag{
+			Name:     flagNameAssumeRoleSessionName,
+			Usage:    "the session name of the assumed role",
+			Sources:  cli.EnvVars("FRONTIER_ASSUME_ROLE_SESSION_NAME"),
+			Category: categoryAWS,
+		},
+		&cli.StringFlag{
+			Name:     flagNameEndpointURL,
+			Usage:    "the CloudFront API endpoint URL to use instead of the default one",
+			Sources:  cli.EnvVars("FRONTIER_ENDPOINT_URL", "AWS_ENDPOINT_URL_CLOUDFRONT"),
+			Category: categoryAWS,
+		},
+		&cli.StringFlag{
+			Name:     flagNameRecordCassette,
+			Usage:    "save CloudFront API requests and responses to the file with account IDs scrubbed to replay them in tests",
+			Sources:  cli.EnvVars("FRONTIER_RECORD_CASSETTE"),
+			Category: categoryAWS,
+		},
+	}
+}
+
+func getSDKOptions(cmd *cli.Command) cf.SDKOptions {
+	return cf.SDKOptions{
+		Profile:         cmd.String(flagNameAWSProfile),
+		Region:          cmd.String(flagNameAWSRegion),
+		AssumeRoleARN:   cmd.String(flagNameAssumeRoleARN),
+		ExternalID:      cmd.String(flagNameAssumeRoleExternalID),
+		RoleSessionName: cmd.String(flagNameAssumeRoleSessionName),
+		EndpointURL:     cmd.String(flagNameEndpointURL),
+		CassettePath:    cmd.String(flagNameRecordCassette),
+	}
+}
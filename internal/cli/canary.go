@@ -0,0 +1,177 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/aereal/frontier"
+	"github.com/urfave/cli/v3"
+)
+
+const (
+	maxCanaryWeight        = 0.15
+	canaryHeaderPrefix     = "aws-cf-cd-"
+	flagNameDistributionID = "distribution-id"
+)
+
+func (a *App) cmdCanary() *cli.Command {
+	return &cli.Command{
+		Name:        "canary",
+		Usage:       "release the function gradually with CloudFront continuous deployment",
+		Description: "deploy the function as the canary function associated with the staging distribution, and route the part of the traffic of the primary distribution to it.",
+		Commands: []*cli.Command{
+			a.cmdCanaryStart(),
+			a.cmdCanaryPromote(),
+			a.cmdCanaryAbort(),
+		},
+		Writer:    a.output,
+		ErrWriter: a.errOutput,
+		Reader:    a.input,
+	}
+}
+
+func (a *App) cmdCanaryStart() *cli.Command {
+	return &cli.Command{
+		Name:        "start",
+		Usage:       "deploy the canary function and start routing the traffic to it",
+		Description: "the staging distribution is copied from the primary distribution if it does not exist yet.",
+		Flags:       newCanaryFlags(),
+		MutuallyExclusiveFlags: []cli.MutuallyExclusiveFlags{
+			{
+				Category: "traffic",
+				Required: true,
+				Flags: [][]cli.Flag{
+					{
+						&cli.FloatFlag{
+							Name:  "weight",
+							Usage: fmt.Sprintf("route the share of the requests to the canary; greater than 0 and at most %s", strconv.FormatFloat(maxCanaryWeight, 'f', -1, 64)),
+						},
+					},
+					{
+						&cli.StringFlag{
+							Name:  "header",
+							Usage: "route the requests having the header to the canary; the value is name=value and the name must start with " + canaryHeaderPrefix,
+						},
+					},
+				},
+			},
+		},
+		Writer:    a.output,
+		ErrWriter: a.errOutput,
+		Reader:    a.input,
+		Action:    a.actionCanaryStart,
+	}
+}
+
+func (a *App) cmdCanaryPromote() *cli.Command {
+	return &cli.Command{
+		Name:        "promote",
+		Usage:       "publish the canary to the function and update the primary distribution with the staging distribution",
+		Description: "the continuous deployment policy is disabled after the primary distribution is updated.",
+		Flags:       newCanaryFlags(),
+		Writer:      a.output,
+		ErrWriter:   a.errOutput,
+		Reader:      a.input,
+		Action:      a.actionCanaryPromote,
+	}
+}
+
+func (a *App) cmdCanaryAbort() *cli.Command {
+	return &cli.Command{
+		Name:      "abort",
+		Usage:     "stop routing the traffic to the canary",
+		Flags:     newCanaryFlags(),
+		Writer:    a.output,
+		ErrWriter: a.errOutput,
+		Reader:    a.input,
+		Action:    a.actionCanaryAbort,
+	}
+}
+
+func newCanaryFlags() []cli.Flag {
+	return []cli.Flag{
+		flagConfigPath,
+		&cli.StringFlag{
+			Name:     flagNameDistributionID,
+			Usage:    "the ID of the primary distribution that the function is associated with",
+			Required: true,
+		},
+		&cli.StringFlag{
+			Name:  "canary-name",
+			Usage: "the name of the canary function. defaults to the function name suffixed with -canary.",
+		},
+		newResultOutputFlag(),
+	}
+}
+
+func (a *App) actionCanaryStart(ctx context.Context, cmd *cli.Command) error {
+	traffic, err := getCanaryTraffic(cmd)
+	if err != nil {
+		return err
+	}
+	result, err := a.controllers.StartCanary(ctx, cmd.String(flagConfigPath.Name), cmd.String(flagNameDistributionID), traffic, getCanaryOptions(cmd)...)
+	if err != nil {
+		return err
+	}
+	return presentResult(cmd, result)
+}
+
+func (a *App) actionCanaryPromote(ctx context.Context, cmd *cli.Command) error {
+	result, err := a.controllers.PromoteCanary(ctx, cmd.String(flagConfigPath.Name), cmd.String(flagNameDistributionID), getCanaryOptions(cmd)...)
+	if err != nil {
+		return err
+	}
+	return presentResult(cmd, result)
+}
+
+func (a *App) actionCanaryAbort(ctx context.Context, cmd *cli.Command) error {
+	result, err := a.controllers.AbortCanary(ctx, cmd.String(flagConfigPath.Name), cmd.String(flagNameDistributionID), getCanaryOptions(cmd)...)
+	if err != nil {
+		return err
+	}
+	return presentResult(cmd, result)
+}
+
+func getCanaryOptions(cmd *cli.Command) []frontier.CanaryOption {
+	var opts []frontier.CanaryOption
+	if name := cmd.String("canary-name"); name != "" {
+		opts = append(opts, frontier.CanaryFunctionName(name))
+	}
+	return opts
+}
+
+func getCanaryTraffic(cmd *cli.Command) (*frontier.CanaryTraffic, error) {
+	if cmd.IsSet("header") {
+		v := cmd.String("header")
+		name, value, ok := strings.Cut(v, "=")
+		if !ok || value == "" || !strings.HasPrefix(strings.ToLower(name), canaryHeaderPrefix) {
+			return nil, &InvalidCanaryTrafficError{Flag: "header", V: v}
+		}
+		return &frontier.CanaryTraffic{Header: name, HeaderValue: value}, nil
+	}
+	weight := cmd.Float("weight")
+	if weight <= 0 || weight > maxCanaryWeight {
+		return nil, &InvalidCanaryTrafficError{Flag: "weight", V: strconv.FormatFloat(weight, 'f', -1, 64)}
+	}
+	return &frontier.CanaryTraffic{Weight: float32(weight)}, nil
+}
+
+type InvalidCanaryTrafficError struct {
+	Flag string
+	V    string
+}
+
+func (e *InvalidCanaryTrafficError) Error() string {
+	return fmt.Sprintf("invalid %s: %q", e.Flag, e.V)
+}
+
+func (e *InvalidCanaryTrafficError) Is(other error) bool {
+	otherErr := new(InvalidCanaryTrafficError)
+	if !errors.As(other, &otherErr) {
+		return false
+	}
+	return *otherErr == *e
+}
@@ -1,45 +1,96 @@
-//go:generate go run go.uber.org/mock/mockgen -build_constraint !live -typed -write_command_comment=false -write_package_comment=false -write_source_comment=false -package cli -destination ./mock_gen.go github.com/aereal/frontier/internal/cli DeployController,ImportController,RenderController,ListDistributionsController,FunctionARNResolver
+//go:generate go run go.uber.org/mock/mockgen -build_constraint !live -typed -write_command_comment=false -write_package_comment=false -write_source_comment=false -package cli -destination ./mock_gen.go github.com/aereal/frontier/internal/cli DeployController,UnlockController,ImportController,RenderController,ExportController,DriftController,ListDistributionsController,WatchController,CompareController,PromoteController,CanaryController,StatsController,LintController,InitController,SDKConfigurer,FunctionARNResolver
 
 package cli
 
 import (
 	"context"
 	"io"
+	"iter"
 	"log/slog"
 	"path/filepath"
+	"slices"
 
 	"github.com/aereal/frontier"
 	"github.com/aereal/frontier/controller/listdist"
-	"github.com/aereal/frontier/internal/fnarn"
+	"github.com/aereal/frontier/fnarn"
 	cli "github.com/urfave/cli/v3"
 	"go.opentelemetry.io/otel"
-	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
-	"go.opentelemetry.io/otel/sdk/resource"
-	sdktrace "go.opentelemetry.io/otel/sdk/trace"
-	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
 )
 
 type ImportController interface {
-	Import(ctx context.Context, functionName string, configStream io.Writer, functionStream *frontier.WritableFile) error
+	Import(ctx context.Context, functionName string, configStream io.Writer, functionStream *frontier.WritableFile, opts ...frontier.ImportOption) (*frontier.ImportResult, error)
 }
 
 type DeployController interface {
-	Deploy(ctx context.Context, configPath string, publish bool) error
+	Deploy(ctx context.Context, configPath string, publish bool, opts ...frontier.DeployOption) (*frontier.DeployResult, error)
+}
+
+type UnlockController interface {
+	Unlock(ctx context.Context, functionName string) error
 }
 
 type RenderController interface {
 	Render(ctx context.Context, configPath string, output io.Writer) error
 }
 
+type ExportController interface {
+	Export(ctx context.Context, configPath string, format frontier.ExportFormat, output io.Writer) error
+}
+
+type DriftController interface {
+	DetectDrift(ctx context.Context, configPath string, opts ...frontier.DriftOption) (*frontier.DriftReport, error)
+}
+
+type CompareController interface {
+	Compare(ctx context.Context, a, b *frontier.CompareTarget) (*frontier.CompareResult, error)
+}
+
+type PromoteController interface {
+	Promote(ctx context.Context, source, target *frontier.PromoteEndpoint, publish bool, opts ...frontier.DeployOption) (*frontier.PromoteResult, error)
+}
+
+type CanaryController interface {
+	StartCanary(ctx context.Context, configPath, distributionID string, traffic *frontier.CanaryTraffic, opts ...frontier.CanaryOption) (*frontier.CanaryResult, error)
+	PromoteCanary(ctx context.Context, configPath, distributionID string, opts ...frontier.CanaryOption) (*frontier.CanaryResult, error)
+	AbortCanary(ctx context.Context, configPath, distributionID string, opts ...frontier.CanaryOption) (*frontier.CanaryResult, error)
+}
+
+type StatsController interface {
+	Stats(ctx context.Context, configPath string, opts ...frontier.StatsOption) (*frontier.FunctionStats, error)
+}
+
+type LintController interface {
+	Lint(ctx context.Context, configPath string, opts ...frontier.LintOption) (*frontier.LintReport, error)
+}
+
+type InitController interface {
+	Init(ctx context.Context, dir string, template frontier.Template, opts ...frontier.InitOption) (*frontier.InitResult, error)
+}
+
+type WatchController interface {
+	Watch(ctx context.Context, configPath string, opts ...frontier.WatchOption) iter.Seq2[*frontier.WatchResult, error]
+}
+
 type ListDistributionsController interface {
-	ListDistributions(ctx context.Context, output io.Writer, criteria *listdist.Criteria) ([]frontier.FunctionAssociation, error)
+	ListDistributions(ctx context.Context, output io.Writer, criteria *listdist.Criteria, opts ...listdist.ListDistributionsOption) iter.Seq2[frontier.FunctionAssociation, error]
 }
 
 type Controllers struct {
 	ImportController
 	DeployController
+	UnlockController
 	RenderController
+	ExportController
+	DriftController
 	ListDistributionsController
+	WatchController
+	CompareController
+	PromoteController
+	CanaryController
+	StatsController
+	LintController
+	InitController
+	SDKConfigurer
 }
 
 type FunctionARNResolver interface {
@@ -64,47 +115,73 @@ type App struct {
 	controllers   Controllers
 	shouldPublish bool
 	arnResolver   FunctionARNResolver
+	errorFormat   string
 }
 
 func (a *App) Run(ctx context.Context, args []string) error {
 	rootCmdName := filepath.Base(args[0])
+	// the flags and the commands are built on each run because a flag holds the value given in the previous run,
+	// which would leak into the next run of the same App.
 	cmd := &cli.Command{
-		Name:      rootCmdName,
-		Reader:    a.input,
-		Writer:    a.output,
-		ErrWriter: a.errOutput,
-		Flags: []cli.Flag{
-			flagOtelTraceEndpoint,
-			flagLogLevel,
-		},
-		Before: a.onBefore,
-		After:  a.onAfter,
+		Name:         rootCmdName,
+		Reader:       a.input,
+		Writer:       a.output,
+		ErrWriter:    a.errOutput,
+		Flags:        slices.Concat(newLogFlags(), newErrorFlags(), newTelemetryFlags(), newAWSFlags()),
+		Before:       a.onBefore,
+		After:        a.onAfter,
+		OnUsageError: onUsageError,
 		Commands: []*cli.Command{
 			a.cmdRender(),
 			a.cmdDeploy(),
+			a.cmdUnlock(),
 			a.cmdImport(),
+			a.cmdExport(),
+			a.cmdDrift(),
 			a.cmdDist(),
+			a.cmdWatch(),
+			a.cmdCompare(),
+			a.cmdPromote(),
+			a.cmdCanary(),
+			a.cmdStats(),
+			a.cmdLint(),
+			a.cmdInit(),
 		},
 	}
 	for _, c := range cmd.Commands {
 		instrumentTrace(c)
+		setOnUsageError(c)
 	}
 	return cmd.Run(ctx, args)
 }
 
-func (a *App) onBefore(ctx context.Context, cmd *cli.Command) (context.Context, error) {
-	if level := getLogLevel(cmd); level != slog.LevelInfo {
-		swapLogger(func(prev *slog.Logger) *slog.Logger {
-			lh := &leveledHandler{
-				level:   level,
-				Handler: prev.Handler(),
-			}
-			return slog.New(lh)
-		})
+// Execute runs the command, prints the error in the format given by --error-format and returns the exit code.
+func (a *App) Execute(ctx context.Context, args []string) int {
+	err := a.Run(ctx, args)
+	if err == nil {
+		return 0
+	}
+	a.reportError(ctx, err)
+	return ExitCodeOf(err)
+}
+
+func setOnUsageError(cmd *cli.Command) {
+	cmd.OnUsageError = onUsageError
+	for _, c := range cmd.Commands {
+		setOnUsageError(c)
 	}
-	if err := a.configureTracerProvider(ctx, cmd); err != nil {
+}
+
+func (a *App) onBefore(ctx context.Context, cmd *cli.Command) (context.Context, error) {
+	slog.SetDefault(slog.New(newLogHandler(a.errOutput, cmd.String(flagNameLogFormat), getLogLevel(cmd))))
+	a.errorFormat = cmd.String(flagNameErrorFormat)
+	ctx, err := a.configureTelemetry(ctx, cmd)
+	if err != nil {
 		return nil, err
 	}
+	if a.controllers.SDKConfigurer != nil {
+		a.controllers.SDKConfigurer.ConfigureSDK(getSDKOptions(cmd))
+	}
 	return ctx, nil
 }
 
@@ -114,33 +191,11 @@ func (a *App) onAfter(ctx context.Context, _ *cli.Command) error {
 			slog.WarnContext(ctx, "failed to shutdown tracer provider", slog.String("error", err.Error()))
 		}
 	}
-	return nil
-}
-
-func (a *App) configureTracerProvider(ctx context.Context, cmd *cli.Command) error { //nolint:unparam
-	endpoint := cmd.String(flagOtelTraceEndpoint.Name)
-	if endpoint == "" {
-		return nil
-	}
-
-	slog.InfoContext(ctx, "set OTel trace endpoint", slog.String("endpoint", endpoint))
-
-	exporter, err := otlptracegrpc.New(ctx, otlptracegrpc.WithInsecure(), otlptracegrpc.WithEndpoint(endpoint))
-	if err != nil {
-		slog.WarnContext(ctx, "failed to setup otlptracegrpc", slog.String("error", err.Error()))
-		return nil
+	if mp, ok := otel.GetMeterProvider().(interface{ Shutdown(context.Context) error }); ok {
+		if err := mp.Shutdown(ctx); err != nil {
+			slog.WarnContext(ctx, "failed to shutdown meter provider", slog.String("error", err.Error()))
+		}
 	}
-
-	res := resource.NewWithAttributes(
-		semconv.SchemaURL,
-		semconv.ServiceName(cmd.Name),
-		semconv.ServiceVersion(cmd.Version),
-	)
-	tp := sdktrace.NewTracerProvider(
-		sdktrace.WithBatcher(exporter),
-		sdktrace.WithResource(res),
-	)
-	otel.SetTracerProvider(tp)
 	return nil
 }
 
@@ -150,13 +205,4 @@ var (
 		Usage: "config file path",
 		Value: "function.yml",
 	}
-	flagOtelTraceEndpoint = &cli.StringFlag{
-		Name:  "otel-trace-endpoint",
-		Usage: "an endpoint (such as localhost:4317) to send OpenTelemetry traces. an empty value indicates no trace should be sent.",
-	}
-	flagLogLevel = &cli.GenericFlag{
-		Name:  "log-level",
-		Usage: "specify minimum log level. accepts valid [slog.Level] string representation.",
-		Value: &logLevel{slog.LevelInfo},
-	}
 )
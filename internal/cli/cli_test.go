@@ -3,25 +3,37 @@ package cli_test
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"io"
+	"io/fs"
+	"iter"
+	"log/slog"
 	"os"
 	"path/filepath"
 	"strconv"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/aereal/frontier"
+	"github.com/aereal/frontier/cf"
 	"github.com/aereal/frontier/controller/listdist"
+	"github.com/aereal/frontier/fnarn"
 	"github.com/aereal/frontier/internal/cli"
-	"github.com/aereal/frontier/internal/fnarn"
 	"github.com/aereal/frontier/internal/testexpectations"
+	"github.com/aereal/frontier/lint"
+	"github.com/aereal/frontier/presenter/sarif"
+	"github.com/aws/aws-sdk-go-v2/service/cloudfront/types"
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
 	gomock "go.uber.org/mock/gomock"
 )
 
 var (
+	errOops                      = errors.New("oops")
 	fnNameDerivedFromConfig      = "test-func"
 	fnArnDerivedFromConfig       = "arn:aws:cloudfront::123456789012:function/" + fnNameDerivedFromConfig
 	associationDerivedFromConfig = frontier.FunctionAssociation{
@@ -54,7 +66,7 @@ func TestApp_Run(t *testing.T) {
 			expectDeploy: func(m *mockWithLogger[*cli.MockDeployController]) {
 				m.M.EXPECT().
 					Deploy(gomock.Any(), configPath, true).
-					Return(nil).
+					Return(nil, nil).
 					Times(1)
 			},
 		},
@@ -63,7 +75,7 @@ func TestApp_Run(t *testing.T) {
 			expectDeploy: func(m *mockWithLogger[*cli.MockDeployController]) {
 				m.M.EXPECT().
 					Deploy(gomock.Any(), configPath, false).
-					Return(nil).
+					Return(nil, nil).
 					Times(1)
 			},
 		},
@@ -72,6 +84,80 @@ func TestApp_Run(t *testing.T) {
 			expectDeploy: func(m *mockWithLogger[*cli.MockDeployController]) {
 				m.M.EXPECT().
 					Deploy(gomock.Any(), configPath, true).
+					Return(nil, nil).
+					Times(1)
+			},
+		},
+		{
+			args: []string{"deploy", "--config", configPath, "--lock-wait", "1m"},
+			expectDeploy: func(m *mockWithLogger[*cli.MockDeployController]) {
+				m.M.EXPECT().
+					Deploy(gomock.Any(), configPath, true, frontier.LockWait(time.Minute)).
+					Return(nil, nil).
+					Times(1)
+			},
+		},
+		{
+			args: []string{"deploy", "--config", configPath, "--output", "json"},
+			expectDeploy: func(m *mockWithLogger[*cli.MockDeployController]) {
+				m.M.EXPECT().
+					Deploy(gomock.Any(), configPath, true).
+					Return(&frontier.DeployResult{FunctionName: "test-func", Action: frontier.DeployActionUpdated, ETagBefore: "etag-1", ETagAfter: "etag-2", Published: true, Stage: types.FunctionStageLive}, nil).
+					Times(1)
+			},
+			expect: testSubommandExpectation{
+				stdout: `{"FunctionName":"test-func","FunctionARN":"","Action":"updated","ETagBefore":"etag-1","ETagAfter":"etag-2","CodeSHA256":"","Published":true,"Stage":"LIVE","Duration":0}` + "\n",
+			},
+		},
+		{
+			args: []string{"deploy", "--config", configPath, "--output", "json"},
+			expectDeploy: func(m *mockWithLogger[*cli.MockDeployController]) {
+				m.M.EXPECT().
+					Deploy(gomock.Any(), configPath, true).
+					Return(&frontier.DeployResult{FunctionName: "test-func", Action: frontier.DeployActionUpdated, ETagBefore: "etag-1", ETagAfter: "etag-2", Published: true, Stage: types.FunctionStageLive}, &frontier.HookError{Phase: frontier.HookPhasePostDeploy, Command: "false"}).
+					Times(1)
+			},
+			expect: testSubommandExpectation{
+				err:    &frontier.HookError{Phase: frontier.HookPhasePostDeploy, Command: "false"},
+				stdout: `{"FunctionName":"test-func","FunctionARN":"","Action":"updated","ETagBefore":"etag-1","ETagAfter":"etag-2","CodeSHA256":"","Published":true,"Stage":"LIVE","Duration":0}` + "\n",
+			},
+		},
+		{
+			args: []string{"watch", "--config", configPath, "--test-event", "event.json", "--interval", "1s"},
+			expectWatch: func(m *mockWithLogger[*cli.MockWatchController]) {
+				m.M.EXPECT().
+					Watch(gomock.Any(), configPath, frontier.TestEvents("event.json"), frontier.WatchInterval(time.Second)).
+					Return(seqOf([]*frontier.WatchResult{
+						{
+							Deploy: &frontier.DeployResult{FunctionName: "test-func", Action: frontier.DeployActionUpdated, ETagBefore: "etag-1", ETagAfter: "etag-2"},
+							Tests:  []*frontier.FunctionTestResult{{EventPath: "event.json", ComputeUtilization: "12"}},
+						},
+						{
+							ChangedPaths: []string{"fn.js"},
+							Deploy:       &frontier.DeployResult{FunctionName: "test-func", Action: frontier.DeployActionUpdated, ETagBefore: "etag-2", ETagAfter: "etag-3"},
+							Tests:        []*frontier.FunctionTestResult{{EventPath: "event.json", ComputeUtilization: "30", ErrorMessage: "oops"}},
+						},
+					}, errOops)).
+					Times(1)
+			},
+			expect: testSubommandExpectation{
+				stdout: "updated test-func: ETag etag-1 -> etag-2 (0s)\n  test event.json: compute utilization 12\nupdated test-func by fn.js: ETag etag-2 -> etag-3 (0s)\n  test event.json: compute utilization 30, error: oops\n",
+			},
+		},
+		{
+			args: []string{"unlock", "--name", "test-fn"},
+			expectUnlock: func(m *mockWithLogger[*cli.MockUnlockController]) {
+				m.M.EXPECT().
+					Unlock(gomock.Any(), "test-fn").
+					Return(nil).
+					Times(1)
+			},
+		},
+		{
+			args: []string{"unlock", "--config", configPath},
+			expectUnlock: func(m *mockWithLogger[*cli.MockUnlockController]) {
+				m.M.EXPECT().
+					Unlock(gomock.Any(), fnNameDerivedFromConfig).
 					Return(nil).
 					Times(1)
 			},
@@ -88,6 +174,12 @@ func TestApp_Run(t *testing.T) {
 			args:   []string{"import", "--config", configPath, "--name", "test-fn", "--function-path", ""},
 			expect: testSubommandExpectation{err: cli.ErrFunctionPathRequired},
 		},
+		{
+			args: []string{"import", "--name", "test-fn", "--stage", "STAGING"},
+			expect: testSubommandExpectation{
+				err: &literalError{`invalid value "STAGING" for flag -stage: invalid stage: "STAGING"`},
+			},
+		},
 		{
 			args:   []string{"import", "--name", "test-fn", "--config", ""},
 			expect: testSubommandExpectation{err: cli.ErrConfigPathRequired},
@@ -101,6 +193,35 @@ func TestApp_Run(t *testing.T) {
 					Times(1)
 			},
 		},
+		{
+			args: []string{
+				"--profile", "test-profile",
+				"--region", "ap-northeast-1",
+				"--assume-role-arn", "arn:aws:iam::123456789012:role/deployer",
+				"--assume-role-external-id", "external-id",
+				"--assume-role-session-name", "frontier-test",
+				"--endpoint-url", "http://localhost:4566",
+				"render", "--config", configPath,
+			},
+			expectSDKConfigurer: func(m *mockWithLogger[*cli.MockSDKConfigurer]) {
+				m.M.EXPECT().
+					ConfigureSDK(cf.SDKOptions{
+						Profile:         "test-profile",
+						Region:          "ap-northeast-1",
+						AssumeRoleARN:   "arn:aws:iam::123456789012:role/deployer",
+						ExternalID:      "external-id",
+						RoleSessionName: "frontier-test",
+						EndpointURL:     "http://localhost:4566",
+					}).
+					Times(1)
+			},
+			expectRender: func(m *mockWithLogger[*cli.MockRenderController]) {
+				m.M.EXPECT().
+					Render(gomock.Any(), configPath, gomock.Any()).
+					Return(nil).
+					Times(1)
+			},
+		},
 		{
 			args: []string{"--log-level", "DEBUG", "render", "--config", configPath},
 			expectRender: func(m *mockWithLogger[*cli.MockRenderController]) {
@@ -110,6 +231,89 @@ func TestApp_Run(t *testing.T) {
 					Times(1)
 			},
 		},
+		{
+			args: []string{"--log-format", "yaml", "render", "--config", configPath},
+			expect: testSubommandExpectation{
+				err: &literalError{`invalid value "yaml" for flag -log-format: invalid log format: "yaml"`},
+			},
+		},
+		{
+			args: []string{"--otel-exporter", "zipkin", "render", "--config", configPath},
+			expect: testSubommandExpectation{
+				err: &literalError{`invalid value "zipkin" for flag -otel-exporter: invalid otel-exporter: "zipkin"`},
+			},
+		},
+		{
+			args: []string{"--otel-sampling-ratio", "1.5", "render", "--config", configPath},
+			expect: testSubommandExpectation{
+				err: &literalError{`invalid value "1.5" for flag -otel-sampling-ratio: invalid otel-sampling-ratio: "1.5"`},
+			},
+		},
+		{
+			args: []string{"export", "--config", configPath},
+			expectExport: func(m *mockWithLogger[*cli.MockExportController]) {
+				m.M.EXPECT().
+					Export(gomock.Any(), configPath, frontier.ExportFormatCloudFormation, gomock.Any()).
+					DoAndReturn(func(_ context.Context, _ string, _ frontier.ExportFormat, w io.Writer) error {
+						_, err := io.WriteString(w, "exported\n")
+						return err
+					}).
+					Times(1)
+			},
+			expect: testSubommandExpectation{stdout: "exported\n"},
+		},
+		{
+			args: []string{"export", "--config", configPath, "--format", "terraform"},
+			expectExport: func(m *mockWithLogger[*cli.MockExportController]) {
+				m.M.EXPECT().
+					Export(gomock.Any(), configPath, frontier.ExportFormatTerraform, gomock.Any()).
+					Return(nil).
+					Times(1)
+			},
+		},
+		{
+			args: []string{"export", "--config", configPath, "--format", "pulumi"},
+			expect: testSubommandExpectation{
+				err: &literalError{`invalid value "pulumi" for flag -format: invalid export format: "pulumi"`},
+			},
+		},
+		{
+			args: []string{"drift", "--config", configPath},
+			expectDrift: func(m *mockWithLogger[*cli.MockDriftController]) {
+				m.M.EXPECT().
+					DetectDrift(gomock.Any(), configPath).
+					Return(&frontier.DriftReport{FunctionName: fnNameDerivedFromConfig, Status: frontier.DriftStatusDevAhead}, nil).
+					Times(1)
+			},
+		},
+		{
+			args: []string{"drift", "--config", configPath, "--fail-on-drift"},
+			expectDrift: func(m *mockWithLogger[*cli.MockDriftController]) {
+				m.M.EXPECT().
+					DetectDrift(gomock.Any(), configPath).
+					Return(&frontier.DriftReport{FunctionName: fnNameDerivedFromConfig, Status: frontier.DriftStatusLiveModified}, nil).
+					Times(1)
+			},
+			expect: testSubommandExpectation{err: cli.ErrDriftDetected},
+		},
+		{
+			args: []string{"drift", "--config", configPath, "--fail-on-drift"},
+			expectDrift: func(m *mockWithLogger[*cli.MockDriftController]) {
+				m.M.EXPECT().
+					DetectDrift(gomock.Any(), configPath).
+					Return(&frontier.DriftReport{FunctionName: fnNameDerivedFromConfig, Status: frontier.DriftStatusInSync}, nil).
+					Times(1)
+			},
+		},
+		{
+			args: []string{"drift", "--dir", testdataDir},
+			expectDrift: func(m *mockWithLogger[*cli.MockDriftController]) {
+				m.M.EXPECT().
+					DetectDrift(gomock.Any(), filepath.Join(testdataDir, "functions", "function.yml"), frontier.DriftCodePathRelativeToConfig(true)).
+					Return(&frontier.DriftReport{FunctionName: fnNameDerivedFromConfig, Status: frontier.DriftStatusInSync}, nil).
+					Times(1)
+			},
+		},
 		{
 			args: []string{"dist", "list"},
 			expectListDistributions: func(m *mockWithLogger[*cli.MockListDistributionsController]) {
@@ -118,7 +322,7 @@ func TestApp_Run(t *testing.T) {
 				}
 				m.M.EXPECT().
 					ListDistributions(gomock.Any(), gomock.Any(), listdist.NewCriteria()).
-					Return(out, nil).
+					Return(seqOf(out, nil)).
 					Times(1)
 			},
 		},
@@ -130,7 +334,7 @@ func TestApp_Run(t *testing.T) {
 				}
 				m.M.EXPECT().
 					ListDistributions(gomock.Any(), gomock.Any(), listdist.NewCriteria(listdist.EqualEventType("viewer-request"))).
-					Return(out, nil).
+					Return(seqOf(out, nil)).
 					Times(1)
 			},
 		},
@@ -142,7 +346,7 @@ func TestApp_Run(t *testing.T) {
 				}
 				m.M.EXPECT().
 					ListDistributions(gomock.Any(), gomock.Any(), listdist.NewCriteria(listdist.EqualFunctionArn(testexpectations.FunctionArn))).
-					Return(out, nil).
+					Return(seqOf(out, nil)).
 					Times(1)
 			},
 		},
@@ -160,7 +364,7 @@ func TestApp_Run(t *testing.T) {
 				}
 				m.M.EXPECT().
 					ListDistributions(gomock.Any(), gomock.Any(), listdist.NewCriteria(listdist.EqualFunctionArn(testexpectations.FunctionArn))).
-					Return(out, nil).
+					Return(seqOf(out, nil)).
 					Times(1)
 			},
 		},
@@ -186,7 +390,7 @@ func TestApp_Run(t *testing.T) {
 				out := []frontier.FunctionAssociation{associationDerivedFromConfig}
 				m.M.EXPECT().
 					ListDistributions(gomock.Any(), gomock.Any(), listdist.NewCriteria(listdist.EqualFunctionArn(fnArnDerivedFromConfig))).
-					Return(out, nil).
+					Return(seqOf(out, nil)).
 					Times(1)
 			},
 		},
@@ -200,9 +404,37 @@ func TestApp_Run(t *testing.T) {
 			},
 			expect: testSubommandExpectation{err: &literalError{"oops"}},
 		},
+		{
+			args: []string{"dist", "list", "--cache-ttl", "10m", "--refresh"},
+			expectListDistributions: func(m *mockWithLogger[*cli.MockListDistributionsController]) {
+				out := []frontier.FunctionAssociation{
+					testexpectations.FunctionAssociatedInDefaultCacheBehavior,
+				}
+				m.M.EXPECT().
+					ListDistributions(gomock.Any(), gomock.Any(), listdist.NewCriteria(), listdist.CacheTTL(10*time.Minute), listdist.RefreshCache(true)).
+					Return(seqOf(out, nil)).
+					Times(1)
+			},
+		},
+		{
+			args:   []string{"dist", "list", "--refresh"},
+			expect: testSubommandExpectation{err: cli.ErrRefreshWithoutCache},
+		},
+		{
+			args: []string{"dist", "list", "--limit", "1"},
+			expectListDistributions: func(m *mockWithLogger[*cli.MockListDistributionsController]) {
+				out := []frontier.FunctionAssociation{
+					testexpectations.FunctionAssociatedInDefaultCacheBehavior,
+				}
+				m.M.EXPECT().
+					ListDistributions(gomock.Any(), gomock.Any(), listdist.NewCriteria(), listdist.Limit(1)).
+					Return(seqOf(out, nil)).
+					Times(1)
+			},
+		},
 		{
 			args:   []string{"dist", "list", "--config", "not_found.yml", "--current"},
-			expect: testSubommandExpectation{&literalError{"os.Open: open not_found.yml: no such file or directory"}},
+			expect: testSubommandExpectation{err: &literalError{"os.Open: open not_found.yml: no such file or directory"}},
 		},
 		{
 			args: []string{"dist", "list", "--format", "unknown"},
@@ -215,7 +447,7 @@ func TestApp_Run(t *testing.T) {
 			expectListDistributions: func(m *mockWithLogger[*cli.MockListDistributionsController]) {
 				m.M.EXPECT().
 					ListDistributions(gomock.Any(), gomock.Any(), gomock.Any()).
-					Return(nil, &literalError{"oops"}).
+					Return(seqOf[frontier.FunctionAssociation](nil, &literalError{"oops"})).
 					Times(1)
 			},
 			expect: testSubommandExpectation{
@@ -234,7 +466,7 @@ func TestApp_Run(t *testing.T) {
 func TestApp_Run_import(t *testing.T) {
 	wantFunctionBody := "console.log(1);\n"
 	wantConfig := "name: test-fn\ncode:\n  path: imported.js\n"
-	tmpDir := "../../testdata/tmp"
+	tmpDir := t.TempDir()
 	importedConfigPath := filepath.Join(tmpDir, "imported.yml")
 	importedFunctionPath := filepath.Join(tmpDir, "imported.js")
 	args := testSubcommandArgs{
@@ -242,10 +474,10 @@ func TestApp_Run_import(t *testing.T) {
 		expectImport: func(m *mockWithLogger[*cli.MockImportController]) {
 			m.M.EXPECT().
 				Import(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
-				DoAndReturn(func(_ context.Context, functionName string, configOutput io.Writer, functionFile *frontier.WritableFile) error {
+				DoAndReturn(func(_ context.Context, functionName string, configOutput io.Writer, functionFile *frontier.WritableFile, _ ...frontier.ImportOption) (*frontier.ImportResult, error) {
 					fmt.Fprint(functionFile.Writer, wantFunctionBody)
 					fmt.Fprint(configOutput, wantConfig)
-					return nil
+					return &frontier.ImportResult{FunctionName: functionName, CodePath: functionFile.FilePath}, nil
 				}).
 				Times(1)
 		},
@@ -262,6 +494,685 @@ func TestApp_Run_import(t *testing.T) {
 	}
 }
 
+func TestApp_Run_telemetry(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	if deadline, ok := t.Deadline(); ok {
+		ctx, cancel = context.WithDeadline(ctx, deadline)
+	}
+	defer cancel()
+
+	prevTP := otel.GetTracerProvider()
+	prevMP := otel.GetMeterProvider()
+	t.Cleanup(func() {
+		otel.SetTracerProvider(prevTP)
+		otel.SetMeterProvider(prevMP)
+	})
+	traceID := "0af7651916cd43dd8448eb211c80319c"
+	t.Setenv("TRACEPARENT", "00-"+traceID+"-b7ad6b7169203331-01")
+
+	configPath := "../../testdata/config.yml"
+	ctrl := gomock.NewController(t)
+	renderCtrl := cli.NewMockRenderController(ctrl)
+	var gotTraceID string
+	renderCtrl.EXPECT().
+		Render(gomock.Any(), configPath, gomock.Any()).
+		DoAndReturn(func(ctx context.Context, _ string, _ io.Writer) error {
+			gotTraceID = trace.SpanContextFromContext(ctx).TraceID().String()
+			return nil
+		}).
+		Times(1)
+	stderr := new(bytes.Buffer)
+	app := cli.New(new(bytes.Buffer), new(bytes.Buffer), stderr, cli.Controllers{RenderController: renderCtrl}, cli.NewMockFunctionARNResolver(ctrl))
+	if err := app.Run(ctx, []string{"frontier", "--otel-exporter", "stdout", "render", "--config", configPath}); err != nil {
+		t.Fatal(err)
+	}
+	if gotTraceID != traceID {
+		t.Errorf("trace ID: want=%s got=%s", traceID, gotTraceID)
+	}
+	for _, want := range []string{`"Name":"cli.frontier render"`, `"TraceID":"` + traceID + `"`} {
+		if !strings.Contains(stderr.String(), want) {
+			t.Errorf("stderr does not contain %s:\n%s", want, stderr.String())
+		}
+	}
+}
+
+func TestApp_Run_logFormat(t *testing.T) {
+	traceID := "0af7651916cd43dd8448eb211c80319c"
+	spanID := "b7ad6b7169203331"
+	t.Setenv("TRACEPARENT", "00-"+traceID+"-"+spanID+"-01")
+	configPath := "../../testdata/config.yml"
+	testCases := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{
+			name: "json",
+			args: []string{"--log-format", "json"},
+			want: `"level":"INFO","msg":"rendered","path":"` + configPath + `","trace_id":"` + traceID + `","span_id":"` + spanID + `"}`,
+		},
+		{
+			name: "text",
+			args: []string{"--log-format", "text"},
+			want: "level=INFO msg=rendered path=" + configPath + " trace_id=" + traceID + " span_id=" + spanID + "\n",
+		},
+		{
+			name: "pretty",
+			args: []string{"--log-format", "pretty"},
+			want: "INFO  rendered path=" + configPath + " trace_id=" + traceID + " span_id=" + spanID + "\n",
+		},
+		{
+			name: "not a terminal",
+			want: `"level":"INFO","msg":"rendered"`,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			if deadline, ok := t.Deadline(); ok {
+				ctx, cancel = context.WithDeadline(ctx, deadline)
+			}
+			defer cancel()
+
+			ctrl := gomock.NewController(t)
+			renderCtrl := cli.NewMockRenderController(ctrl)
+			renderCtrl.EXPECT().
+				Render(gomock.Any(), configPath, gomock.Any()).
+				DoAndReturn(func(ctx context.Context, configPath string, _ io.Writer) error {
+					slog.InfoContext(ctx, "rendered", slog.String("path", configPath))
+					return nil
+				}).
+				Times(1)
+			stderr := new(bytes.Buffer)
+			app := cli.New(new(bytes.Buffer), new(bytes.Buffer), stderr, cli.Controllers{RenderController: renderCtrl}, cli.NewMockFunctionARNResolver(ctrl))
+			args := append(append([]string{"frontier"}, tc.args...), "render", "--config", configPath)
+			if err := app.Run(ctx, args); err != nil {
+				t.Fatal(err)
+			}
+			if !strings.Contains(stderr.String(), tc.want) {
+				t.Errorf("stderr does not contain %s:\n%s", tc.want, stderr.String())
+			}
+		})
+	}
+}
+
+func TestApp_Run_importLiveStage(t *testing.T) {
+	tmpDir := t.TempDir()
+	args := testSubcommandArgs{
+		args: []string{"import", "--config", filepath.Join(tmpDir, "imported.yml"), "--name", "test-fn", "--function-path", filepath.Join(tmpDir, "imported.js"), "--stage", "LIVE", "--with-associations"},
+		expectImport: func(m *mockWithLogger[*cli.MockImportController]) {
+			m.M.EXPECT().
+				Import(gomock.Any(), "test-fn", gomock.Any(), gomock.Any(), frontier.ImportStage(types.FunctionStageLive), gomock.Any()).
+				Return(&frontier.ImportResult{FunctionName: "test-fn"}, nil).
+				Times(1)
+		},
+	}
+	testSubcommand(t, args)
+}
+
+func TestApp_Run_importOutput(t *testing.T) {
+	const (
+		existingBody   = "existing\n"
+		importedBody   = "console.log(1);\n"
+		importedConfig = "name: test-fn\n"
+	)
+	asGiven := func(functionPath string) string { return functionPath }
+	importArgs := func(configPath, functionPath string, extra ...string) []string {
+		return append([]string{"import", "--config", configPath, "--name", "test-fn", "--function-path", functionPath}, extra...)
+	}
+	testCases := []struct {
+		name         string
+		args         func(configPath, functionPath, dir string) []string
+		existing     bool
+		imported     bool
+		importErr    error
+		wantCodePath func(functionPath string) string
+		wantErr      error
+		wantStdout   string
+		wantConfig   string
+		wantFunction string
+	}{
+		{
+			name:         "refuse to overwrite",
+			args:         func(configPath, functionPath, _ string) []string { return importArgs(configPath, functionPath) },
+			existing:     true,
+			wantErr:      &cli.FileExistsError{},
+			wantConfig:   existingBody,
+			wantFunction: existingBody,
+		},
+		{
+			name: "force",
+			args: func(configPath, functionPath, _ string) []string {
+				return importArgs(configPath, functionPath, "--force")
+			},
+			existing:     true,
+			imported:     true,
+			wantCodePath: asGiven,
+			wantConfig:   importedConfig,
+			wantFunction: importedBody,
+		},
+		{
+			name:         "failed import",
+			args:         func(configPath, functionPath, _ string) []string { return importArgs(configPath, functionPath) },
+			imported:     true,
+			importErr:    &literalError{"oops"},
+			wantCodePath: asGiven,
+			wantErr:      &literalError{"oops"},
+		},
+		{
+			name:         "config to stdout",
+			args:         func(_, functionPath, _ string) []string { return importArgs("-", functionPath) },
+			imported:     true,
+			wantCodePath: asGiven,
+			wantStdout:   importedConfig,
+			wantFunction: importedBody,
+		},
+		{
+			name:         "function to stdout",
+			args:         func(configPath, _, _ string) []string { return importArgs(configPath, "-") },
+			imported:     true,
+			wantCodePath: func(string) string { return "" },
+			wantStdout:   importedBody,
+			wantConfig:   importedConfig,
+		},
+		{
+			name:    "both to stdout",
+			args:    func(_, _, _ string) []string { return importArgs("-", "-") },
+			wantErr: cli.ErrBothStdout,
+		},
+		{
+			name: "relative to",
+			args: func(configPath, functionPath, dir string) []string {
+				return importArgs(configPath, functionPath, "--relative-to", filepath.Join(dir, "conf"))
+			},
+			imported:     true,
+			wantCodePath: func(string) string { return "../src/fn.js" },
+			wantConfig:   importedConfig,
+			wantFunction: importedBody,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			for _, dir := range []string{"conf", "src"} {
+				if err := os.Mkdir(filepath.Join(tmpDir, dir), 0o755); err != nil {
+					t.Fatal(err)
+				}
+			}
+			configPath := filepath.Join(tmpDir, "conf", "function.yml")
+			functionPath := filepath.Join(tmpDir, "src", "fn.js")
+			if tc.existing {
+				for _, p := range []string{configPath, functionPath} {
+					if err := os.WriteFile(p, []byte(existingBody), 0o600); err != nil {
+						t.Fatal(err)
+					}
+				}
+			}
+			wantErr := tc.wantErr
+			if existsErr := new(cli.FileExistsError); errors.As(wantErr, &existsErr) {
+				wantErr = &cli.FileExistsError{Path: functionPath}
+			}
+			var expectImport func(m *mockWithLogger[*cli.MockImportController])
+			if tc.imported {
+				expectImport = func(m *mockWithLogger[*cli.MockImportController]) {
+					m.M.EXPECT().
+						Import(gomock.Any(), "test-fn", gomock.Any(), gomock.Any()).
+						DoAndReturn(func(_ context.Context, functionName string, configOutput io.Writer, functionFile *frontier.WritableFile, _ ...frontier.ImportOption) (*frontier.ImportResult, error) {
+							if want := tc.wantCodePath(functionPath); functionFile.FilePath != want {
+								t.Errorf("code path: want=%q got=%q", want, functionFile.FilePath)
+							}
+							fmt.Fprint(functionFile.Writer, importedBody)
+							if tc.importErr != nil {
+								return nil, tc.importErr
+							}
+							fmt.Fprint(configOutput, importedConfig)
+							return &frontier.ImportResult{FunctionName: functionName}, nil
+						}).
+						Times(1)
+				}
+			}
+			testSubcommand(t, testSubcommandArgs{
+				args:         tc.args(configPath, functionPath, tmpDir),
+				expectImport: expectImport,
+				expect:       testSubommandExpectation{err: wantErr, stdout: tc.wantStdout},
+			})
+			for path, want := range map[string]string{configPath: tc.wantConfig, functionPath: tc.wantFunction} {
+				got, err := os.ReadFile(path)
+				if want == "" {
+					if !errors.Is(err, fs.ErrNotExist) {
+						t.Errorf("%s: want not exist got err=%v", path, err)
+					}
+					continue
+				}
+				if diff := cmp.Diff(want, string(got)); diff != "" {
+					t.Errorf("%s (-want, +got):\n%s", path, diff)
+				}
+			}
+			if tmpFiles, _ := filepath.Glob(filepath.Join(tmpDir, "*", "*.tmp")); len(tmpFiles) > 0 {
+				t.Errorf("temporary files left: %v", tmpFiles)
+			}
+		})
+	}
+}
+
+func TestApp_Run_compare(t *testing.T) {
+	tcs := []testSubcommandArgs{
+		{
+			args: []string{"compare", "test-fn-staging@live", "test-fn-prod@LIVE"},
+			expectCompare: func(m *mockWithLogger[*cli.MockCompareController]) {
+				m.M.EXPECT().
+					Compare(gomock.Any(),
+						&frontier.CompareTarget{FunctionName: "test-fn-staging", Stage: types.FunctionStageLive},
+						&frontier.CompareTarget{FunctionName: "test-fn-prod", Stage: types.FunctionStageLive}).
+					Return(&frontier.CompareResult{A: "test-fn-staging@LIVE", B: "test-fn-prod@LIVE", CodeDiff: "--- test-fn-staging@LIVE (code)\n+++ test-fn-prod@LIVE (code)\n"}, nil).
+					Times(1)
+			},
+			expect: testSubommandExpectation{stdout: "--- test-fn-staging@LIVE (code)\n+++ test-fn-prod@LIVE (code)\n"},
+		},
+		{
+			args: []string{"compare", "./function.yml", "test-fn"},
+			expectCompare: func(m *mockWithLogger[*cli.MockCompareController]) {
+				m.M.EXPECT().
+					Compare(gomock.Any(),
+						&frontier.CompareTarget{ConfigPath: "./function.yml"},
+						&frontier.CompareTarget{FunctionName: "test-fn", Stage: types.FunctionStageDevelopment}).
+					Return(&frontier.CompareResult{A: "./function.yml", B: "test-fn@DEVELOPMENT"}, nil).
+					Times(1)
+			},
+			expect: testSubommandExpectation{stdout: "./function.yml and test-fn@DEVELOPMENT are identical\n"},
+		},
+		{
+			args:   []string{"compare", "test-fn"},
+			expect: testSubommandExpectation{err: cli.ErrCompareTargetsRequired},
+		},
+		{
+			args:   []string{"compare", "test-fn@STAGING", "test-fn"},
+			expect: testSubommandExpectation{err: &cli.InvalidStageError{V: "STAGING"}},
+		},
+		{
+			args: []string{"compare", "test-fn", "test-fn@LIVE"},
+			expectCompare: func(m *mockWithLogger[*cli.MockCompareController]) {
+				m.M.EXPECT().
+					Compare(gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(nil, errOops).
+					Times(1)
+			},
+			expect: testSubommandExpectation{err: errOops},
+		},
+	}
+	for idx, tc := range tcs {
+		t.Run(strconv.Itoa(idx)+strings.Join(tc.args, " "), func(t *testing.T) {
+			testSubcommand(t, tc)
+		})
+	}
+}
+
+func TestApp_Run_drift_invalidConfigInDir(t *testing.T) {
+	dir := t.TempDir()
+	brokenPath := filepath.Join(dir, "broken", "function.yml")
+	okPath := filepath.Join(dir, "ok", "function.yml")
+	for _, p := range []string{brokenPath, okPath} {
+		if err := os.MkdirAll(filepath.Dir(p), 0o700); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte("name: test-func\n"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	invalidErr := &frontier.InvalidConfigError{Field: "code.path", Reason: "is required"}
+	testSubcommand(t, testSubcommandArgs{
+		args: []string{"drift", "--dir", dir},
+		expectDrift: func(m *mockWithLogger[*cli.MockDriftController]) {
+			m.M.EXPECT().
+				DetectDrift(gomock.Any(), brokenPath, frontier.DriftCodePathRelativeToConfig(true)).
+				Return(nil, invalidErr).
+				Times(1)
+			m.M.EXPECT().
+				DetectDrift(gomock.Any(), okPath, frontier.DriftCodePathRelativeToConfig(true)).
+				Return(&frontier.DriftReport{FunctionName: fnNameDerivedFromConfig, ConfigPath: okPath, Status: frontier.DriftStatusInSync}, nil).
+				Times(1)
+		},
+		expect: testSubommandExpectation{
+			err: invalidErr,
+			stdout: `{"FunctionName":"","ConfigPath":"` + brokenPath + `","Status":"","Local":null,"Development":null,"Live":null,"Error":"code.path: is required"}` + "\n" +
+				`{"FunctionName":"test-func","ConfigPath":"` + okPath + `","Status":"in-sync","Local":null,"Development":null,"Live":null}` + "\n",
+		},
+	})
+}
+
+func TestApp_Run_promote(t *testing.T) {
+	expectEndpoints := func(m *mockWithLogger[*cli.MockPromoteController], wantSource, wantTarget [2]string, wantPublish bool, result *frontier.PromoteResult) {
+		m.M.EXPECT().
+			Promote(gomock.Any(), gomock.Any(), gomock.Any(), wantPublish).
+			DoAndReturn(func(_ context.Context, source, target *frontier.PromoteEndpoint, _ bool, _ ...frontier.DeployOption) (*frontier.PromoteResult, error) {
+				for _, pair := range []struct {
+					want     [2]string
+					endpoint *frontier.PromoteEndpoint
+				}{{wantSource, source}, {wantTarget, target}} {
+					provider, ok := pair.endpoint.Provider.(*cf.SDKProvider)
+					if !ok {
+						return nil, &literalError{fmt.Sprintf("unexpected provider: %T", pair.endpoint.Provider)}
+					}
+					if got := [2]string{provider.Options.Profile, pair.endpoint.FunctionName}; got != pair.want {
+						return nil, &literalError{fmt.Sprintf("endpoint: want=%v got=%v", pair.want, got)}
+					}
+				}
+				return result, nil
+			}).
+			Times(1)
+	}
+	tcs := []testSubcommandArgs{
+		{
+			args: []string{"promote", "--from-profile", "stg", "--to-profile", "prod", "--name", "test-fn", "--publish", "--output", "json"},
+			expectPromote: func(m *mockWithLogger[*cli.MockPromoteController]) {
+				expectEndpoints(m, [2]string{"stg", "test-fn"}, [2]string{"prod", "test-fn"}, true, &frontier.PromoteResult{
+					SourceFunctionName: "test-fn",
+					SourceETag:         "E1",
+					DeployResult:       frontier.DeployResult{FunctionName: "test-fn", Action: frontier.DeployActionUpdated},
+				})
+			},
+			expect: testSubommandExpectation{
+				stdout: `{"FunctionName":"test-fn","FunctionARN":"","Action":"updated","ETagBefore":"","ETagAfter":"","CodeSHA256":"","Published":false,"Stage":"","Duration":0,"SourceFunctionName":"test-fn","SourceETag":"E1"}` + "\n",
+			},
+		},
+		{
+			args: []string{"--profile", "shared", "promote", "--name", "test-fn-staging", "--to-name", "test-fn-prod"},
+			expectPromote: func(m *mockWithLogger[*cli.MockPromoteController]) {
+				expectEndpoints(m, [2]string{"shared", "test-fn-staging"}, [2]string{"shared", "test-fn-prod"}, false, &frontier.PromoteResult{})
+			},
+		},
+		{
+			args:   []string{"promote", "--name", "test-fn"},
+			expect: testSubommandExpectation{err: cli.ErrPromoteToItself},
+		},
+		{
+			args: []string{"promote", "--from-profile", "stg", "--to-profile", "prod", "--name", "test-fn", "--key-value-store", "arn:stg:kvs-1=arn:prod:kvs-1", "--key-value-store", "arn:stg:kvs-2=arn:prod:kvs-2"},
+			expectPromote: func(m *mockWithLogger[*cli.MockPromoteController]) {
+				m.M.EXPECT().
+					Promote(gomock.Any(), gomock.Any(), gomock.Any(), false).
+					DoAndReturn(func(_ context.Context, _, target *frontier.PromoteEndpoint, _ bool, _ ...frontier.DeployOption) (*frontier.PromoteResult, error) {
+						want := map[string]string{"arn:stg:kvs-1": "arn:prod:kvs-1", "arn:stg:kvs-2": "arn:prod:kvs-2"}
+						if diff := cmp.Diff(want, target.KeyValueStoreARNs); diff != "" {
+							return nil, &literalError{fmt.Sprintf("key value stores (-want, +got):\n%s", diff)}
+						}
+						return &frontier.PromoteResult{}, nil
+					}).
+					Times(1)
+			},
+		},
+		{
+			args:   []string{"promote", "--from-profile", "stg", "--to-profile", "prod", "--name", "test-fn", "--key-value-store", "arn:stg:kvs-1"},
+			expect: testSubommandExpectation{err: &cli.InvalidKeyValueStoreMappingError{V: "arn:stg:kvs-1"}},
+		},
+		{
+			args: []string{"promote", "--from-profile", "stg", "--to-profile", "prod", "--name", "test-fn"},
+			expectPromote: func(m *mockWithLogger[*cli.MockPromoteController]) {
+				m.M.EXPECT().
+					Promote(gomock.Any(), gomock.Any(), gomock.Any(), false).
+					Return(nil, errOops).
+					Times(1)
+			},
+			expect: testSubommandExpectation{err: errOops},
+		},
+	}
+	for idx, tc := range tcs {
+		t.Run(strconv.Itoa(idx)+strings.Join(tc.args, " "), func(t *testing.T) {
+			testSubcommand(t, tc)
+		})
+	}
+}
+
+func TestApp_Run_canary(t *testing.T) {
+	result := &frontier.CanaryResult{
+		Status:                frontier.CanaryStatusStarted,
+		FunctionName:          "test-fn",
+		CanaryFunctionName:    "test-fn-canary",
+		DistributionID:        "EPRIMARY",
+		StagingDistributionID: "ESTAGING",
+		PolicyID:              "policy-1",
+	}
+	tcs := []testSubcommandArgs{
+		{
+			args: []string{"canary", "start", "--config", "function.yml", "--distribution-id", "EPRIMARY", "--weight", "0.1", "--output", "json"},
+			expectCanary: func(m *mockWithLogger[*cli.MockCanaryController]) {
+				m.M.EXPECT().
+					StartCanary(gomock.Any(), "function.yml", "EPRIMARY", &frontier.CanaryTraffic{Weight: 0.1}).
+					Return(result, nil).
+					Times(1)
+			},
+			expect: testSubommandExpectation{
+				stdout: `{"Status":"started","FunctionName":"test-fn","CanaryFunctionName":"test-fn-canary","DistributionID":"EPRIMARY","StagingDistributionID":"ESTAGING","PolicyID":"policy-1"}` + "\n",
+			},
+		},
+		{
+			args: []string{"canary", "start", "--distribution-id", "EPRIMARY", "--header", "aws-cf-cd-canary=true", "--canary-name", "my-canary"},
+			expectCanary: func(m *mockWithLogger[*cli.MockCanaryController]) {
+				m.M.EXPECT().
+					StartCanary(gomock.Any(), "function.yml", "EPRIMARY", &frontier.CanaryTraffic{Header: "aws-cf-cd-canary", HeaderValue: "true"}, frontier.CanaryFunctionName("my-canary")).
+					Return(result, nil).
+					Times(1)
+			},
+		},
+		{
+			args:   []string{"canary", "start", "--distribution-id", "EPRIMARY", "--weight", "0.5"},
+			expect: testSubommandExpectation{err: &cli.InvalidCanaryTrafficError{Flag: "weight", V: "0.5"}},
+		},
+		{
+			args:   []string{"canary", "start", "--distribution-id", "EPRIMARY", "--header", "x-canary=true"},
+			expect: testSubommandExpectation{err: &cli.InvalidCanaryTrafficError{Flag: "header", V: "x-canary=true"}},
+		},
+		{
+			args: []string{"canary", "promote", "--distribution-id", "EPRIMARY"},
+			expectCanary: func(m *mockWithLogger[*cli.MockCanaryController]) {
+				m.M.EXPECT().
+					PromoteCanary(gomock.Any(), "function.yml", "EPRIMARY").
+					Return(result, nil).
+					Times(1)
+			},
+		},
+		{
+			args: []string{"canary", "abort", "--distribution-id", "EPRIMARY"},
+			expectCanary: func(m *mockWithLogger[*cli.MockCanaryController]) {
+				m.M.EXPECT().
+					AbortCanary(gomock.Any(), "function.yml", "EPRIMARY").
+					Return(nil, frontier.ErrCanaryNotStarted).
+					Times(1)
+			},
+			expect: testSubommandExpectation{err: frontier.ErrCanaryNotStarted},
+		},
+	}
+	for idx, tc := range tcs {
+		t.Run(strconv.Itoa(idx)+strings.Join(tc.args, " "), func(t *testing.T) {
+			testSubcommand(t, tc)
+		})
+	}
+}
+
+func TestApp_Run_stats(t *testing.T) {
+	exceeded := &frontier.FunctionStats{
+		FunctionName: "test-fn",
+		Stage:        types.FunctionStageDevelopment,
+		CodeSize:     &frontier.CodeSizeStats{Bundled: 2048, Limit: 1024},
+		Exceeded:     []frontier.LimitName{frontier.LimitMaxCodeBytes},
+	}
+	tcs := []testSubcommandArgs{
+		{
+			args: []string{"stats", "--config", "function.yml"},
+			expectStats: func(m *mockWithLogger[*cli.MockStatsController]) {
+				m.M.EXPECT().
+					Stats(gomock.Any(), "function.yml").
+					Return(exceeded, nil).
+					Times(1)
+			},
+			expect: testSubommandExpectation{
+				stdout: `{"FunctionName":"test-fn","Stage":"DEVELOPMENT","CodeSize":{"Bundled":2048,"Deployed":null,"Limit":1024},"Exceeded":["maxCodeBytes"]}` + "\n",
+			},
+		},
+		{
+			args: []string{"stats", "--stage", "LIVE", "--test-event", "a.json", "--test-event", "b.json", "--fail-on-exceeded"},
+			expectStats: func(m *mockWithLogger[*cli.MockStatsController]) {
+				m.M.EXPECT().
+					Stats(gomock.Any(), "function.yml", frontier.StatsStage(types.FunctionStageLive), frontier.TestEvents("a.json", "b.json")).
+					Return(exceeded, nil).
+					Times(1)
+			},
+			expect: testSubommandExpectation{err: cli.ErrLimitExceeded},
+		},
+		{
+			args: []string{"stats", "--fail-on-exceeded"},
+			expectStats: func(m *mockWithLogger[*cli.MockStatsController]) {
+				m.M.EXPECT().
+					Stats(gomock.Any(), "function.yml").
+					Return(&frontier.FunctionStats{FunctionName: "test-fn", CodeSize: &frontier.CodeSizeStats{}}, nil).
+					Times(1)
+			},
+		},
+	}
+	for idx, tc := range tcs {
+		t.Run(strconv.Itoa(idx)+strings.Join(tc.args, " "), func(t *testing.T) {
+			testSubcommand(t, tc)
+		})
+	}
+}
+
+func TestApp_Run_lint(t *testing.T) {
+	report := &frontier.LintReport{
+		FunctionName: "test-fn",
+		CodePath:     "fn.js",
+		EventType:    lint.EventTypeViewerRequest,
+		Findings: []*lint.Finding{
+			{RuleID: lint.RuleQuerystringAsString, Severity: lint.SeverityWarning, Message: "request.querystring is an object of the query parameters, not a string", Path: "fn.js", Line: 3, Column: 23},
+		},
+	}
+	failed := &frontier.LintReport{
+		FunctionName: "test-fn",
+		CodePath:     "fn.js",
+		Findings: []*lint.Finding{
+			{RuleID: lint.RuleNoTimers, Severity: lint.SeverityError, Message: "setTimeout is not available: CloudFront Functions do not provide timers", Path: "fn.js", Line: 4, Column: 3},
+		},
+	}
+	tcs := []testSubcommandArgs{
+		{
+			args: []string{"lint", "--config", "function.yml"},
+			expectLint: func(m *mockWithLogger[*cli.MockLintController]) {
+				m.M.EXPECT().
+					Lint(gomock.Any(), "function.yml").
+					Return(report, nil).
+					Times(1)
+			},
+			expect: testSubommandExpectation{
+				stdout: "fn.js:3:23: warning querystring-as-string: request.querystring is an object of the query parameters, not a string\n",
+			},
+		},
+		{
+			args: []string{"lint", "--event-type", "viewer-response"},
+			expectLint: func(m *mockWithLogger[*cli.MockLintController]) {
+				m.M.EXPECT().
+					Lint(gomock.Any(), "function.yml", frontier.LintEventType(lint.EventTypeViewerResponse)).
+					Return(failed, nil).
+					Times(1)
+			},
+			expect: testSubommandExpectation{
+				err:    cli.ErrLintFailed,
+				stdout: "fn.js:4:3: error no-timers: setTimeout is not available: CloudFront Functions do not provide timers\n",
+			},
+		},
+		{
+			args:   []string{"lint", "--event-type", "origin-request"},
+			expect: testSubommandExpectation{err: &cli.InvalidEventTypeError{V: "origin-request"}},
+		},
+		{
+			args: []string{"lint", "--format", "sarif"},
+			expectLint: func(m *mockWithLogger[*cli.MockLintController]) {
+				m.M.EXPECT().
+					Lint(gomock.Any(), "function.yml").
+					Return(&frontier.LintReport{FunctionName: "test-fn", CodePath: "fn.js"}, nil).
+					Times(1)
+			},
+			expect: testSubommandExpectation{
+				stdout: sarifOf(t, &frontier.LintReport{FunctionName: "test-fn", CodePath: "fn.js"}),
+			},
+		},
+	}
+	for idx, tc := range tcs {
+		t.Run(strconv.Itoa(idx)+strings.Join(tc.args, " "), func(t *testing.T) {
+			testSubcommand(t, tc)
+		})
+	}
+}
+
+func TestApp_Run_init(t *testing.T) {
+	result := &frontier.InitResult{
+		FunctionName:    "my-fn",
+		Template:        frontier.TemplateRedirect,
+		ConfigPath:      "my-fn/function.yml",
+		CodePath:        "my-fn/fn.js",
+		TestEvents:      []string{"my-fn/events/redirected.json"},
+		ExpectedOutputs: []string{"my-fn/expected/redirected.json"},
+	}
+	tcs := []testSubcommandArgs{
+		{
+			args: []string{"init", "--template", "redirect"},
+			expectInit: func(m *mockWithLogger[*cli.MockInitController]) {
+				m.M.EXPECT().
+					Init(gomock.Any(), ".", frontier.TemplateRedirect).
+					Return(result, nil).
+					Times(1)
+			},
+		},
+		{
+			args: []string{"init", "--template", "redirect", "--name", "my-fn", "--runtime", "cloudfront-js-1.0", "--dir", "my-fn", "--force", "--output", "json"},
+			expectInit: func(m *mockWithLogger[*cli.MockInitController]) {
+				m.M.EXPECT().
+					Init(gomock.Any(), "my-fn", frontier.TemplateRedirect, frontier.InitFunctionName("my-fn"), frontier.InitRuntime(types.FunctionRuntimeCloudfrontJs10), frontier.InitOverwrite(true)).
+					Return(result, nil).
+					Times(1)
+			},
+			expect: testSubommandExpectation{
+				stdout: `{"FunctionName":"my-fn","Template":"redirect","ConfigPath":"my-fn/function.yml","CodePath":"my-fn/fn.js","TestEvents":["my-fn/events/redirected.json"],"ExpectedOutputs":["my-fn/expected/redirected.json"]}` + "\n",
+			},
+		},
+		{
+			args: []string{"init", "--template", "redirect"},
+			expectInit: func(m *mockWithLogger[*cli.MockInitController]) {
+				m.M.EXPECT().
+					Init(gomock.Any(), ".", frontier.TemplateRedirect).
+					Return(nil, &fs.PathError{Op: "init", Path: "fn.js", Err: fs.ErrExist}).
+					Times(1)
+			},
+			expect: testSubommandExpectation{err: &cli.FileExistsError{Path: "fn.js"}},
+		},
+		{
+			args:   []string{"init", "--template", "redirect", "--runtime", "cloudfront-js-0.1"},
+			expect: testSubommandExpectation{err: &cli.InvalidRuntimeError{V: "cloudfront-js-0.1"}},
+		},
+		{
+			args: []string{"init", "--template", "no-such-template"},
+			expectInit: func(m *mockWithLogger[*cli.MockInitController]) {
+				m.M.EXPECT().
+					Init(gomock.Any(), ".", frontier.Template("no-such-template")).
+					Return(nil, &frontier.UnknownTemplateError{Name: "no-such-template"}).
+					Times(1)
+			},
+			expect: testSubommandExpectation{err: &frontier.UnknownTemplateError{Name: "no-such-template"}},
+		},
+	}
+	for idx, tc := range tcs {
+		t.Run(strconv.Itoa(idx)+strings.Join(tc.args, " "), func(t *testing.T) {
+			testSubcommand(t, tc)
+		})
+	}
+}
+
+func sarifOf(t *testing.T, report *frontier.LintReport) string {
+	t.Helper()
+	buf := new(bytes.Buffer)
+	if err := sarif.NewLintReportPresenter(buf).PresentLintReport(report); err != nil {
+		t.Fatal(err)
+	}
+	return buf.String()
+}
+
 type mockWithLogger[M any] struct {
 	M      M
 	Logger testLogger
@@ -274,16 +1185,28 @@ type testLogger interface {
 
 type testSubcommandArgs struct {
 	expectDeploy              func(m *mockWithLogger[*cli.MockDeployController])
+	expectUnlock              func(m *mockWithLogger[*cli.MockUnlockController])
+	expectSDKConfigurer       func(m *mockWithLogger[*cli.MockSDKConfigurer])
 	expectImport              func(m *mockWithLogger[*cli.MockImportController])
 	expectRender              func(m *mockWithLogger[*cli.MockRenderController])
+	expectExport              func(m *mockWithLogger[*cli.MockExportController])
+	expectDrift               func(m *mockWithLogger[*cli.MockDriftController])
 	expectListDistributions   func(m *mockWithLogger[*cli.MockListDistributionsController])
+	expectWatch               func(m *mockWithLogger[*cli.MockWatchController])
+	expectCompare             func(m *mockWithLogger[*cli.MockCompareController])
+	expectPromote             func(m *mockWithLogger[*cli.MockPromoteController])
+	expectCanary              func(m *mockWithLogger[*cli.MockCanaryController])
+	expectStats               func(m *mockWithLogger[*cli.MockStatsController])
+	expectLint                func(m *mockWithLogger[*cli.MockLintController])
+	expectInit                func(m *mockWithLogger[*cli.MockInitController])
 	expectFunctionARNResolver func(m *mockWithLogger[*cli.MockFunctionARNResolver])
 	args                      []string
 	expect                    testSubommandExpectation
 }
 
 type testSubommandExpectation struct {
-	err error
+	err    error
+	stdout string
 }
 
 func testSubcommand(t *testing.T, args testSubcommandArgs) {
@@ -294,28 +1217,83 @@ func testSubcommand(t *testing.T, args testSubcommandArgs) {
 	stderr := new(bytes.Buffer)
 	ctrl := gomock.NewController(t)
 	deployCtrl := cli.NewMockDeployController(ctrl)
+	unlockCtrl := cli.NewMockUnlockController(ctrl)
 	importCtrl := cli.NewMockImportController(ctrl)
 	renderCtrl := cli.NewMockRenderController(ctrl)
+	exportCtrl := cli.NewMockExportController(ctrl)
+	driftCtrl := cli.NewMockDriftController(ctrl)
 	listDistsCtrl := cli.NewMockListDistributionsController(ctrl)
+	watchCtrl := cli.NewMockWatchController(ctrl)
+	compareCtrl := cli.NewMockCompareController(ctrl)
+	promoteCtrl := cli.NewMockPromoteController(ctrl)
+	canaryCtrl := cli.NewMockCanaryController(ctrl)
+	statsCtrl := cli.NewMockStatsController(ctrl)
+	lintCtrl := cli.NewMockLintController(ctrl)
+	initCtrl := cli.NewMockInitController(ctrl)
 	controllers := cli.Controllers{
 		DeployController:            deployCtrl,
+		UnlockController:            unlockCtrl,
 		ImportController:            importCtrl,
 		RenderController:            renderCtrl,
+		ExportController:            exportCtrl,
+		DriftController:             driftCtrl,
 		ListDistributionsController: listDistsCtrl,
+		WatchController:             watchCtrl,
+		CompareController:           compareCtrl,
+		PromoteController:           promoteCtrl,
+		CanaryController:            canaryCtrl,
+		StatsController:             statsCtrl,
+		LintController:              lintCtrl,
+		InitController:              initCtrl,
 	}
 	if args.expectDeploy != nil {
 		args.expectDeploy(&mockWithLogger[*cli.MockDeployController]{M: deployCtrl, Logger: t})
 	}
+	if args.expectUnlock != nil {
+		args.expectUnlock(&mockWithLogger[*cli.MockUnlockController]{M: unlockCtrl, Logger: t})
+	}
 	if args.expectImport != nil {
 		args.expectImport(&mockWithLogger[*cli.MockImportController]{M: importCtrl, Logger: t})
 	}
 	if args.expectRender != nil {
 		args.expectRender(&mockWithLogger[*cli.MockRenderController]{M: renderCtrl, Logger: t})
 	}
+	if args.expectExport != nil {
+		args.expectExport(&mockWithLogger[*cli.MockExportController]{M: exportCtrl, Logger: t})
+	}
+	if args.expectDrift != nil {
+		args.expectDrift(&mockWithLogger[*cli.MockDriftController]{M: driftCtrl, Logger: t})
+	}
+	if args.expectSDKConfigurer != nil {
+		sdkConfigurer := cli.NewMockSDKConfigurer(ctrl)
+		args.expectSDKConfigurer(&mockWithLogger[*cli.MockSDKConfigurer]{M: sdkConfigurer, Logger: t})
+		controllers.SDKConfigurer = sdkConfigurer
+	}
 	if args.expectListDistributions != nil {
 		m := &mockWithLogger[*cli.MockListDistributionsController]{M: listDistsCtrl, Logger: t}
 		args.expectListDistributions(m)
 	}
+	if args.expectWatch != nil {
+		args.expectWatch(&mockWithLogger[*cli.MockWatchController]{M: watchCtrl, Logger: t})
+	}
+	if args.expectCompare != nil {
+		args.expectCompare(&mockWithLogger[*cli.MockCompareController]{M: compareCtrl, Logger: t})
+	}
+	if args.expectPromote != nil {
+		args.expectPromote(&mockWithLogger[*cli.MockPromoteController]{M: promoteCtrl, Logger: t})
+	}
+	if args.expectCanary != nil {
+		args.expectCanary(&mockWithLogger[*cli.MockCanaryController]{M: canaryCtrl, Logger: t})
+	}
+	if args.expectStats != nil {
+		args.expectStats(&mockWithLogger[*cli.MockStatsController]{M: statsCtrl, Logger: t})
+	}
+	if args.expectLint != nil {
+		args.expectLint(&mockWithLogger[*cli.MockLintController]{M: lintCtrl, Logger: t})
+	}
+	if args.expectInit != nil {
+		args.expectInit(&mockWithLogger[*cli.MockInitController]{M: initCtrl, Logger: t})
+	}
 	arnResolver := cli.NewMockFunctionARNResolver(ctrl)
 	if args.expectFunctionARNResolver != nil {
 		m := &mockWithLogger[*cli.MockFunctionARNResolver]{
@@ -339,6 +1317,25 @@ func testSubcommand(t *testing.T, args testSubcommandArgs) {
 	if diff := diffErrorsConservatively(args.expect.err, gotErr); diff != "" {
 		t.Errorf("error (-want, +got):\n%s", diff)
 	}
+	if args.expect.stdout != "" {
+		if diff := cmp.Diff(args.expect.stdout, stdout.String()); diff != "" {
+			t.Errorf("stdout (-want, +got):\n%s", diff)
+		}
+	}
+}
+
+func seqOf[T any](xs []T, err error) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		for _, x := range xs {
+			if !yield(x, nil) {
+				return
+			}
+		}
+		if err != nil {
+			var zero T
+			yield(zero, err)
+		}
+	}
 }
 
 type literalError struct {
@@ -0,0 +1,78 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/aereal/frontier"
+	"github.com/aws/aws-sdk-go-v2/service/cloudfront/types"
+	"github.com/urfave/cli/v3"
+)
+
+func (a *App) cmdCompare() *cli.Command {
+	return &cli.Command{
+		Name:        "compare",
+		Usage:       "compare the configs and the codes of two functions",
+		Description: "each of <a> and <b> is a function name with an optional stage like my-function@LIVE (DEVELOPMENT by default), or a path to the local config like ./function.yml",
+		ArgsUsage:   "<a> <b>",
+		Writer:      a.output,
+		ErrWriter:   a.errOutput,
+		Reader:      a.input,
+		Action:      a.actionCompare,
+	}
+}
+
+func (a *App) actionCompare(ctx context.Context, cmd *cli.Command) error {
+	if cmd.Args().Len() != 2 {
+		return ErrCompareTargetsRequired
+	}
+	targetA, err := parseCompareTarget(cmd.Args().Get(0))
+	if err != nil {
+		return err
+	}
+	targetB, err := parseCompareTarget(cmd.Args().Get(1))
+	if err != nil {
+		return err
+	}
+	result, err := a.controllers.Compare(ctx, targetA, targetB)
+	if err != nil {
+		return err
+	}
+	return writeCompareResult(cmd.Writer, result)
+}
+
+// parseCompareTarget parses `name`, `name@STAGE` or the path to the config.
+// The argument that contains a dot or a slash is a path because they are not allowed in the function name.
+func parseCompareTarget(arg string) (*frontier.CompareTarget, error) {
+	if strings.ContainsAny(arg, "./") {
+		return &frontier.CompareTarget{ConfigPath: arg}, nil
+	}
+	name, stage, _ := strings.Cut(arg, "@")
+	if name == "" {
+		return nil, ErrFunctionNameRequired
+	}
+	target := &frontier.CompareTarget{FunctionName: name, Stage: types.FunctionStageDevelopment}
+	if stage != "" {
+		target.Stage = types.FunctionStage(strings.ToUpper(stage))
+		if !slices.Contains(target.Stage.Values(), target.Stage) {
+			return nil, &InvalidStageError{V: stage}
+		}
+	}
+	return target, nil
+}
+
+func writeCompareResult(w io.Writer, result *frontier.CompareResult) error {
+	if result.Identical() {
+		_, err := fmt.Fprintf(w, "%s and %s are identical\n", result.A, result.B)
+		return err
+	}
+	for _, diff := range []string{result.ConfigDiff, result.CodeDiff} {
+		if _, err := io.WriteString(w, diff); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -3,6 +3,7 @@ package cli
 import (
 	"context"
 
+	"github.com/aereal/frontier"
 	"github.com/urfave/cli/v3"
 )
 
@@ -43,13 +44,68 @@ func (a *App) cmdDeploy() *cli.Command {
 		},
 		Flags: []cli.Flag{
 			flagConfigPath,
+			&cli.DurationFlag{
+				Name:  "lock-wait",
+				Usage: "wait for the deployment of the same function by others up to the duration. zero indicates fail immediately.",
+			},
+			newResultOutputFlag(),
 		},
-		Action: a.actionDeploy,
+		Writer:    a.output,
+		ErrWriter: a.errOutput,
+		Reader:    a.input,
+		Action:    a.actionDeploy,
 	}
 }
 
 func (a *App) actionDeploy(ctx context.Context, cmd *cli.Command) error {
 	configPath := cmd.String(flagConfigPath.Name)
 	doPublish := a.shouldPublish
-	return a.controllers.Deploy(ctx, configPath, doPublish)
+	var opts []frontier.DeployOption
+	if wait := cmd.Duration("lock-wait"); wait > 0 {
+		opts = append(opts, frontier.LockWait(wait))
+	}
+	result, deployErr := a.controllers.Deploy(ctx, configPath, doPublish, opts...)
+	if result == nil {
+		return deployErr
+	}
+	// the function is deployed even if postDeploy hooks fail, so the result is printed with the error
+	if err := presentResult(cmd, result); err != nil {
+		return err
+	}
+	return deployErr
+}
+
+func (a *App) cmdUnlock() *cli.Command {
+	return &cli.Command{
+		Name:        "unlock",
+		Description: "release the deployment lock of the function forcibly",
+		MutuallyExclusiveFlags: []cli.MutuallyExclusiveFlags{
+			{
+				Flags: [][]cli.Flag{
+					{
+						flagConfigPath,
+					},
+					{
+						&cli.StringFlag{
+							Name:  "name",
+							Usage: "function name",
+						},
+					},
+				},
+			},
+		},
+		Action: a.actionUnlock,
+	}
+}
+
+func (a *App) actionUnlock(ctx context.Context, cmd *cli.Command) error {
+	functionName := cmd.String("name")
+	if functionName == "" {
+		fn, err := frontier.ParseConfigFromPath(cmd.String(flagConfigPath.Name))
+		if err != nil {
+			return err
+		}
+		functionName = fn.Name
+	}
+	return a.controllers.Unlock(ctx, functionName)
 }
@@ -10,9 +10,9 @@ import (
 
 	"github.com/aereal/frontier"
 	"github.com/aereal/frontier/controller/listdist"
-	"github.com/aereal/frontier/internal/fnarn"
-	"github.com/aereal/frontier/internal/presenter"
-	"github.com/aereal/frontier/internal/presenter/json"
+	"github.com/aereal/frontier/fnarn"
+	"github.com/aereal/frontier/presenter"
+	"github.com/aereal/frontier/presenter/json"
 	"github.com/urfave/cli/v3"
 )
 
@@ -74,6 +74,21 @@ func (a *App) cmdDistList() *cli.Command {
 				Usage:    "list only associations that run functions against given event type",
 				Category: "search criteria",
 			},
+			&cli.IntFlag{
+				Name:  "limit",
+				Usage: "stop listing after the given number of associations are found. zero indicates no limit.",
+			},
+			&cli.DurationFlag{
+				Name:     "cache-ttl",
+				Usage:    "reuse the distributions fetched within the duration. zero indicates no cache should be used.",
+				Sources:  cli.EnvVars("FRONTIER_DIST_CACHE_TTL"),
+				Category: "cache",
+			},
+			&cli.BoolFlag{
+				Name:     "refresh",
+				Usage:    "ignore the cached distributions and fetch them again. requires --cache-ttl.",
+				Category: "cache",
+			},
 		},
 	}
 }
@@ -117,12 +132,22 @@ func (a *App) actionDistList(ctx context.Context, cmd *cli.Command) error {
 		}
 		criteria.Add(listdist.EqualFunctionArn(functionArn))
 	}
-	associations, err := a.controllers.ListDistributions(ctx, cmd.Writer, criteria)
-	if err != nil {
-		return err
+	var opts []listdist.ListDistributionsOption
+	ttl := cmd.Duration("cache-ttl")
+	if ttl > 0 {
+		opts = append(opts, listdist.CacheTTL(ttl))
+	}
+	if cmd.Bool("refresh") {
+		// the cache is not used at all without the TTL, so nothing would be refreshed
+		if ttl <= 0 {
+			return ErrRefreshWithoutCache
+		}
+		opts = append(opts, listdist.RefreshCache(true))
+	}
+	if limit := cmd.Int("limit"); limit > 0 {
+		opts = append(opts, listdist.Limit(int(limit)))
 	}
-	presenter.PresentAssociatedDistributions(associations)
-	return nil
+	return presenter.PresentAssociatedDistributions(a.controllers.ListDistributions(ctx, cmd.Writer, criteria, opts...))
 }
 
 func join[T fmt.Stringer](out io.Writer, xs iter.Seq[T], sep string) {
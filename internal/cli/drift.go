@@ -0,0 +1,119 @@
+package cli
+
+import (
+	"context"
+	"io/fs"
+	"path/filepath"
+	"slices"
+
+	"github.com/aereal/frontier"
+	"github.com/urfave/cli/v3"
+)
+
+func (a *App) cmdDrift() *cli.Command {
+	return &cli.Command{
+		Name:        "drift",
+		Description: "detect differences between the function config and the deployed function",
+		Writer:      a.output,
+		ErrWriter:   a.errOutput,
+		Reader:      a.input,
+		MutuallyExclusiveFlags: []cli.MutuallyExclusiveFlags{
+			{
+				Flags: [][]cli.Flag{
+					{
+						flagConfigPath,
+					},
+					{
+						&cli.StringFlag{
+							Name:  "dir",
+							Usage: "check every function config (function.yml or function.yaml) found under the directory",
+						},
+					},
+				},
+			},
+		},
+		Flags: []cli.Flag{
+			&cli.FlagBase[OutputFormat, cli.NoConfig, outputFormatCreator]{
+				Name:  "format",
+				Usage: usageText(slices.Values(AvailableOutputFormatValues()), "output format"),
+				Value: OutputFormatJSON,
+			},
+			&cli.BoolFlag{
+				Name:  "fail-on-drift",
+				Usage: "exit with an error if any function is not in sync",
+			},
+		},
+		Action: a.actionDrift,
+	}
+}
+
+func (a *App) actionDrift(ctx context.Context, cmd *cli.Command) error {
+	format, ok := cmd.Value("format").(OutputFormat)
+	if !ok {
+		format = OutputFormatJSON
+	}
+	presenter := newResultPresenter[*frontier.DriftReport](cmd.Writer, format)
+
+	configPaths := []string{cmd.String(flagConfigPath.Name)}
+	var opts []frontier.DriftOption
+	dir := cmd.String("dir")
+	if dir != "" {
+		var err error
+		configPaths, err = findFunctionConfigs(dir)
+		if err != nil {
+			return err
+		}
+		// the configs under the directory cannot assume the current directory to resolve code.path
+		opts = append(opts, frontier.DriftCodePathRelativeToConfig(true))
+	}
+	var (
+		drifted   bool
+		configErr error
+	)
+	for _, configPath := range configPaths {
+		report, err := a.controllers.DetectDrift(ctx, configPath, opts...)
+		if err != nil {
+			// one broken config must not stop checking the rest of the directory
+			if dir == "" || ErrorKindOf(err) != ErrorKindInvalidConfig {
+				return err
+			}
+			if configErr == nil {
+				configErr = err
+			}
+			report = &frontier.DriftReport{ConfigPath: configPath, Error: err.Error()}
+		}
+		if report.Status != frontier.DriftStatusInSync {
+			drifted = true
+		}
+		if err := presenter.PresentResult(report); err != nil {
+			return err
+		}
+	}
+	if configErr != nil {
+		return configErr
+	}
+	if drifted && cmd.Bool("fail-on-drift") {
+		return ErrDriftDetected
+	}
+	return nil
+}
+
+func findFunctionConfigs(dir string) ([]string, error) {
+	var paths []string
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		if name := d.Name(); name == "function.yml" || name == "function.yaml" {
+			paths = append(paths, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return paths, nil
+}
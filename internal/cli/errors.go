@@ -1,9 +1,230 @@
 package cli
 
-import "errors"
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"slices"
+
+	"github.com/aereal/frontier"
+	"github.com/aereal/frontier/fnarn"
+	"github.com/aereal/frontier/lock"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/cloudfront/types"
+	"github.com/aws/smithy-go"
+	cli "github.com/urfave/cli/v3"
+)
 
 var (
-	ErrFunctionNameRequired = errors.New("function name is required")
-	ErrFunctionPathRequired = errors.New("function path is required")
-	ErrConfigPathRequired   = errors.New("config path is required")
+	ErrFunctionNameRequired   = errors.New("function name is required")
+	ErrFunctionPathRequired   = errors.New("function path is required")
+	ErrConfigPathRequired     = errors.New("config path is required")
+	ErrDriftDetected          = errors.New("drift detected")
+	ErrBothStdout             = errors.New("either the config or the function can be written to stdout")
+	ErrCompareTargetsRequired = errors.New("two functions or configs to compare are required")
+	ErrPromoteToItself        = errors.New("the source and the target of the promotion are the same function")
+	ErrLimitExceeded          = errors.New("limit exceeded")
+	ErrLintFailed             = errors.New("lint failed")
+	ErrRefreshWithoutCache    = errors.New("--refresh requires --cache-ttl or FRONTIER_DIST_CACHE_TTL")
+)
+
+// UsageError tells the command is called with the invalid flags or arguments.
+type UsageError struct {
+	Err error
+}
+
+func (e *UsageError) Error() string { return e.Err.Error() }
+
+func (e *UsageError) Unwrap() error { return e.Err }
+
+// ErrorKind classifies the errors so that the scripts can tell them by the exit code.
+type ErrorKind string
+
+const (
+	ErrorKindUnknown       ErrorKind = "unknown"
+	ErrorKindUsage         ErrorKind = "usage"
+	ErrorKindInvalidConfig ErrorKind = "invalid_config"
+	ErrorKindNotFound      ErrorKind = "not_found"
+	ErrorKindAuth          ErrorKind = "auth"
+	ErrorKindConflict      ErrorKind = "conflict"
+	ErrorKindDriftDetected ErrorKind = "drift_detected"
+	ErrorKindHookFailed    ErrorKind = "hook_failed"
+	ErrorKindLimitExceeded ErrorKind = "limit_exceeded"
+	ErrorKindLintFailed    ErrorKind = "lint_failed"
+	ErrorKindCanceled      ErrorKind = "canceled"
+)
+
+var exitCodes = map[ErrorKind]int{
+	ErrorKindUnknown:       1,
+	ErrorKindUsage:         2,
+	ErrorKindInvalidConfig: 3,
+	ErrorKindNotFound:      4,
+	ErrorKindAuth:          5,
+	ErrorKindConflict:      6,
+	ErrorKindDriftDetected: 7,
+	ErrorKindHookFailed:    8,
+	ErrorKindLimitExceeded: 9,
+	ErrorKindLintFailed:    10,
+	ErrorKindCanceled:      130,
+}
+
+// ExitCode returns the documented exit code of the kind.
+func (k ErrorKind) ExitCode() int {
+	if code, ok := exitCodes[k]; ok {
+		return code
+	}
+	return exitCodes[ErrorKindUnknown]
+}
+
+var authErrorCodes = []string{
+	"AccessDenied",
+	"AccessDeniedException",
+	"ExpiredToken",
+	"ExpiredTokenException",
+	"InvalidClientTokenId",
+	"SignatureDoesNotMatch",
+	"UnrecognizedClientException",
+}
+
+// ErrorKindOf classifies the error; the error not classified is [ErrorKindUnknown].
+func ErrorKindOf(err error) ErrorKind {
+	var (
+		usageErr        *UsageError
+		outputFormatErr *InvalidOutputFormatError
+		logFormatErr    *InvalidLogFormatError
+		telemetryErr    *InvalidTelemetryOptionError
+		stageErr        *InvalidStageError
+		fileExistsErr   *FileExistsError
+		trafficErr      *InvalidCanaryTrafficError
+		eventTypeErr    *InvalidEventTypeError
+		runtimeErr      *InvalidRuntimeError
+		templateErr     *frontier.UnknownTemplateError
+		exportFormatErr *frontier.InvalidExportFormatError
+		identifierErr   *fnarn.UnsupportedFunctionIdentifierError
+		configErr       *frontier.ConfigError
+		invalidCfgErr   *frontier.InvalidConfigError
+		noFunctionErr   *types.NoSuchFunctionExists
+		noEntityErr     *types.EntityNotFound
+		noDistErr       *types.NoSuchDistribution
+		signingErr      *v4.SigningError
+		noProfileErr    config.SharedConfigProfileNotExistError
+		apiErr          smithy.APIError
+		conflictErr     *frontier.ConflictError
+		heldErr         *lock.HeldError
+		preconditionErr *types.PreconditionFailed
+		hookErr         *frontier.HookError
+		notAssocErr     *frontier.FunctionNotAssociatedError
+		limitErr        *frontier.LimitExceededError
+		kvsMappingErr   *InvalidKeyValueStoreMappingError
+		unmappedKVSErr  *frontier.UnmappedKeyValueStoreError
+	)
+	switch {
+	case err == nil:
+		return ""
+	case errors.Is(err, ErrDriftDetected):
+		return ErrorKindDriftDetected
+	case errors.As(err, &hookErr):
+		return ErrorKindHookFailed
+	case errors.Is(err, ErrLimitExceeded), errors.As(err, &limitErr):
+		return ErrorKindLimitExceeded
+	case errors.Is(err, ErrLintFailed):
+		return ErrorKindLintFailed
+	case errors.Is(err, context.Canceled):
+		return ErrorKindCanceled
+	case errors.As(err, &usageErr),
+		errors.As(err, &outputFormatErr),
+		errors.As(err, &logFormatErr),
+		errors.As(err, &telemetryErr),
+		errors.As(err, &stageErr),
+		errors.As(err, &fileExistsErr),
+		errors.As(err, &trafficErr),
+		errors.As(err, &eventTypeErr),
+		errors.As(err, &runtimeErr),
+		errors.As(err, &templateErr),
+		errors.As(err, &kvsMappingErr),
+		errors.Is(err, ErrBothStdout),
+		errors.Is(err, ErrCompareTargetsRequired),
+		errors.Is(err, ErrPromoteToItself),
+		errors.Is(err, ErrRefreshWithoutCache),
+		errors.As(err, &exportFormatErr),
+		errors.As(err, &identifierErr),
+		errors.Is(err, ErrFunctionNameRequired),
+		errors.Is(err, ErrFunctionPathRequired),
+		errors.Is(err, ErrConfigPathRequired):
+		return ErrorKindUsage
+	case errors.As(err, &configErr),
+		errors.As(err, &invalidCfgErr),
+		errors.As(err, &unmappedKVSErr),
+		errors.Is(err, frontier.MissingFunctionNameError{}):
+		return ErrorKindInvalidConfig
+	case errors.As(err, &noFunctionErr), errors.As(err, &noEntityErr), errors.As(err, &noDistErr),
+		errors.As(err, &notAssocErr), errors.Is(err, frontier.ErrCanaryNotStarted):
+		return ErrorKindNotFound
+	case errors.As(err, &signingErr), errors.As(err, &noProfileErr):
+		return ErrorKindAuth
+	case errors.As(err, &apiErr) && slices.Contains(authErrorCodes, apiErr.ErrorCode()):
+		return ErrorKindAuth
+	case errors.As(err, &conflictErr), errors.As(err, &heldErr), errors.As(err, &preconditionErr),
+		errors.Is(err, frontier.ErrCanaryInProgress):
+		return ErrorKindConflict
+	default:
+		return ErrorKindUnknown
+	}
+}
+
+// ExitCodeOf returns the exit code for the error; 0 if the error is nil.
+func ExitCodeOf(err error) int {
+	if err == nil {
+		return 0
+	}
+	return ErrorKindOf(err).ExitCode()
+}
+
+const flagNameErrorFormat = "error-format"
+
+const (
+	errorFormatText = "text"
+	errorFormatJSON = "json"
 )
+
+func newErrorFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:    flagNameErrorFormat,
+			Usage:   "the format of the error printed on failure: text (as a log record) or json",
+			Value:   errorFormatText,
+			Sources: cli.EnvVars("FRONTIER_ERROR_FORMAT"),
+			Validator: func(v string) error {
+				if v != errorFormatText && v != errorFormatJSON {
+					return &InvalidOutputFormatError{V: v}
+				}
+				return nil
+			},
+		},
+	}
+}
+
+// ErrorReport is printed with --error-format=json.
+type ErrorReport struct {
+	Kind     ErrorKind
+	Message  string
+	ExitCode int
+}
+
+func (a *App) reportError(ctx context.Context, err error) {
+	kind := ErrorKindOf(err)
+	if a.errorFormat == errorFormatJSON {
+		report := &ErrorReport{Kind: kind, Message: err.Error(), ExitCode: kind.ExitCode()}
+		if encErr := json.NewEncoder(a.errOutput).Encode(report); encErr == nil {
+			return
+		}
+	}
+	slog.ErrorContext(ctx, err.Error(), slog.String("error", err.Error()), slog.String("kind", string(kind)))
+}
+
+// onUsageError marks the errors of parsing flags as [UsageError].
+func onUsageError(_ context.Context, _ *cli.Command, err error, _ bool) error {
+	return &UsageError{Err: err}
+}
@@ -0,0 +1,118 @@
+package cli_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/aereal/frontier"
+	"github.com/aereal/frontier/internal/cli"
+	"github.com/aereal/frontier/lock"
+	"github.com/aws/aws-sdk-go-v2/service/cloudfront/types"
+	"github.com/aws/smithy-go"
+	"github.com/google/go-cmp/cmp"
+	"go.uber.org/mock/gomock"
+)
+
+func TestErrorKindOf(t *testing.T) {
+	testCases := []struct {
+		name string
+		err  error
+		want cli.ErrorKind
+	}{
+		{name: "nil", err: nil, want: ""},
+		{name: "unknown", err: errOops, want: cli.ErrorKindUnknown},
+		{name: "usage", err: &cli.UsageError{Err: errOops}, want: cli.ErrorKindUsage},
+		{name: "refresh without cache", err: cli.ErrRefreshWithoutCache, want: cli.ErrorKindUsage},
+		{name: "invalid key value store mapping", err: &cli.InvalidKeyValueStoreMappingError{V: "arn:kvs"}, want: cli.ErrorKindUsage},
+		{name: "required argument", err: cli.ErrFunctionNameRequired, want: cli.ErrorKindUsage},
+		{name: "invalid config", err: fmt.Errorf("wrapped: %w", &frontier.InvalidConfigError{Field: "name", Reason: "oops"}), want: cli.ErrorKindInvalidConfig},
+		{name: "config not readable", err: &frontier.ConfigError{Path: "function.yml", Err: errOops}, want: cli.ErrorKindInvalidConfig},
+		{name: "missing name", err: frontier.MissingFunctionNameError{}, want: cli.ErrorKindInvalidConfig},
+		{name: "unmapped key value store", err: &frontier.UnmappedKeyValueStoreError{FunctionName: "test-fn", ARNs: []string{"arn:kvs"}}, want: cli.ErrorKindInvalidConfig},
+		{name: "function not found", err: &smithy.OperationError{ServiceID: "CloudFront", OperationName: "GetFunction", Err: &types.NoSuchFunctionExists{}}, want: cli.ErrorKindNotFound},
+		{name: "access denied", err: &smithy.OperationError{ServiceID: "CloudFront", OperationName: "GetFunction", Err: &smithy.GenericAPIError{Code: "AccessDenied"}}, want: cli.ErrorKindAuth},
+		{name: "other API error", err: &smithy.GenericAPIError{Code: "Throttling"}, want: cli.ErrorKindUnknown},
+		{name: "conflict", err: &frontier.ConflictError{FunctionName: "test-func"}, want: cli.ErrorKindConflict},
+		{name: "canary in progress", err: fmt.Errorf("wrapped: %w", frontier.ErrCanaryInProgress), want: cli.ErrorKindConflict},
+		{name: "canary not started", err: frontier.ErrCanaryNotStarted, want: cli.ErrorKindNotFound},
+		{name: "function not associated", err: &frontier.FunctionNotAssociatedError{FunctionName: "test-func", DistributionID: "EDIST"}, want: cli.ErrorKindNotFound},
+		{name: "invalid canary traffic", err: &cli.InvalidCanaryTrafficError{Flag: "weight", V: "0.5"}, want: cli.ErrorKindUsage},
+		{name: "invalid event type", err: &cli.InvalidEventTypeError{V: "origin-request"}, want: cli.ErrorKindUsage},
+		{name: "invalid runtime", err: &cli.InvalidRuntimeError{V: "cloudfront-js-0.1"}, want: cli.ErrorKindUsage},
+		{name: "unknown template", err: &frontier.UnknownTemplateError{Name: "no-such-template"}, want: cli.ErrorKindUsage},
+		{name: "lock held", err: &lock.HeldError{Lease: &lock.Lease{Key: "test-func"}}, want: cli.ErrorKindConflict},
+		{name: "limit exceeded on deploy", err: &frontier.LimitExceededError{FunctionName: "test-func", Limit: frontier.LimitMaxCodeBytes}, want: cli.ErrorKindLimitExceeded},
+		{name: "limit exceeded on stats", err: cli.ErrLimitExceeded, want: cli.ErrorKindLimitExceeded},
+		{name: "lint failed", err: cli.ErrLintFailed, want: cli.ErrorKindLintFailed},
+		{name: "drift", err: cli.ErrDriftDetected, want: cli.ErrorKindDriftDetected},
+		{name: "hook", err: &frontier.HookError{Phase: frontier.HookPhasePreDeploy, Command: "false", Err: errOops}, want: cli.ErrorKindHookFailed},
+		{name: "canceled", err: fmt.Errorf("wrapped: %w", context.Canceled), want: cli.ErrorKindCanceled},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := cli.ErrorKindOf(tc.err); got != tc.want {
+				t.Errorf("want=%q got=%q", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestApp_Execute(t *testing.T) {
+	configPath := "../../testdata/config.yml"
+	testCases := []struct {
+		name       string
+		args       []string
+		deployErr  error
+		wantCode   int
+		wantStderr string
+	}{
+		{
+			name:     "ok",
+			args:     []string{"deploy", "--config", configPath},
+			wantCode: 0,
+		},
+		{
+			name:       "conflict in JSON",
+			args:       []string{"--error-format", "json", "deploy", "--config", configPath},
+			deployErr:  &frontier.ConflictError{FunctionName: "test-func", ExpectedSHA256: "a", ActualSHA256: "b"},
+			wantCode:   6,
+			wantStderr: `{"Kind":"conflict","Message":"function test-func has been modified by someone else: expected code sha256 is a but actual is b","ExitCode":6}` + "\n",
+		},
+		{
+			name:       "unknown flag",
+			args:       []string{"--error-format", "json", "deploy", "--unknown"},
+			wantCode:   2,
+			wantStderr: `{"Kind":"usage","Message":"flag provided but not defined: -unknown","ExitCode":2}` + "\n",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			if deadline, ok := t.Deadline(); ok {
+				ctx, cancel = context.WithDeadline(ctx, deadline)
+			}
+			defer cancel()
+
+			ctrl := gomock.NewController(t)
+			deployCtrl := cli.NewMockDeployController(ctrl)
+			deployCtrl.EXPECT().
+				Deploy(gomock.Any(), configPath, true).
+				Return(&frontier.DeployResult{}, tc.deployErr).
+				AnyTimes()
+			stderr := new(bytes.Buffer)
+			app := cli.New(new(bytes.Buffer), new(bytes.Buffer), stderr, cli.Controllers{DeployController: deployCtrl}, cli.NewMockFunctionARNResolver(ctrl))
+			gotCode := app.Execute(ctx, append([]string{"frontier"}, tc.args...))
+			if gotCode != tc.wantCode {
+				t.Errorf("exit code: want=%d got=%d", tc.wantCode, gotCode)
+			}
+			if tc.wantStderr == "" {
+				return
+			}
+			if diff := cmp.Diff(tc.wantStderr, stderr.String()); diff != "" {
+				t.Errorf("stderr (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
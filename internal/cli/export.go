@@ -0,0 +1,63 @@
+package cli
+
+import (
+	"context"
+	"slices"
+
+	"github.com/aereal/frontier"
+	"github.com/urfave/cli/v3"
+)
+
+func (a *App) cmdExport() *cli.Command {
+	return &cli.Command{
+		Name:        "export",
+		Description: "export the function as an infrastructure as code resource",
+		Writer:      a.output,
+		ErrWriter:   a.errOutput,
+		Reader:      a.input,
+		Flags: []cli.Flag{
+			flagConfigPath,
+			&cli.FlagBase[frontier.ExportFormat, cli.NoConfig, exportFormatCreator]{
+				Name:  "format",
+				Usage: usageText(slices.Values(frontier.AvailableExportFormatValues()), "export format"),
+				Value: frontier.ExportFormatCloudFormation,
+			},
+		},
+		Action: a.actionExport,
+	}
+}
+
+func (a *App) actionExport(ctx context.Context, cmd *cli.Command) error {
+	format, ok := cmd.Value("format").(frontier.ExportFormat)
+	if !ok {
+		format = frontier.ExportFormatCloudFormation
+	}
+	return a.controllers.Export(ctx, cmd.String(flagConfigPath.Name), format, cmd.Writer)
+}
+
+type exportFormatValue frontier.ExportFormat
+
+var _ cli.Value = (*exportFormatValue)(nil)
+
+func (v exportFormatValue) String() string {
+	return (frontier.ExportFormat)(v).String()
+}
+
+func (v *exportFormatValue) Set(s string) error {
+	return (*frontier.ExportFormat)(v).UnmarshalText([]byte(s))
+}
+
+func (v *exportFormatValue) Get() any { return (frontier.ExportFormat)(*v) }
+
+type exportFormatCreator struct{}
+
+var _ cli.ValueCreator[frontier.ExportFormat, cli.NoConfig] = (*exportFormatCreator)(nil)
+
+func (exportFormatCreator) Create(v frontier.ExportFormat, ref *frontier.ExportFormat, _ cli.NoConfig) cli.Value { //nolint:ireturn
+	*ref = v
+	return (*exportFormatValue)(ref)
+}
+
+func (exportFormatCreator) ToString(v frontier.ExportFormat) string {
+	return v.String()
+}
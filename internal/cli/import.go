@@ -1,10 +1,20 @@
 package cli
 
 import (
+	"bytes"
 	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"iter"
 	"os"
+	"path/filepath"
+	"slices"
 
 	"github.com/aereal/frontier"
+	"github.com/aereal/frontier/controller/listdist"
+	"github.com/aws/aws-sdk-go-v2/service/cloudfront/types"
 	"github.com/urfave/cli/v3"
 )
 
@@ -23,8 +33,29 @@ func (a *App) cmdImport() *cli.Command {
 				Usage: "function implementation path",
 				Value: "fn.js",
 			},
+			&cli.StringFlag{
+				Name:      "stage",
+				Usage:     "the stage of the function to import: DEVELOPMENT (default) or LIVE",
+				Validator: validateStage,
+			},
+			&cli.BoolFlag{
+				Name:  "force",
+				Usage: "overwrite the existing config and function files",
+			},
+			&cli.StringFlag{
+				Name:  "relative-to",
+				Usage: "write code.path in the config relative to the directory, such as the directory of the config. defaults to the function path as given.",
+			},
+			&cli.BoolFlag{
+				Name:  "with-associations",
+				Usage: "write the distributions associated with the function to the config",
+			},
+			newResultOutputFlag(),
 		},
-		Action: a.actionImport,
+		Writer:    a.output,
+		ErrWriter: a.errOutput,
+		Reader:    a.input,
+		Action:    a.actionImport,
 	}
 }
 
@@ -42,28 +73,182 @@ func (a *App) actionImport(ctx context.Context, cmd *cli.Command) error {
 		return ErrConfigPathRequired
 	}
 
-	fnFile, err := openForWrite(functionPath, 0600)
+	if configPath == pathStdout && functionPath == pathStdout {
+		return ErrBothStdout
+	}
+	codePath, err := codePathInConfig(functionPath, cmd.String("relative-to"))
+	if err != nil {
+		return err
+	}
+
+	force := cmd.Bool("force")
+	fnOut, err := newImportOutput(functionPath, cmd.Writer, force)
 	if err != nil {
 		return err
 	}
-	defer fnFile.Close()
-	configFile, err := openForWrite(configPath, 0600)
+	defer fnOut.discard()
+	configOut, err := newImportOutput(configPath, cmd.Writer, force)
 	if err != nil {
 		return err
 	}
-	defer configFile.Close()
+	defer configOut.discard()
 
 	functionOut := &frontier.WritableFile{
-		FilePath: functionPath,
-		Writer:   fnFile,
+		FilePath: codePath,
+		Writer:   fnOut,
+	}
+	var opts []frontier.ImportOption
+	if stage := cmd.String("stage"); stage != "" {
+		opts = append(opts, frontier.ImportStage(types.FunctionStage(stage)))
+	}
+	if cmd.Bool("with-associations") {
+		opts = append(opts, frontier.ImportAssociations(&associationLister{ctrl: a.controllers.ListDistributionsController}))
+	}
+	result, err := a.controllers.Import(ctx, functionName, configOut, functionOut, opts...)
+	if err != nil {
+		return err
+	}
+	if err := fnOut.commit(); err != nil {
+		return err
+	}
+	if err := configOut.commit(); err != nil {
+		return err
+	}
+	return presentResult(cmd, result)
+}
+
+func validateStage(v string) error {
+	if !slices.Contains(types.FunctionStage("").Values(), types.FunctionStage(v)) {
+		return &InvalidStageError{V: v}
+	}
+	return nil
+}
+
+type InvalidStageError struct {
+	V string
+}
+
+func (e *InvalidStageError) Error() string {
+	return fmt.Sprintf("invalid stage: %q", e.V)
+}
+
+func (e *InvalidStageError) Is(other error) bool {
+	otherErr := new(InvalidStageError)
+	if !errors.As(other, &otherErr) {
+		return false
+	}
+	return otherErr.V == e.V
+}
+
+// associationLister lists the associations of the function through the controller of `frontier dist`.
+type associationLister struct {
+	ctrl ListDistributionsController
+}
+
+var _ frontier.AssociationLister = (*associationLister)(nil)
+
+func (l *associationLister) ListAssociations(ctx context.Context, functionARN string) iter.Seq2[frontier.FunctionAssociation, error] {
+	return l.ctrl.ListDistributions(ctx, io.Discard, listdist.NewCriteria(listdist.EqualFunctionArn(functionARN)))
+}
+
+const pathStdout = "-"
+
+type FileExistsError struct {
+	Path string
+}
+
+func (e *FileExistsError) Error() string {
+	return fmt.Sprintf("%s already exists; pass --force to overwrite", e.Path)
+}
+
+func (e *FileExistsError) Is(other error) bool {
+	otherErr := new(FileExistsError)
+	if !errors.As(other, &otherErr) {
+		return false
 	}
-	return a.controllers.Import(ctx, functionName, configFile, functionOut)
+	return otherErr.Path == e.Path
 }
 
-func openForWrite(name string, perm os.FileMode) (*os.File, error) {
-	f, err := os.OpenFile(name, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, perm)
+// codePathInConfig returns code.path written in the config; the function printed to stdout has no path.
+func codePathInConfig(functionPath, relativeTo string) (string, error) {
+	if functionPath == pathStdout {
+		return "", nil
+	}
+	if relativeTo == "" {
+		return functionPath, nil
+	}
+	base, err := filepath.Abs(relativeTo)
+	if err != nil {
+		return "", err
+	}
+	target, err := filepath.Abs(functionPath)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Rel(base, target)
+}
+
+// importOutput writes to the temporary file next to the path and renames it to the path on commit,
+// so that a failed import leaves neither empty nor partial files.
+// The output to stdout is buffered until commit for the same reason.
+type importOutput struct {
+	path   string
+	stdout io.Writer
+	buf    *bytes.Buffer
+	tmp    *os.File
+	done   bool
+}
+
+var _ io.Writer = (*importOutput)(nil)
+
+func newImportOutput(path string, stdout io.Writer, force bool) (*importOutput, error) {
+	if path == pathStdout {
+		return &importOutput{path: path, stdout: stdout, buf: new(bytes.Buffer)}, nil
+	}
+	if !force {
+		_, err := os.Stat(path)
+		if err == nil {
+			return nil, &FileExistsError{Path: path}
+		}
+		if !errors.Is(err, fs.ErrNotExist) {
+			return nil, err
+		}
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*.tmp")
 	if err != nil {
 		return nil, err
 	}
-	return f, nil
+	return &importOutput{path: path, tmp: tmp}, nil
+}
+
+func (o *importOutput) Write(p []byte) (int, error) {
+	if o.buf != nil {
+		return o.buf.Write(p)
+	}
+	return o.tmp.Write(p)
+}
+
+func (o *importOutput) commit() error {
+	o.done = true
+	if o.buf != nil {
+		_, err := o.buf.WriteTo(o.stdout)
+		return err
+	}
+	if err := o.tmp.Close(); err != nil {
+		_ = os.Remove(o.tmp.Name())
+		return err
+	}
+	if err := os.Rename(o.tmp.Name(), o.path); err != nil {
+		_ = os.Remove(o.tmp.Name())
+		return err
+	}
+	return nil
+}
+
+func (o *importOutput) discard() {
+	if o.done || o.tmp == nil {
+		return
+	}
+	_ = o.tmp.Close()
+	_ = os.Remove(o.tmp.Name())
 }
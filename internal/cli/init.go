@@ -0,0 +1,90 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"slices"
+
+	"github.com/aereal/frontier"
+	"github.com/aws/aws-sdk-go-v2/service/cloudfront/types"
+	"github.com/urfave/cli/v3"
+)
+
+func (a *App) cmdInit() *cli.Command {
+	return &cli.Command{
+		Name:        "init",
+		Usage:       "scaffold a new function from the built-in template",
+		Description: "write function.yml, fn.js, the sample events in events/ and the outputs expected for them in expected/ to the directory. testEvents of the config lists the sample events.",
+		Writer:      a.output,
+		ErrWriter:   a.errOutput,
+		Reader:      a.input,
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:     "template",
+				Usage:    usageText(slices.Values(frontier.AvailableTemplates()), "the template of the function"),
+				Required: true,
+			},
+			&cli.StringFlag{
+				Name:  "name",
+				Usage: "function name. defaults to the name of the template.",
+			},
+			&cli.StringFlag{
+				Name:  "runtime",
+				Usage: "the runtime of the function: cloudfront-js-2.0 (default) or cloudfront-js-1.0",
+			},
+			&cli.StringFlag{
+				Name:  "dir",
+				Usage: "the directory to write the files to",
+				Value: ".",
+			},
+			&cli.BoolFlag{
+				Name:  "force",
+				Usage: "overwrite the existing files",
+			},
+			newResultOutputFlag(),
+		},
+		Action: a.actionInit,
+	}
+}
+
+func (a *App) actionInit(ctx context.Context, cmd *cli.Command) error {
+	var opts []frontier.InitOption
+	if name := cmd.String("name"); name != "" {
+		opts = append(opts, frontier.InitFunctionName(name))
+	}
+	if runtime := cmd.String("runtime"); runtime != "" {
+		if !slices.Contains(types.FunctionRuntime("").Values(), types.FunctionRuntime(runtime)) {
+			return &InvalidRuntimeError{V: runtime}
+		}
+		opts = append(opts, frontier.InitRuntime(types.FunctionRuntime(runtime)))
+	}
+	if cmd.Bool("force") {
+		opts = append(opts, frontier.InitOverwrite(true))
+	}
+	result, err := a.controllers.Init(ctx, cmd.String("dir"), frontier.Template(cmd.String("template")), opts...)
+	if pathErr := new(fs.PathError); errors.As(err, &pathErr) && errors.Is(err, fs.ErrExist) {
+		return &FileExistsError{Path: pathErr.Path}
+	}
+	if err != nil {
+		return err
+	}
+	return presentResult(cmd, result)
+}
+
+type InvalidRuntimeError struct {
+	V string
+}
+
+func (e *InvalidRuntimeError) Error() string {
+	return fmt.Sprintf("invalid runtime: %q", e.V)
+}
+
+func (e *InvalidRuntimeError) Is(other error) bool {
+	otherErr := new(InvalidRuntimeError)
+	if !errors.As(other, &otherErr) {
+		return false
+	}
+	return otherErr.V == e.V
+}
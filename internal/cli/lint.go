@@ -0,0 +1,96 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/aereal/frontier"
+	"github.com/aereal/frontier/lint"
+	"github.com/aereal/frontier/presenter/sarif"
+	"github.com/urfave/cli/v3"
+)
+
+const (
+	lintFormatText  = "text"
+	lintFormatSARIF = "sarif"
+)
+
+func (a *App) cmdLint() *cli.Command {
+	return &cli.Command{
+		Name:        "lint",
+		Usage:       "check the function code against the restrictions of CloudFront Functions",
+		Description: "report the network or file APIs, the timers, the global mutation, the read-only headers, the incorrect shape of the returned object and request.querystring used as a string. the severities of the rules can be overridden by lint.rules of the function config.",
+		Writer:      a.output,
+		ErrWriter:   a.errOutput,
+		Reader:      a.input,
+		Flags: []cli.Flag{
+			flagConfigPath,
+			&cli.StringFlag{
+				Name:  "event-type",
+				Usage: "the event type to lint the function for: viewer-request or viewer-response. defaults to lint.eventType or the event type of the associations.",
+			},
+			&cli.StringFlag{
+				Name:  "format",
+				Usage: "the format of the findings: text or sarif",
+				Value: lintFormatText,
+				Validator: func(v string) error {
+					if v != lintFormatText && v != lintFormatSARIF {
+						return &InvalidOutputFormatError{V: v}
+					}
+					return nil
+				},
+			},
+		},
+		Action: a.actionLint,
+	}
+}
+
+func (a *App) actionLint(ctx context.Context, cmd *cli.Command) error {
+	var opts []frontier.LintOption
+	if v := cmd.String("event-type"); v != "" {
+		eventType := lint.EventType(v)
+		if eventType != lint.EventTypeViewerRequest && eventType != lint.EventTypeViewerResponse {
+			return &InvalidEventTypeError{V: v}
+		}
+		opts = append(opts, frontier.LintEventType(eventType))
+	}
+	report, err := a.controllers.Lint(ctx, cmd.String(flagConfigPath.Name), opts...)
+	if err != nil {
+		return err
+	}
+	if cmd.String("format") == lintFormatSARIF {
+		if err := sarif.NewLintReportPresenter(cmd.Writer).PresentLintReport(report); err != nil {
+			return err
+		}
+	} else {
+		printLintReport(cmd.Writer, report)
+	}
+	if report.Failed() {
+		return ErrLintFailed
+	}
+	return nil
+}
+
+func printLintReport(w io.Writer, report *frontier.LintReport) {
+	for _, f := range report.Findings {
+		fmt.Fprintf(w, "%s:%d:%d: %s %s: %s\n", f.Path, f.Line, f.Column, f.Severity, f.RuleID, f.Message)
+	}
+}
+
+type InvalidEventTypeError struct {
+	V string
+}
+
+func (e *InvalidEventTypeError) Error() string {
+	return fmt.Sprintf("invalid event type: %q", e.V)
+}
+
+func (e *InvalidEventTypeError) Is(other error) bool {
+	otherErr := new(InvalidEventTypeError)
+	if !errors.As(other, &otherErr) {
+		return false
+	}
+	return otherErr.V == e.V
+}
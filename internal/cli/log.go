@@ -2,12 +2,71 @@ package cli
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"io"
 	"log/slog"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/urfave/cli/v3"
+	"go.opentelemetry.io/otel/trace"
 )
 
+const (
+	flagNameLogLevel  = "log-level"
+	flagNameLogFormat = "log-format"
+)
+
+const (
+	logFormatJSON   = "json"
+	logFormatText   = "text"
+	logFormatPretty = "pretty"
+)
+
+var logFormats = []string{logFormatJSON, logFormatText, logFormatPretty}
+
+type InvalidLogFormatError struct {
+	V string
+}
+
+func (e *InvalidLogFormatError) Error() string {
+	return fmt.Sprintf("invalid log format: %q", e.V)
+}
+
+func (e *InvalidLogFormatError) Is(other error) bool {
+	otherErr := new(InvalidLogFormatError)
+	if !errors.As(other, &otherErr) {
+		return false
+	}
+	return otherErr.V == e.V
+}
+
+func newLogFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.GenericFlag{
+			Name:  flagNameLogLevel,
+			Usage: "specify minimum log level. accepts valid [slog.Level] string representation.",
+			Value: &logLevel{slog.LevelInfo},
+		},
+		&cli.StringFlag{
+			Name:    flagNameLogFormat,
+			Usage:   "the format of logs: json, text or pretty. defaults to pretty if stderr is a terminal, otherwise json.",
+			Sources: cli.EnvVars("FRONTIER_LOG_FORMAT"),
+			Validator: func(v string) error {
+				if !slices.Contains(logFormats, v) {
+					return &InvalidLogFormatError{V: v}
+				}
+				return nil
+			},
+		},
+	}
+}
+
 type logLevel struct {
 	slog.Level
 }
@@ -26,29 +85,174 @@ func (l *logLevel) Set(v string) error {
 func (l logLevel) Get() any { return l.Level }
 
 func getLogLevel(cmd *cli.Command) slog.Level {
-	sl, ok := cmd.Value(flagLogLevel.Name).(slog.Level)
+	sl, ok := cmd.Value(flagNameLogLevel).(slog.Level)
 	if ok {
 		return sl
 	}
 	return slog.LevelInfo
 }
 
-type leveledHandler struct {
+// newLogHandler returns the handler of the format; the empty format means pretty on a terminal and JSON otherwise.
+func newLogHandler(w io.Writer, format string, level slog.Level) slog.Handler { //nolint:ireturn
+	tty := isTerminal(w)
+	if format == "" {
+		format = logFormatJSON
+		if tty {
+			format = logFormatPretty
+		}
+	}
+	opts := &slog.HandlerOptions{Level: level}
+	var h slog.Handler
+	switch format {
+	case logFormatText:
+		h = slog.NewTextHandler(w, opts)
+	case logFormatPretty:
+		h = &prettyHandler{w: w, mu: new(sync.Mutex), level: level, color: tty && os.Getenv("NO_COLOR") == ""}
+	default:
+		h = slog.NewJSONHandler(w, opts)
+	}
+	return &traceHandler{Handler: h}
+}
+
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	fi, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return fi.Mode()&os.ModeCharDevice != 0
+}
+
+// traceHandler adds the trace ID and the span ID of the context to the records.
+type traceHandler struct {
 	slog.Handler
-	level slog.Level
 }
 
-var _ slog.Handler = (*leveledHandler)(nil)
+var _ slog.Handler = (*traceHandler)(nil)
+
+func (h *traceHandler) Handle(ctx context.Context, r slog.Record) error {
+	if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+		r.AddAttrs(slog.String("trace_id", sc.TraceID().String()), slog.String("span_id", sc.SpanID().String()))
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h *traceHandler) WithAttrs(attrs []slog.Attr) slog.Handler { //nolint:ireturn
+	return &traceHandler{Handler: h.Handler.WithAttrs(attrs)}
+}
+
+func (h *traceHandler) WithGroup(name string) slog.Handler { //nolint:ireturn
+	return &traceHandler{Handler: h.Handler.WithGroup(name)}
+}
 
-func (h *leveledHandler) Enabled(ctx context.Context, level slog.Level) bool {
+const (
+	ansiReset  = "\x1b[0m"
+	ansiFaint  = "\x1b[2m"
+	ansiRed    = "\x1b[31m"
+	ansiYellow = "\x1b[33m"
+	ansiCyan   = "\x1b[36m"
+	ansiGray   = "\x1b[90m"
+)
+
+// prettyHandler writes the records like `15:04:05 INFO  message key=value` for humans.
+type prettyHandler struct {
+	w      io.Writer
+	mu     *sync.Mutex
+	level  slog.Level
+	color  bool
+	attrs  []byte
+	prefix string
+}
+
+var _ slog.Handler = (*prettyHandler)(nil)
+
+func (h *prettyHandler) Enabled(_ context.Context, level slog.Level) bool {
 	return level >= h.level
 }
 
-var loggerMutex sync.Mutex
+func (h *prettyHandler) Handle(_ context.Context, r slog.Record) error {
+	buf := make([]byte, 0, 256)
+	if !r.Time.IsZero() {
+		buf = h.appendColored(buf, ansiFaint, r.Time.Format(time.TimeOnly))
+		buf = append(buf, ' ')
+	}
+	buf = h.appendColored(buf, levelColor(r.Level), fmt.Sprintf("%-5s", r.Level.String()))
+	buf = append(buf, ' ')
+	buf = append(buf, r.Message...)
+	buf = append(buf, h.attrs...)
+	r.Attrs(func(a slog.Attr) bool {
+		buf = h.appendAttr(buf, h.prefix, a)
+		return true
+	})
+	buf = append(buf, '\n')
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	_, err := h.w.Write(buf)
+	return err
+}
+
+func (h *prettyHandler) WithAttrs(attrs []slog.Attr) slog.Handler { //nolint:ireturn
+	h2 := *h
+	h2.attrs = slices.Clip(h.attrs)
+	for _, a := range attrs {
+		h2.attrs = h.appendAttr(h2.attrs, h.prefix, a)
+	}
+	return &h2
+}
+
+func (h *prettyHandler) WithGroup(name string) slog.Handler { //nolint:ireturn
+	if name == "" {
+		return h
+	}
+	h2 := *h
+	h2.prefix = h.prefix + name + "."
+	return &h2
+}
 
-func swapLogger(swapFn func(prev *slog.Logger) *slog.Logger) {
-	loggerMutex.Lock()
-	defer loggerMutex.Unlock()
-	newLogger := swapFn(slog.Default())
-	slog.SetDefault(newLogger)
+func (h *prettyHandler) appendAttr(buf []byte, prefix string, a slog.Attr) []byte {
+	a.Value = a.Value.Resolve()
+	if a.Equal(slog.Attr{}) {
+		return buf
+	}
+	if a.Value.Kind() == slog.KindGroup {
+		if a.Key != "" {
+			prefix += a.Key + "."
+		}
+		for _, ga := range a.Value.Group() {
+			buf = h.appendAttr(buf, prefix, ga)
+		}
+		return buf
+	}
+	buf = append(buf, ' ')
+	buf = h.appendColored(buf, ansiFaint, prefix+a.Key+"=")
+	v := a.Value.String()
+	if v == "" || strings.ContainsAny(v, " \t\n\"=") {
+		v = strconv.Quote(v)
+	}
+	return append(buf, v...)
+}
+
+func (h *prettyHandler) appendColored(buf []byte, color, s string) []byte {
+	if !h.color {
+		return append(buf, s...)
+	}
+	buf = append(buf, color...)
+	buf = append(buf, s...)
+	return append(buf, ansiReset...)
+}
+
+func levelColor(level slog.Level) string {
+	switch {
+	case level >= slog.LevelError:
+		return ansiRed
+	case level >= slog.LevelWarn:
+		return ansiYellow
+	case level >= slog.LevelInfo:
+		return ansiCyan
+	default:
+		return ansiGray
+	}
 }
@@ -7,11 +7,13 @@ package cli
 import (
 	context "context"
 	io "io"
+	iter "iter"
 	reflect "reflect"
 
 	frontier "github.com/aereal/frontier"
+	cf "github.com/aereal/frontier/cf"
 	listdist "github.com/aereal/frontier/controller/listdist"
-	fnarn "github.com/aereal/frontier/internal/fnarn"
+	fnarn "github.com/aereal/frontier/fnarn"
 	gomock "go.uber.org/mock/gomock"
 )
 
@@ -40,17 +42,23 @@ func (m *MockDeployController) EXPECT() *MockDeployControllerMockRecorder {
 }
 
 // Deploy mocks base method.
-func (m *MockDeployController) Deploy(ctx context.Context, configPath string, publish bool) error {
+func (m *MockDeployController) Deploy(ctx context.Context, configPath string, publish bool, opts ...frontier.DeployOption) (*frontier.DeployResult, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Deploy", ctx, configPath, publish)
-	ret0, _ := ret[0].(error)
-	return ret0
+	varargs := []any{ctx, configPath, publish}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Deploy", varargs...)
+	ret0, _ := ret[0].(*frontier.DeployResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // Deploy indicates an expected call of Deploy.
-func (mr *MockDeployControllerMockRecorder) Deploy(ctx, configPath, publish any) *MockDeployControllerDeployCall {
+func (mr *MockDeployControllerMockRecorder) Deploy(ctx, configPath, publish any, opts ...any) *MockDeployControllerDeployCall {
 	mr.mock.ctrl.T.Helper()
-	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Deploy", reflect.TypeOf((*MockDeployController)(nil).Deploy), ctx, configPath, publish)
+	varargs := append([]any{ctx, configPath, publish}, opts...)
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Deploy", reflect.TypeOf((*MockDeployController)(nil).Deploy), varargs...)
 	return &MockDeployControllerDeployCall{Call: call}
 }
 
@@ -60,19 +68,81 @@ type MockDeployControllerDeployCall struct {
 }
 
 // Return rewrite *gomock.Call.Return
-func (c *MockDeployControllerDeployCall) Return(arg0 error) *MockDeployControllerDeployCall {
+func (c *MockDeployControllerDeployCall) Return(arg0 *frontier.DeployResult, arg1 error) *MockDeployControllerDeployCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockDeployControllerDeployCall) Do(f func(context.Context, string, bool, ...frontier.DeployOption) (*frontier.DeployResult, error)) *MockDeployControllerDeployCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockDeployControllerDeployCall) DoAndReturn(f func(context.Context, string, bool, ...frontier.DeployOption) (*frontier.DeployResult, error)) *MockDeployControllerDeployCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// MockUnlockController is a mock of UnlockController interface.
+type MockUnlockController struct {
+	ctrl     *gomock.Controller
+	recorder *MockUnlockControllerMockRecorder
+	isgomock struct{}
+}
+
+// MockUnlockControllerMockRecorder is the mock recorder for MockUnlockController.
+type MockUnlockControllerMockRecorder struct {
+	mock *MockUnlockController
+}
+
+// NewMockUnlockController creates a new mock instance.
+func NewMockUnlockController(ctrl *gomock.Controller) *MockUnlockController {
+	mock := &MockUnlockController{ctrl: ctrl}
+	mock.recorder = &MockUnlockControllerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUnlockController) EXPECT() *MockUnlockControllerMockRecorder {
+	return m.recorder
+}
+
+// Unlock mocks base method.
+func (m *MockUnlockController) Unlock(ctx context.Context, functionName string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Unlock", ctx, functionName)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Unlock indicates an expected call of Unlock.
+func (mr *MockUnlockControllerMockRecorder) Unlock(ctx, functionName any) *MockUnlockControllerUnlockCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Unlock", reflect.TypeOf((*MockUnlockController)(nil).Unlock), ctx, functionName)
+	return &MockUnlockControllerUnlockCall{Call: call}
+}
+
+// MockUnlockControllerUnlockCall wrap *gomock.Call
+type MockUnlockControllerUnlockCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockUnlockControllerUnlockCall) Return(arg0 error) *MockUnlockControllerUnlockCall {
 	c.Call = c.Call.Return(arg0)
 	return c
 }
 
 // Do rewrite *gomock.Call.Do
-func (c *MockDeployControllerDeployCall) Do(f func(context.Context, string, bool) error) *MockDeployControllerDeployCall {
+func (c *MockUnlockControllerUnlockCall) Do(f func(context.Context, string) error) *MockUnlockControllerUnlockCall {
 	c.Call = c.Call.Do(f)
 	return c
 }
 
 // DoAndReturn rewrite *gomock.Call.DoAndReturn
-func (c *MockDeployControllerDeployCall) DoAndReturn(f func(context.Context, string, bool) error) *MockDeployControllerDeployCall {
+func (c *MockUnlockControllerUnlockCall) DoAndReturn(f func(context.Context, string) error) *MockUnlockControllerUnlockCall {
 	c.Call = c.Call.DoAndReturn(f)
 	return c
 }
@@ -102,17 +172,23 @@ func (m *MockImportController) EXPECT() *MockImportControllerMockRecorder {
 }
 
 // Import mocks base method.
-func (m *MockImportController) Import(ctx context.Context, functionName string, configStream io.Writer, functionStream *frontier.WritableFile) error {
+func (m *MockImportController) Import(ctx context.Context, functionName string, configStream io.Writer, functionStream *frontier.WritableFile, opts ...frontier.ImportOption) (*frontier.ImportResult, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Import", ctx, functionName, configStream, functionStream)
-	ret0, _ := ret[0].(error)
-	return ret0
+	varargs := []any{ctx, functionName, configStream, functionStream}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Import", varargs...)
+	ret0, _ := ret[0].(*frontier.ImportResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // Import indicates an expected call of Import.
-func (mr *MockImportControllerMockRecorder) Import(ctx, functionName, configStream, functionStream any) *MockImportControllerImportCall {
+func (mr *MockImportControllerMockRecorder) Import(ctx, functionName, configStream, functionStream any, opts ...any) *MockImportControllerImportCall {
 	mr.mock.ctrl.T.Helper()
-	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Import", reflect.TypeOf((*MockImportController)(nil).Import), ctx, functionName, configStream, functionStream)
+	varargs := append([]any{ctx, functionName, configStream, functionStream}, opts...)
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Import", reflect.TypeOf((*MockImportController)(nil).Import), varargs...)
 	return &MockImportControllerImportCall{Call: call}
 }
 
@@ -122,19 +198,19 @@ type MockImportControllerImportCall struct {
 }
 
 // Return rewrite *gomock.Call.Return
-func (c *MockImportControllerImportCall) Return(arg0 error) *MockImportControllerImportCall {
-	c.Call = c.Call.Return(arg0)
+func (c *MockImportControllerImportCall) Return(arg0 *frontier.ImportResult, arg1 error) *MockImportControllerImportCall {
+	c.Call = c.Call.Return(arg0, arg1)
 	return c
 }
 
 // Do rewrite *gomock.Call.Do
-func (c *MockImportControllerImportCall) Do(f func(context.Context, string, io.Writer, *frontier.WritableFile) error) *MockImportControllerImportCall {
+func (c *MockImportControllerImportCall) Do(f func(context.Context, string, io.Writer, *frontier.WritableFile, ...frontier.ImportOption) (*frontier.ImportResult, error)) *MockImportControllerImportCall {
 	c.Call = c.Call.Do(f)
 	return c
 }
 
 // DoAndReturn rewrite *gomock.Call.DoAndReturn
-func (c *MockImportControllerImportCall) DoAndReturn(f func(context.Context, string, io.Writer, *frontier.WritableFile) error) *MockImportControllerImportCall {
+func (c *MockImportControllerImportCall) DoAndReturn(f func(context.Context, string, io.Writer, *frontier.WritableFile, ...frontier.ImportOption) (*frontier.ImportResult, error)) *MockImportControllerImportCall {
 	c.Call = c.Call.DoAndReturn(f)
 	return c
 }
@@ -201,6 +277,136 @@ func (c *MockRenderControllerRenderCall) DoAndReturn(f func(context.Context, str
 	return c
 }
 
+// MockExportController is a mock of ExportController interface.
+type MockExportController struct {
+	ctrl     *gomock.Controller
+	recorder *MockExportControllerMockRecorder
+	isgomock struct{}
+}
+
+// MockExportControllerMockRecorder is the mock recorder for MockExportController.
+type MockExportControllerMockRecorder struct {
+	mock *MockExportController
+}
+
+// NewMockExportController creates a new mock instance.
+func NewMockExportController(ctrl *gomock.Controller) *MockExportController {
+	mock := &MockExportController{ctrl: ctrl}
+	mock.recorder = &MockExportControllerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockExportController) EXPECT() *MockExportControllerMockRecorder {
+	return m.recorder
+}
+
+// Export mocks base method.
+func (m *MockExportController) Export(ctx context.Context, configPath string, format frontier.ExportFormat, output io.Writer) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Export", ctx, configPath, format, output)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Export indicates an expected call of Export.
+func (mr *MockExportControllerMockRecorder) Export(ctx, configPath, format, output any) *MockExportControllerExportCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Export", reflect.TypeOf((*MockExportController)(nil).Export), ctx, configPath, format, output)
+	return &MockExportControllerExportCall{Call: call}
+}
+
+// MockExportControllerExportCall wrap *gomock.Call
+type MockExportControllerExportCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockExportControllerExportCall) Return(arg0 error) *MockExportControllerExportCall {
+	c.Call = c.Call.Return(arg0)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockExportControllerExportCall) Do(f func(context.Context, string, frontier.ExportFormat, io.Writer) error) *MockExportControllerExportCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockExportControllerExportCall) DoAndReturn(f func(context.Context, string, frontier.ExportFormat, io.Writer) error) *MockExportControllerExportCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// MockDriftController is a mock of DriftController interface.
+type MockDriftController struct {
+	ctrl     *gomock.Controller
+	recorder *MockDriftControllerMockRecorder
+	isgomock struct{}
+}
+
+// MockDriftControllerMockRecorder is the mock recorder for MockDriftController.
+type MockDriftControllerMockRecorder struct {
+	mock *MockDriftController
+}
+
+// NewMockDriftController creates a new mock instance.
+func NewMockDriftController(ctrl *gomock.Controller) *MockDriftController {
+	mock := &MockDriftController{ctrl: ctrl}
+	mock.recorder = &MockDriftControllerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDriftController) EXPECT() *MockDriftControllerMockRecorder {
+	return m.recorder
+}
+
+// DetectDrift mocks base method.
+func (m *MockDriftController) DetectDrift(ctx context.Context, configPath string, opts ...frontier.DriftOption) (*frontier.DriftReport, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, configPath}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DetectDrift", varargs...)
+	ret0, _ := ret[0].(*frontier.DriftReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DetectDrift indicates an expected call of DetectDrift.
+func (mr *MockDriftControllerMockRecorder) DetectDrift(ctx, configPath any, opts ...any) *MockDriftControllerDetectDriftCall {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, configPath}, opts...)
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DetectDrift", reflect.TypeOf((*MockDriftController)(nil).DetectDrift), varargs...)
+	return &MockDriftControllerDetectDriftCall{Call: call}
+}
+
+// MockDriftControllerDetectDriftCall wrap *gomock.Call
+type MockDriftControllerDetectDriftCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockDriftControllerDetectDriftCall) Return(arg0 *frontier.DriftReport, arg1 error) *MockDriftControllerDetectDriftCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockDriftControllerDetectDriftCall) Do(f func(context.Context, string, ...frontier.DriftOption) (*frontier.DriftReport, error)) *MockDriftControllerDetectDriftCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockDriftControllerDetectDriftCall) DoAndReturn(f func(context.Context, string, ...frontier.DriftOption) (*frontier.DriftReport, error)) *MockDriftControllerDetectDriftCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
 // MockListDistributionsController is a mock of ListDistributionsController interface.
 type MockListDistributionsController struct {
 	ctrl     *gomock.Controller
@@ -226,18 +432,22 @@ func (m *MockListDistributionsController) EXPECT() *MockListDistributionsControl
 }
 
 // ListDistributions mocks base method.
-func (m *MockListDistributionsController) ListDistributions(ctx context.Context, output io.Writer, criteria *listdist.Criteria) ([]frontier.FunctionAssociation, error) {
+func (m *MockListDistributionsController) ListDistributions(ctx context.Context, output io.Writer, criteria *listdist.Criteria, opts ...listdist.ListDistributionsOption) iter.Seq2[frontier.FunctionAssociation, error] {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListDistributions", ctx, output, criteria)
-	ret0, _ := ret[0].([]frontier.FunctionAssociation)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	varargs := []any{ctx, output, criteria}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ListDistributions", varargs...)
+	ret0, _ := ret[0].(iter.Seq2[frontier.FunctionAssociation, error])
+	return ret0
 }
 
 // ListDistributions indicates an expected call of ListDistributions.
-func (mr *MockListDistributionsControllerMockRecorder) ListDistributions(ctx, output, criteria any) *MockListDistributionsControllerListDistributionsCall {
+func (mr *MockListDistributionsControllerMockRecorder) ListDistributions(ctx, output, criteria any, opts ...any) *MockListDistributionsControllerListDistributionsCall {
 	mr.mock.ctrl.T.Helper()
-	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDistributions", reflect.TypeOf((*MockListDistributionsController)(nil).ListDistributions), ctx, output, criteria)
+	varargs := append([]any{ctx, output, criteria}, opts...)
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDistributions", reflect.TypeOf((*MockListDistributionsController)(nil).ListDistributions), varargs...)
 	return &MockListDistributionsControllerListDistributionsCall{Call: call}
 }
 
@@ -247,19 +457,637 @@ type MockListDistributionsControllerListDistributionsCall struct {
 }
 
 // Return rewrite *gomock.Call.Return
-func (c *MockListDistributionsControllerListDistributionsCall) Return(arg0 []frontier.FunctionAssociation, arg1 error) *MockListDistributionsControllerListDistributionsCall {
+func (c *MockListDistributionsControllerListDistributionsCall) Return(arg0 iter.Seq2[frontier.FunctionAssociation, error]) *MockListDistributionsControllerListDistributionsCall {
+	c.Call = c.Call.Return(arg0)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockListDistributionsControllerListDistributionsCall) Do(f func(context.Context, io.Writer, *listdist.Criteria, ...listdist.ListDistributionsOption) iter.Seq2[frontier.FunctionAssociation, error]) *MockListDistributionsControllerListDistributionsCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockListDistributionsControllerListDistributionsCall) DoAndReturn(f func(context.Context, io.Writer, *listdist.Criteria, ...listdist.ListDistributionsOption) iter.Seq2[frontier.FunctionAssociation, error]) *MockListDistributionsControllerListDistributionsCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// MockWatchController is a mock of WatchController interface.
+type MockWatchController struct {
+	ctrl     *gomock.Controller
+	recorder *MockWatchControllerMockRecorder
+	isgomock struct{}
+}
+
+// MockWatchControllerMockRecorder is the mock recorder for MockWatchController.
+type MockWatchControllerMockRecorder struct {
+	mock *MockWatchController
+}
+
+// NewMockWatchController creates a new mock instance.
+func NewMockWatchController(ctrl *gomock.Controller) *MockWatchController {
+	mock := &MockWatchController{ctrl: ctrl}
+	mock.recorder = &MockWatchControllerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWatchController) EXPECT() *MockWatchControllerMockRecorder {
+	return m.recorder
+}
+
+// Watch mocks base method.
+func (m *MockWatchController) Watch(ctx context.Context, configPath string, opts ...frontier.WatchOption) iter.Seq2[*frontier.WatchResult, error] {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, configPath}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Watch", varargs...)
+	ret0, _ := ret[0].(iter.Seq2[*frontier.WatchResult, error])
+	return ret0
+}
+
+// Watch indicates an expected call of Watch.
+func (mr *MockWatchControllerMockRecorder) Watch(ctx, configPath any, opts ...any) *MockWatchControllerWatchCall {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, configPath}, opts...)
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Watch", reflect.TypeOf((*MockWatchController)(nil).Watch), varargs...)
+	return &MockWatchControllerWatchCall{Call: call}
+}
+
+// MockWatchControllerWatchCall wrap *gomock.Call
+type MockWatchControllerWatchCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockWatchControllerWatchCall) Return(arg0 iter.Seq2[*frontier.WatchResult, error]) *MockWatchControllerWatchCall {
+	c.Call = c.Call.Return(arg0)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockWatchControllerWatchCall) Do(f func(context.Context, string, ...frontier.WatchOption) iter.Seq2[*frontier.WatchResult, error]) *MockWatchControllerWatchCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockWatchControllerWatchCall) DoAndReturn(f func(context.Context, string, ...frontier.WatchOption) iter.Seq2[*frontier.WatchResult, error]) *MockWatchControllerWatchCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// MockCompareController is a mock of CompareController interface.
+type MockCompareController struct {
+	ctrl     *gomock.Controller
+	recorder *MockCompareControllerMockRecorder
+	isgomock struct{}
+}
+
+// MockCompareControllerMockRecorder is the mock recorder for MockCompareController.
+type MockCompareControllerMockRecorder struct {
+	mock *MockCompareController
+}
+
+// NewMockCompareController creates a new mock instance.
+func NewMockCompareController(ctrl *gomock.Controller) *MockCompareController {
+	mock := &MockCompareController{ctrl: ctrl}
+	mock.recorder = &MockCompareControllerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCompareController) EXPECT() *MockCompareControllerMockRecorder {
+	return m.recorder
+}
+
+// Compare mocks base method.
+func (m *MockCompareController) Compare(ctx context.Context, a, b *frontier.CompareTarget) (*frontier.CompareResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Compare", ctx, a, b)
+	ret0, _ := ret[0].(*frontier.CompareResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Compare indicates an expected call of Compare.
+func (mr *MockCompareControllerMockRecorder) Compare(ctx, a, b any) *MockCompareControllerCompareCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Compare", reflect.TypeOf((*MockCompareController)(nil).Compare), ctx, a, b)
+	return &MockCompareControllerCompareCall{Call: call}
+}
+
+// MockCompareControllerCompareCall wrap *gomock.Call
+type MockCompareControllerCompareCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockCompareControllerCompareCall) Return(arg0 *frontier.CompareResult, arg1 error) *MockCompareControllerCompareCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockCompareControllerCompareCall) Do(f func(context.Context, *frontier.CompareTarget, *frontier.CompareTarget) (*frontier.CompareResult, error)) *MockCompareControllerCompareCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockCompareControllerCompareCall) DoAndReturn(f func(context.Context, *frontier.CompareTarget, *frontier.CompareTarget) (*frontier.CompareResult, error)) *MockCompareControllerCompareCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// MockPromoteController is a mock of PromoteController interface.
+type MockPromoteController struct {
+	ctrl     *gomock.Controller
+	recorder *MockPromoteControllerMockRecorder
+	isgomock struct{}
+}
+
+// MockPromoteControllerMockRecorder is the mock recorder for MockPromoteController.
+type MockPromoteControllerMockRecorder struct {
+	mock *MockPromoteController
+}
+
+// NewMockPromoteController creates a new mock instance.
+func NewMockPromoteController(ctrl *gomock.Controller) *MockPromoteController {
+	mock := &MockPromoteController{ctrl: ctrl}
+	mock.recorder = &MockPromoteControllerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPromoteController) EXPECT() *MockPromoteControllerMockRecorder {
+	return m.recorder
+}
+
+// Promote mocks base method.
+func (m *MockPromoteController) Promote(ctx context.Context, source, target *frontier.PromoteEndpoint, publish bool, opts ...frontier.DeployOption) (*frontier.PromoteResult, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, source, target, publish}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Promote", varargs...)
+	ret0, _ := ret[0].(*frontier.PromoteResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Promote indicates an expected call of Promote.
+func (mr *MockPromoteControllerMockRecorder) Promote(ctx, source, target, publish any, opts ...any) *MockPromoteControllerPromoteCall {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, source, target, publish}, opts...)
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Promote", reflect.TypeOf((*MockPromoteController)(nil).Promote), varargs...)
+	return &MockPromoteControllerPromoteCall{Call: call}
+}
+
+// MockPromoteControllerPromoteCall wrap *gomock.Call
+type MockPromoteControllerPromoteCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockPromoteControllerPromoteCall) Return(arg0 *frontier.PromoteResult, arg1 error) *MockPromoteControllerPromoteCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockPromoteControllerPromoteCall) Do(f func(context.Context, *frontier.PromoteEndpoint, *frontier.PromoteEndpoint, bool, ...frontier.DeployOption) (*frontier.PromoteResult, error)) *MockPromoteControllerPromoteCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockPromoteControllerPromoteCall) DoAndReturn(f func(context.Context, *frontier.PromoteEndpoint, *frontier.PromoteEndpoint, bool, ...frontier.DeployOption) (*frontier.PromoteResult, error)) *MockPromoteControllerPromoteCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// MockCanaryController is a mock of CanaryController interface.
+type MockCanaryController struct {
+	ctrl     *gomock.Controller
+	recorder *MockCanaryControllerMockRecorder
+	isgomock struct{}
+}
+
+// MockCanaryControllerMockRecorder is the mock recorder for MockCanaryController.
+type MockCanaryControllerMockRecorder struct {
+	mock *MockCanaryController
+}
+
+// NewMockCanaryController creates a new mock instance.
+func NewMockCanaryController(ctrl *gomock.Controller) *MockCanaryController {
+	mock := &MockCanaryController{ctrl: ctrl}
+	mock.recorder = &MockCanaryControllerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCanaryController) EXPECT() *MockCanaryControllerMockRecorder {
+	return m.recorder
+}
+
+// AbortCanary mocks base method.
+func (m *MockCanaryController) AbortCanary(ctx context.Context, configPath, distributionID string, opts ...frontier.CanaryOption) (*frontier.CanaryResult, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, configPath, distributionID}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "AbortCanary", varargs...)
+	ret0, _ := ret[0].(*frontier.CanaryResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AbortCanary indicates an expected call of AbortCanary.
+func (mr *MockCanaryControllerMockRecorder) AbortCanary(ctx, configPath, distributionID any, opts ...any) *MockCanaryControllerAbortCanaryCall {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, configPath, distributionID}, opts...)
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AbortCanary", reflect.TypeOf((*MockCanaryController)(nil).AbortCanary), varargs...)
+	return &MockCanaryControllerAbortCanaryCall{Call: call}
+}
+
+// MockCanaryControllerAbortCanaryCall wrap *gomock.Call
+type MockCanaryControllerAbortCanaryCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockCanaryControllerAbortCanaryCall) Return(arg0 *frontier.CanaryResult, arg1 error) *MockCanaryControllerAbortCanaryCall {
 	c.Call = c.Call.Return(arg0, arg1)
 	return c
 }
 
 // Do rewrite *gomock.Call.Do
-func (c *MockListDistributionsControllerListDistributionsCall) Do(f func(context.Context, io.Writer, *listdist.Criteria) ([]frontier.FunctionAssociation, error)) *MockListDistributionsControllerListDistributionsCall {
+func (c *MockCanaryControllerAbortCanaryCall) Do(f func(context.Context, string, string, ...frontier.CanaryOption) (*frontier.CanaryResult, error)) *MockCanaryControllerAbortCanaryCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockCanaryControllerAbortCanaryCall) DoAndReturn(f func(context.Context, string, string, ...frontier.CanaryOption) (*frontier.CanaryResult, error)) *MockCanaryControllerAbortCanaryCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// PromoteCanary mocks base method.
+func (m *MockCanaryController) PromoteCanary(ctx context.Context, configPath, distributionID string, opts ...frontier.CanaryOption) (*frontier.CanaryResult, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, configPath, distributionID}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "PromoteCanary", varargs...)
+	ret0, _ := ret[0].(*frontier.CanaryResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PromoteCanary indicates an expected call of PromoteCanary.
+func (mr *MockCanaryControllerMockRecorder) PromoteCanary(ctx, configPath, distributionID any, opts ...any) *MockCanaryControllerPromoteCanaryCall {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, configPath, distributionID}, opts...)
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PromoteCanary", reflect.TypeOf((*MockCanaryController)(nil).PromoteCanary), varargs...)
+	return &MockCanaryControllerPromoteCanaryCall{Call: call}
+}
+
+// MockCanaryControllerPromoteCanaryCall wrap *gomock.Call
+type MockCanaryControllerPromoteCanaryCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockCanaryControllerPromoteCanaryCall) Return(arg0 *frontier.CanaryResult, arg1 error) *MockCanaryControllerPromoteCanaryCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockCanaryControllerPromoteCanaryCall) Do(f func(context.Context, string, string, ...frontier.CanaryOption) (*frontier.CanaryResult, error)) *MockCanaryControllerPromoteCanaryCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockCanaryControllerPromoteCanaryCall) DoAndReturn(f func(context.Context, string, string, ...frontier.CanaryOption) (*frontier.CanaryResult, error)) *MockCanaryControllerPromoteCanaryCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// StartCanary mocks base method.
+func (m *MockCanaryController) StartCanary(ctx context.Context, configPath, distributionID string, traffic *frontier.CanaryTraffic, opts ...frontier.CanaryOption) (*frontier.CanaryResult, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, configPath, distributionID, traffic}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "StartCanary", varargs...)
+	ret0, _ := ret[0].(*frontier.CanaryResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StartCanary indicates an expected call of StartCanary.
+func (mr *MockCanaryControllerMockRecorder) StartCanary(ctx, configPath, distributionID, traffic any, opts ...any) *MockCanaryControllerStartCanaryCall {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, configPath, distributionID, traffic}, opts...)
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartCanary", reflect.TypeOf((*MockCanaryController)(nil).StartCanary), varargs...)
+	return &MockCanaryControllerStartCanaryCall{Call: call}
+}
+
+// MockCanaryControllerStartCanaryCall wrap *gomock.Call
+type MockCanaryControllerStartCanaryCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockCanaryControllerStartCanaryCall) Return(arg0 *frontier.CanaryResult, arg1 error) *MockCanaryControllerStartCanaryCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockCanaryControllerStartCanaryCall) Do(f func(context.Context, string, string, *frontier.CanaryTraffic, ...frontier.CanaryOption) (*frontier.CanaryResult, error)) *MockCanaryControllerStartCanaryCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockCanaryControllerStartCanaryCall) DoAndReturn(f func(context.Context, string, string, *frontier.CanaryTraffic, ...frontier.CanaryOption) (*frontier.CanaryResult, error)) *MockCanaryControllerStartCanaryCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// MockStatsController is a mock of StatsController interface.
+type MockStatsController struct {
+	ctrl     *gomock.Controller
+	recorder *MockStatsControllerMockRecorder
+	isgomock struct{}
+}
+
+// MockStatsControllerMockRecorder is the mock recorder for MockStatsController.
+type MockStatsControllerMockRecorder struct {
+	mock *MockStatsController
+}
+
+// NewMockStatsController creates a new mock instance.
+func NewMockStatsController(ctrl *gomock.Controller) *MockStatsController {
+	mock := &MockStatsController{ctrl: ctrl}
+	mock.recorder = &MockStatsControllerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockStatsController) EXPECT() *MockStatsControllerMockRecorder {
+	return m.recorder
+}
+
+// Stats mocks base method.
+func (m *MockStatsController) Stats(ctx context.Context, configPath string, opts ...frontier.StatsOption) (*frontier.FunctionStats, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, configPath}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Stats", varargs...)
+	ret0, _ := ret[0].(*frontier.FunctionStats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Stats indicates an expected call of Stats.
+func (mr *MockStatsControllerMockRecorder) Stats(ctx, configPath any, opts ...any) *MockStatsControllerStatsCall {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, configPath}, opts...)
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Stats", reflect.TypeOf((*MockStatsController)(nil).Stats), varargs...)
+	return &MockStatsControllerStatsCall{Call: call}
+}
+
+// MockStatsControllerStatsCall wrap *gomock.Call
+type MockStatsControllerStatsCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockStatsControllerStatsCall) Return(arg0 *frontier.FunctionStats, arg1 error) *MockStatsControllerStatsCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockStatsControllerStatsCall) Do(f func(context.Context, string, ...frontier.StatsOption) (*frontier.FunctionStats, error)) *MockStatsControllerStatsCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockStatsControllerStatsCall) DoAndReturn(f func(context.Context, string, ...frontier.StatsOption) (*frontier.FunctionStats, error)) *MockStatsControllerStatsCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// MockLintController is a mock of LintController interface.
+type MockLintController struct {
+	ctrl     *gomock.Controller
+	recorder *MockLintControllerMockRecorder
+	isgomock struct{}
+}
+
+// MockLintControllerMockRecorder is the mock recorder for MockLintController.
+type MockLintControllerMockRecorder struct {
+	mock *MockLintController
+}
+
+// NewMockLintController creates a new mock instance.
+func NewMockLintController(ctrl *gomock.Controller) *MockLintController {
+	mock := &MockLintController{ctrl: ctrl}
+	mock.recorder = &MockLintControllerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLintController) EXPECT() *MockLintControllerMockRecorder {
+	return m.recorder
+}
+
+// Lint mocks base method.
+func (m *MockLintController) Lint(ctx context.Context, configPath string, opts ...frontier.LintOption) (*frontier.LintReport, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, configPath}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Lint", varargs...)
+	ret0, _ := ret[0].(*frontier.LintReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Lint indicates an expected call of Lint.
+func (mr *MockLintControllerMockRecorder) Lint(ctx, configPath any, opts ...any) *MockLintControllerLintCall {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, configPath}, opts...)
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Lint", reflect.TypeOf((*MockLintController)(nil).Lint), varargs...)
+	return &MockLintControllerLintCall{Call: call}
+}
+
+// MockLintControllerLintCall wrap *gomock.Call
+type MockLintControllerLintCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockLintControllerLintCall) Return(arg0 *frontier.LintReport, arg1 error) *MockLintControllerLintCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockLintControllerLintCall) Do(f func(context.Context, string, ...frontier.LintOption) (*frontier.LintReport, error)) *MockLintControllerLintCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockLintControllerLintCall) DoAndReturn(f func(context.Context, string, ...frontier.LintOption) (*frontier.LintReport, error)) *MockLintControllerLintCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// MockInitController is a mock of InitController interface.
+type MockInitController struct {
+	ctrl     *gomock.Controller
+	recorder *MockInitControllerMockRecorder
+	isgomock struct{}
+}
+
+// MockInitControllerMockRecorder is the mock recorder for MockInitController.
+type MockInitControllerMockRecorder struct {
+	mock *MockInitController
+}
+
+// NewMockInitController creates a new mock instance.
+func NewMockInitController(ctrl *gomock.Controller) *MockInitController {
+	mock := &MockInitController{ctrl: ctrl}
+	mock.recorder = &MockInitControllerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockInitController) EXPECT() *MockInitControllerMockRecorder {
+	return m.recorder
+}
+
+// Init mocks base method.
+func (m *MockInitController) Init(ctx context.Context, dir string, template frontier.Template, opts ...frontier.InitOption) (*frontier.InitResult, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, dir, template}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Init", varargs...)
+	ret0, _ := ret[0].(*frontier.InitResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Init indicates an expected call of Init.
+func (mr *MockInitControllerMockRecorder) Init(ctx, dir, template any, opts ...any) *MockInitControllerInitCall {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, dir, template}, opts...)
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Init", reflect.TypeOf((*MockInitController)(nil).Init), varargs...)
+	return &MockInitControllerInitCall{Call: call}
+}
+
+// MockInitControllerInitCall wrap *gomock.Call
+type MockInitControllerInitCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockInitControllerInitCall) Return(arg0 *frontier.InitResult, arg1 error) *MockInitControllerInitCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockInitControllerInitCall) Do(f func(context.Context, string, frontier.Template, ...frontier.InitOption) (*frontier.InitResult, error)) *MockInitControllerInitCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockInitControllerInitCall) DoAndReturn(f func(context.Context, string, frontier.Template, ...frontier.InitOption) (*frontier.InitResult, error)) *MockInitControllerInitCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// MockSDKConfigurer is a mock of SDKConfigurer interface.
+type MockSDKConfigurer struct {
+	ctrl     *gomock.Controller
+	recorder *MockSDKConfigurerMockRecorder
+	isgomock struct{}
+}
+
+// MockSDKConfigurerMockRecorder is the mock recorder for MockSDKConfigurer.
+type MockSDKConfigurerMockRecorder struct {
+	mock *MockSDKConfigurer
+}
+
+// NewMockSDKConfigurer creates a new mock instance.
+func NewMockSDKConfigurer(ctrl *gomock.Controller) *MockSDKConfigurer {
+	mock := &MockSDKConfigurer{ctrl: ctrl}
+	mock.recorder = &MockSDKConfigurerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSDKConfigurer) EXPECT() *MockSDKConfigurerMockRecorder {
+	return m.recorder
+}
+
+// ConfigureSDK mocks base method.
+func (m *MockSDKConfigurer) ConfigureSDK(opts cf.SDKOptions) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "ConfigureSDK", opts)
+}
+
+// ConfigureSDK indicates an expected call of ConfigureSDK.
+func (mr *MockSDKConfigurerMockRecorder) ConfigureSDK(opts any) *MockSDKConfigurerConfigureSDKCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConfigureSDK", reflect.TypeOf((*MockSDKConfigurer)(nil).ConfigureSDK), opts)
+	return &MockSDKConfigurerConfigureSDKCall{Call: call}
+}
+
+// MockSDKConfigurerConfigureSDKCall wrap *gomock.Call
+type MockSDKConfigurerConfigureSDKCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockSDKConfigurerConfigureSDKCall) Return() *MockSDKConfigurerConfigureSDKCall {
+	c.Call = c.Call.Return()
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockSDKConfigurerConfigureSDKCall) Do(f func(cf.SDKOptions)) *MockSDKConfigurerConfigureSDKCall {
 	c.Call = c.Call.Do(f)
 	return c
 }
 
 // DoAndReturn rewrite *gomock.Call.DoAndReturn
-func (c *MockListDistributionsControllerListDistributionsCall) DoAndReturn(f func(context.Context, io.Writer, *listdist.Criteria) ([]frontier.FunctionAssociation, error)) *MockListDistributionsControllerListDistributionsCall {
+func (c *MockSDKConfigurerConfigureSDKCall) DoAndReturn(f func(cf.SDKOptions)) *MockSDKConfigurerConfigureSDKCall {
 	c.Call = c.Call.DoAndReturn(f)
 	return c
 }
@@ -4,9 +4,12 @@ import (
 	"encoding"
 	"errors"
 	"fmt"
+	"io"
 	"maps"
 	"slices"
 
+	"github.com/aereal/frontier/presenter"
+	"github.com/aereal/frontier/presenter/json"
 	"github.com/aereal/iter/seq2"
 	cli "github.com/urfave/cli/v3"
 )
@@ -108,3 +111,42 @@ func (outputFormatCreator) Create(v OutputFormat, ref *OutputFormat, _ cli.NoCon
 func (outputFormatCreator) ToString(v OutputFormat) string {
 	return v.String()
 }
+
+func newResultOutputFlag() cli.Flag {
+	return &cli.FlagBase[OutputFormat, cli.NoConfig, outputFormatCreator]{
+		Name:  flagNameResultOutput,
+		Usage: usageText(slices.Values(AvailableOutputFormatValues()), "print the result in the format"),
+		Value: OutputFormatJSON,
+	}
+}
+
+const flagNameResultOutput = "output"
+
+// getResultOutputFormat returns false if the result should not be printed.
+func getResultOutputFormat(cmd *cli.Command) (OutputFormat, bool) {
+	if !cmd.IsSet(flagNameResultOutput) {
+		return 0, false
+	}
+	format, ok := cmd.Value(flagNameResultOutput).(OutputFormat)
+	if !ok {
+		format = OutputFormatJSON
+	}
+	return format, true
+}
+
+// newResultPresenter returns the presenter that writes the result in the format.
+func newResultPresenter[T any](out io.Writer, format OutputFormat) presenter.ResultPresenter[T] { //nolint:ireturn
+	if format == OutputFormatJSONPretty {
+		return json.NewResultPresenter[T](out, json.Pretty(true))
+	}
+	return json.NewResultPresenter[T](out)
+}
+
+// presentResult writes the result in the format given by --output; nothing is written without the flag.
+func presentResult[T any](cmd *cli.Command, result T) error {
+	format, ok := getResultOutputFormat(cmd)
+	if !ok {
+		return nil
+	}
+	return newResultPresenter[T](cmd.Writer, format).PresentResult(result)
+}
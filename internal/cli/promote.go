@@ -0,0 +1,125 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/aereal/frontier"
+	"github.com/aereal/frontier/cf"
+	"github.com/urfave/cli/v3"
+)
+
+func (a *App) cmdPromote() *cli.Command {
+	return &cli.Command{
+		Name:        "promote",
+		Usage:       "copy the LIVE function to another account or name",
+		Description: "fetch the code and the config of the LIVE stage of the function with --from-profile, and create or update the function with --to-profile. the comment of the target records the ETag and the code hash of the source.",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:     "name",
+				Usage:    "the name of the source function",
+				Required: true,
+			},
+			&cli.StringFlag{
+				Name:  "to-name",
+				Usage: "the name of the target function. defaults to the name of the source function.",
+			},
+			&cli.StringFlag{
+				Name:  "from-profile",
+				Usage: "the shared config profile of the source account. defaults to --profile.",
+			},
+			&cli.StringFlag{
+				Name:  "to-profile",
+				Usage: "the shared config profile of the target account. defaults to --profile.",
+			},
+			&cli.StringSliceFlag{
+				Name:  "key-value-store",
+				Usage: "SOURCE_ARN=TARGET_ARN replaces the key value store associated with the source function in the target. required for each key value store when the accounts differ. can be given multiple times.",
+			},
+			&cli.BoolFlag{
+				Name:  "publish",
+				Usage: "publish the target function after the update",
+			},
+			&cli.DurationFlag{
+				Name:  "lock-wait",
+				Usage: "wait for the deployment of the target function by others up to the duration. zero indicates fail immediately.",
+			},
+			newResultOutputFlag(),
+		},
+		Writer:    a.output,
+		ErrWriter: a.errOutput,
+		Reader:    a.input,
+		Action:    a.actionPromote,
+	}
+}
+
+func (a *App) actionPromote(ctx context.Context, cmd *cli.Command) error {
+	sourceName := cmd.String("name")
+	if sourceName == "" {
+		return ErrFunctionNameRequired
+	}
+	targetName := cmd.String("to-name")
+	if targetName == "" {
+		targetName = sourceName
+	}
+	fromProfile, toProfile := cmd.String("from-profile"), cmd.String("to-profile")
+	if targetName == sourceName && fromProfile == toProfile {
+		return ErrPromoteToItself
+	}
+	kvsARNs, err := parseKeyValueStoreMappings(cmd.StringSlice("key-value-store"))
+	if err != nil {
+		return err
+	}
+	source := &frontier.PromoteEndpoint{Provider: newSDKProvider(cmd, fromProfile), FunctionName: sourceName}
+	target := &frontier.PromoteEndpoint{Provider: newSDKProvider(cmd, toProfile), FunctionName: targetName, KeyValueStoreARNs: kvsARNs}
+	var opts []frontier.DeployOption
+	if wait := cmd.Duration("lock-wait"); wait > 0 {
+		opts = append(opts, frontier.LockWait(wait))
+	}
+	result, err := a.controllers.Promote(ctx, source, target, cmd.Bool("publish"), opts...)
+	if err != nil {
+		return err
+	}
+	return presentResult(cmd, result)
+}
+
+func parseKeyValueStoreMappings(values []string) (map[string]string, error) {
+	mappings := make(map[string]string, len(values))
+	for _, v := range values {
+		sourceARN, targetARN, ok := strings.Cut(v, "=")
+		if !ok || sourceARN == "" || targetARN == "" {
+			return nil, &InvalidKeyValueStoreMappingError{V: v}
+		}
+		mappings[sourceARN] = targetARN
+	}
+	return mappings, nil
+}
+
+type InvalidKeyValueStoreMappingError struct {
+	V string
+}
+
+func (e *InvalidKeyValueStoreMappingError) Error() string {
+	return fmt.Sprintf("invalid key-value-store: %q; SOURCE_ARN=TARGET_ARN is expected", e.V)
+}
+
+func (e *InvalidKeyValueStoreMappingError) Is(other error) bool {
+	otherErr := new(InvalidKeyValueStoreMappingError)
+	if !errors.As(other, &otherErr) {
+		return false
+	}
+	return *otherErr == *e
+}
+
+// newSDKProvider returns the provider configured with the AWS flags, and the profile if given.
+func newSDKProvider(cmd *cli.Command, profile string) *cf.SDKProvider {
+	opts := getSDKOptions(cmd)
+	if profile != "" {
+		opts.Profile = profile
+	}
+	provider := new(cf.SDKProvider)
+	provider.ConfigureSDK(opts)
+	return provider
+}
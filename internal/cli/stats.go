@@ -0,0 +1,69 @@
+package cli
+
+import (
+	"context"
+	"slices"
+
+	"github.com/aereal/frontier"
+	"github.com/aws/aws-sdk-go-v2/service/cloudfront/types"
+	"github.com/urfave/cli/v3"
+)
+
+func (a *App) cmdStats() *cli.Command {
+	return &cli.Command{
+		Name:        "stats",
+		Usage:       "report the code size and the compute utilization of the function against the limits",
+		Description: "compare the size of the local code and the deployed code with limits.maxCodeBytes (or 10 KB), and run TestFunction with testEvents of the function config to measure the compute utilization.",
+		Writer:      a.output,
+		ErrWriter:   a.errOutput,
+		Reader:      a.input,
+		Flags: []cli.Flag{
+			flagConfigPath,
+			&cli.StringFlag{
+				Name:      "stage",
+				Usage:     "the stage of the deployed function to measure: DEVELOPMENT (default) or LIVE",
+				Validator: validateStage,
+			},
+			&cli.StringSliceFlag{
+				Name:  "test-event",
+				Usage: "the event object file to test the function with in addition to testEvents. can be given multiple times.",
+			},
+			&cli.FlagBase[OutputFormat, cli.NoConfig, outputFormatCreator]{
+				Name:  "format",
+				Usage: usageText(slices.Values(AvailableOutputFormatValues()), "output format"),
+				Value: OutputFormatJSON,
+			},
+			&cli.BoolFlag{
+				Name:  "fail-on-exceeded",
+				Usage: "exit with an error if the function exceeds the limits in the function config",
+			},
+		},
+		Action: a.actionStats,
+	}
+}
+
+func (a *App) actionStats(ctx context.Context, cmd *cli.Command) error {
+	var opts []frontier.StatsOption
+	if stage := cmd.String("stage"); stage != "" {
+		opts = append(opts, frontier.StatsStage(types.FunctionStage(stage)))
+	}
+	if paths := cmd.StringSlice("test-event"); len(paths) > 0 {
+		opts = append(opts, frontier.TestEvents(paths...))
+	}
+	stats, err := a.controllers.Stats(ctx, cmd.String(flagConfigPath.Name), opts...)
+	if err != nil {
+		return err
+	}
+	format, ok := cmd.Value("format").(OutputFormat)
+	if !ok {
+		format = OutputFormatJSON
+	}
+	presenter := newResultPresenter[*frontier.FunctionStats](cmd.Writer, format)
+	if err := presenter.PresentResult(stats); err != nil {
+		return err
+	}
+	if len(stats.Exceeded) > 0 && cmd.Bool("fail-on-exceeded") {
+		return ErrLimitExceeded
+	}
+	return nil
+}
@@ -0,0 +1,344 @@
+package cli
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"slices"
+	"strings"
+
+	cli "github.com/urfave/cli/v3"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdoutmetric"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"google.golang.org/grpc/credentials"
+)
+
+const (
+	categoryTelemetry         = "OpenTelemetry"
+	flagNameOtelEndpoint      = "otel-trace-endpoint"
+	flagNameOtelExporter      = "otel-exporter"
+	flagNameOtelProtocol      = "otel-protocol"
+	flagNameOtelHeader        = "otel-header"
+	flagNameOtelCACert        = "otel-ca-cert"
+	flagNameOtelSamplingRatio = "otel-sampling-ratio"
+)
+
+const (
+	otelExporterOTLP    = "otlp"
+	otelExporterStdout  = "stdout"
+	otelExporterConsole = "console" // the name used by OTEL_TRACES_EXPORTER
+	otelExporterNone    = "none"
+
+	otelProtocolGRPC         = "grpc"
+	otelProtocolHTTPProtobuf = "http/protobuf"
+)
+
+var (
+	otelExporters = []string{otelExporterOTLP, otelExporterStdout, otelExporterConsole, otelExporterNone}
+	otelProtocols = []string{otelProtocolGRPC, otelProtocolHTTPProtobuf}
+)
+
+type InvalidTelemetryOptionError struct {
+	Flag string
+	V    string
+}
+
+func (e *InvalidTelemetryOptionError) Error() string {
+	return fmt.Sprintf("invalid %s: %q", e.Flag, e.V)
+}
+
+func (e *InvalidTelemetryOptionError) Is(other error) bool {
+	otherErr := new(InvalidTelemetryOptionError)
+	if !errors.As(other, &otherErr) {
+		return false
+	}
+	return otherErr.Flag == e.Flag && otherErr.V == e.V
+}
+
+// newTelemetryFlags builds only the overrides: the exporters read the other OTEL_EXPORTER_OTLP_* environment variables by themselves.
+func newTelemetryFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:     flagNameOtelEndpoint,
+			Aliases:  []string{"otel-endpoint"},
+			Usage:    "an endpoint (such as localhost:4317 or https://otlp.example.com) to send OpenTelemetry traces and metrics. a bare host:port is connected without TLS.",
+			Category: categoryTelemetry,
+		},
+		&cli.StringFlag{
+			Name:     flagNameOtelExporter,
+			Usage:    "the exporter of traces and metrics: otlp, stdout or none. defaults to otlp if the OTLP endpoint is given, otherwise none.",
+			Sources:  cli.EnvVars("OTEL_TRACES_EXPORTER"),
+			Category: categoryTelemetry,
+			Validator: func(v string) error {
+				if !slices.Contains(otelExporters, v) {
+					return &InvalidTelemetryOptionError{Flag: flagNameOtelExporter, V: v}
+				}
+				return nil
+			},
+		},
+		&cli.StringFlag{
+			Name:     flagNameOtelProtocol,
+			Usage:    "the protocol of the OTLP exporter: grpc or http/protobuf",
+			Value:    otelProtocolGRPC,
+			Sources:  cli.EnvVars("OTEL_EXPORTER_OTLP_TRACES_PROTOCOL", "OTEL_EXPORTER_OTLP_PROTOCOL"),
+			Category: categoryTelemetry,
+			Validator: func(v string) error {
+				if !slices.Contains(otelProtocols, v) {
+					return &InvalidTelemetryOptionError{Flag: flagNameOtelProtocol, V: v}
+				}
+				return nil
+			},
+		},
+		&cli.StringMapFlag{
+			Name:     flagNameOtelHeader,
+			Usage:    "a header (such as key=value) sent with the OTLP requests. can be given multiple times.",
+			Category: categoryTelemetry,
+		},
+		&cli.StringFlag{
+			Name:     flagNameOtelCACert,
+			Usage:    "the PEM file of the CA certificates to verify the OTLP endpoint",
+			Category: categoryTelemetry,
+		},
+		&cli.FloatFlag{
+			Name:     flagNameOtelSamplingRatio,
+			Usage:    "the ratio of the traces sampled, from 0 to 1. the traces started by the parent given in TRACEPARENT follow the parent's decision.",
+			Value:    1,
+			Category: categoryTelemetry,
+			Validator: func(v float64) error {
+				if v < 0 || v > 1 {
+					return &InvalidTelemetryOptionError{Flag: flagNameOtelSamplingRatio, V: fmt.Sprint(v)}
+				}
+				return nil
+			},
+		},
+	}
+}
+
+type telemetryConfig struct {
+	exporter       string
+	protocol       string
+	endpoint       string
+	headers        map[string]string
+	tlsConfig      *tls.Config
+	sampler        sdktrace.Sampler
+	stdoutOutput   io.Writer
+	serviceName    string
+	serviceVersion string
+}
+
+func getTelemetryConfig(cmd *cli.Command, stdoutOutput io.Writer) (*telemetryConfig, error) {
+	cfg := &telemetryConfig{
+		exporter:       cmd.String(flagNameOtelExporter),
+		protocol:       cmd.String(flagNameOtelProtocol),
+		endpoint:       cmd.String(flagNameOtelEndpoint),
+		headers:        cmd.StringMap(flagNameOtelHeader),
+		stdoutOutput:   stdoutOutput,
+		serviceName:    cmd.Name,
+		serviceVersion: cmd.Version,
+	}
+	switch cfg.exporter {
+	case "":
+		cfg.exporter = otelExporterNone
+		if cfg.endpoint != "" || os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT") != "" || os.Getenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT") != "" {
+			cfg.exporter = otelExporterOTLP
+		}
+	case otelExporterConsole:
+		cfg.exporter = otelExporterStdout
+	}
+	if path := cmd.String(flagNameOtelCACert); path != "" {
+		pem, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the CA certificates: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, &InvalidTelemetryOptionError{Flag: flagNameOtelCACert, V: path}
+		}
+		cfg.tlsConfig = &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12}
+	}
+	if cmd.IsSet(flagNameOtelSamplingRatio) {
+		cfg.sampler = sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cmd.Float(flagNameOtelSamplingRatio)))
+	}
+	return cfg, nil
+}
+
+func (a *App) configureTelemetry(ctx context.Context, cmd *cli.Command) (context.Context, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+	// nest the spans under the trace of the caller such as the CI pipeline
+	ctx = otel.GetTextMapPropagator().Extract(ctx, environCarrier{})
+
+	cfg, err := getTelemetryConfig(cmd, a.errOutput)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.exporter == otelExporterNone {
+		return ctx, nil
+	}
+
+	slog.InfoContext(ctx, "set OTel exporter", slog.String("exporter", cfg.exporter), slog.String("protocol", cfg.protocol), slog.String("endpoint", cfg.endpoint))
+
+	res, err := resource.New(ctx,
+		resource.WithAttributes(
+			semconv.ServiceName(cfg.serviceName),
+			semconv.ServiceVersion(cfg.serviceVersion),
+		),
+		resource.WithFromEnv(),
+	)
+	if err != nil {
+		slog.WarnContext(ctx, "failed to build OTel resource", slog.String("error", err.Error()))
+	}
+
+	spanExporter, err := cfg.newSpanExporter(ctx)
+	if err != nil {
+		slog.WarnContext(ctx, "failed to setup span exporter", slog.String("error", err.Error()))
+		return ctx, nil
+	}
+	tpOpts := []sdktrace.TracerProviderOption{
+		sdktrace.WithBatcher(spanExporter),
+		sdktrace.WithResource(res),
+	}
+	if cfg.sampler != nil {
+		tpOpts = append(tpOpts, sdktrace.WithSampler(cfg.sampler))
+	}
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(tpOpts...))
+
+	metricExporter, err := cfg.newMetricExporter(ctx)
+	if err != nil {
+		slog.WarnContext(ctx, "failed to setup metric exporter", slog.String("error", err.Error()))
+		return ctx, nil
+	}
+	otel.SetMeterProvider(sdkmetric.NewMeterProvider(
+		sdkmetric.WithReader(sdkmetric.NewPeriodicReader(metricExporter)),
+		sdkmetric.WithResource(res),
+	))
+	return ctx, nil
+}
+
+// hasScheme reports whether the endpoint is given as an URL; otherwise it is a host:port connected without TLS to keep the earlier behavior.
+func (c *telemetryConfig) hasScheme() bool {
+	return strings.Contains(c.endpoint, "://")
+}
+
+func (c *telemetryConfig) newSpanExporter(ctx context.Context) (sdktrace.SpanExporter, error) { //nolint:ireturn
+	switch {
+	case c.exporter == otelExporterStdout:
+		return stdouttrace.New(stdouttrace.WithWriter(c.stdoutOutput))
+	case c.exporter == otelExporterOTLP && c.protocol == otelProtocolHTTPProtobuf:
+		var opts []otlptracehttp.Option
+		switch {
+		case c.endpoint == "":
+		case c.hasScheme():
+			opts = append(opts, otlptracehttp.WithEndpointURL(c.endpoint))
+		case c.tlsConfig == nil:
+			opts = append(opts, otlptracehttp.WithEndpoint(c.endpoint), otlptracehttp.WithInsecure())
+		default:
+			opts = append(opts, otlptracehttp.WithEndpoint(c.endpoint))
+		}
+		if len(c.headers) > 0 {
+			opts = append(opts, otlptracehttp.WithHeaders(c.headers))
+		}
+		if c.tlsConfig != nil {
+			opts = append(opts, otlptracehttp.WithTLSClientConfig(c.tlsConfig))
+		}
+		return otlptracehttp.New(ctx, opts...)
+	case c.exporter == otelExporterOTLP && c.protocol == otelProtocolGRPC:
+		var opts []otlptracegrpc.Option
+		switch {
+		case c.endpoint == "":
+		case c.hasScheme():
+			opts = append(opts, otlptracegrpc.WithEndpointURL(c.endpoint))
+		case c.tlsConfig == nil:
+			opts = append(opts, otlptracegrpc.WithEndpoint(c.endpoint), otlptracegrpc.WithInsecure())
+		default:
+			opts = append(opts, otlptracegrpc.WithEndpoint(c.endpoint))
+		}
+		if len(c.headers) > 0 {
+			opts = append(opts, otlptracegrpc.WithHeaders(c.headers))
+		}
+		if c.tlsConfig != nil {
+			opts = append(opts, otlptracegrpc.WithTLSCredentials(credentials.NewTLS(c.tlsConfig)))
+		}
+		return otlptracegrpc.New(ctx, opts...)
+	default:
+		return nil, c.invalidOptionError()
+	}
+}
+
+func (c *telemetryConfig) newMetricExporter(ctx context.Context) (sdkmetric.Exporter, error) { //nolint:ireturn
+	switch {
+	case c.exporter == otelExporterStdout:
+		return stdoutmetric.New(stdoutmetric.WithWriter(c.stdoutOutput))
+	case c.exporter == otelExporterOTLP && c.protocol == otelProtocolHTTPProtobuf:
+		var opts []otlpmetrichttp.Option
+		switch {
+		case c.endpoint == "":
+		case c.hasScheme():
+			opts = append(opts, otlpmetrichttp.WithEndpointURL(c.endpoint))
+		case c.tlsConfig == nil:
+			opts = append(opts, otlpmetrichttp.WithEndpoint(c.endpoint), otlpmetrichttp.WithInsecure())
+		default:
+			opts = append(opts, otlpmetrichttp.WithEndpoint(c.endpoint))
+		}
+		if len(c.headers) > 0 {
+			opts = append(opts, otlpmetrichttp.WithHeaders(c.headers))
+		}
+		if c.tlsConfig != nil {
+			opts = append(opts, otlpmetrichttp.WithTLSClientConfig(c.tlsConfig))
+		}
+		return otlpmetrichttp.New(ctx, opts...)
+	case c.exporter == otelExporterOTLP && c.protocol == otelProtocolGRPC:
+		var opts []otlpmetricgrpc.Option
+		switch {
+		case c.endpoint == "":
+		case c.hasScheme():
+			opts = append(opts, otlpmetricgrpc.WithEndpointURL(c.endpoint))
+		case c.tlsConfig == nil:
+			opts = append(opts, otlpmetricgrpc.WithEndpoint(c.endpoint), otlpmetricgrpc.WithInsecure())
+		default:
+			opts = append(opts, otlpmetricgrpc.WithEndpoint(c.endpoint))
+		}
+		if len(c.headers) > 0 {
+			opts = append(opts, otlpmetricgrpc.WithHeaders(c.headers))
+		}
+		if c.tlsConfig != nil {
+			opts = append(opts, otlpmetricgrpc.WithTLSCredentials(credentials.NewTLS(c.tlsConfig)))
+		}
+		return otlpmetricgrpc.New(ctx, opts...)
+	default:
+		return nil, c.invalidOptionError()
+	}
+}
+
+// invalidOptionError tells the unknown exporter or protocol given by the environment variables, which are not validated as the flags are.
+func (c *telemetryConfig) invalidOptionError() error {
+	if c.exporter != otelExporterOTLP {
+		return &InvalidTelemetryOptionError{Flag: flagNameOtelExporter, V: c.exporter}
+	}
+	return &InvalidTelemetryOptionError{Flag: flagNameOtelProtocol, V: c.protocol}
+}
+
+// environCarrier reads the trace context from the environment variables such as TRACEPARENT and TRACESTATE.
+type environCarrier struct{}
+
+var _ propagation.TextMapCarrier = environCarrier{}
+
+func (environCarrier) Get(key string) string { return os.Getenv(strings.ToUpper(key)) }
+
+func (environCarrier) Set(string, string) {}
+
+func (environCarrier) Keys() []string { return []string{"traceparent", "tracestate", "baggage"} }
@@ -0,0 +1,74 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/aereal/frontier"
+	"github.com/urfave/cli/v3"
+)
+
+func (a *App) cmdWatch() *cli.Command {
+	return &cli.Command{
+		Name:        "watch",
+		Description: "deploy the function to the DEVELOPMENT stage without publishing each time the config, the code or the files listed in `watch` change",
+		Writer:      a.output,
+		ErrWriter:   a.errOutput,
+		Reader:      a.input,
+		Flags: []cli.Flag{
+			flagConfigPath,
+			&cli.StringSliceFlag{
+				Name:  "test-event",
+				Usage: "the event object file to test the function with after each deployment. can be given multiple times.",
+			},
+			&cli.DurationFlag{
+				Name:  "interval",
+				Usage: "interval to check whether the files are changed",
+			},
+			&cli.DurationFlag{
+				Name:  "debounce",
+				Usage: "duration to wait for the files to stop changing before deploying",
+			},
+		},
+		Action: a.actionWatch,
+	}
+}
+
+func (a *App) actionWatch(ctx context.Context, cmd *cli.Command) error {
+	var opts []frontier.WatchOption
+	if paths := cmd.StringSlice("test-event"); len(paths) > 0 {
+		opts = append(opts, frontier.TestEvents(paths...))
+	}
+	if interval := cmd.Duration("interval"); interval > 0 {
+		opts = append(opts, frontier.WatchInterval(interval))
+	}
+	if debounce := cmd.Duration("debounce"); debounce > 0 {
+		opts = append(opts, frontier.WatchDebounce(debounce))
+	}
+	for result, err := range a.controllers.Watch(ctx, cmd.String(flagConfigPath.Name), opts...) {
+		if err != nil {
+			fmt.Fprintf(cmd.ErrWriter, "failed: %s\n", err)
+			continue
+		}
+		printWatchResult(cmd.Writer, result)
+	}
+	return nil
+}
+
+func printWatchResult(w io.Writer, result *frontier.WatchResult) {
+	var changed string
+	if len(result.ChangedPaths) > 0 {
+		changed = " by " + strings.Join(result.ChangedPaths, ", ")
+	}
+	d := result.Deploy
+	fmt.Fprintf(w, "%s %s%s: ETag %s -> %s (%s)\n", d.Action, d.FunctionName, changed, d.ETagBefore, d.ETagAfter, d.Duration)
+	for _, t := range result.Tests {
+		fmt.Fprintf(w, "  test %s: compute utilization %s", t.EventPath, t.ComputeUtilization)
+		if t.ErrorMessage != "" {
+			fmt.Fprintf(w, ", error: %s", t.ErrorMessage)
+		}
+		fmt.Fprintln(w)
+	}
+}
@@ -0,0 +1,149 @@
+package frontier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/aereal/frontier/cf"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudfront"
+	"github.com/aws/aws-sdk-go-v2/service/cloudfront/types"
+)
+
+// MaxFunctionCodeBytes is the maximum size of the function code that CloudFront accepts.
+const MaxFunctionCodeBytes = 10 * 1024
+
+const maxComputeUtilization = 100
+
+// FunctionLimits are the thresholds that fail the deployment when the function exceeds them.
+// The zero value disables the limit.
+type FunctionLimits struct {
+	MaxCodeBytes int `yaml:"maxCodeBytes,omitempty"`
+	// MaxComputeUtilization is compared with the highest compute utilization of the test events run by TestFunction.
+	MaxComputeUtilization int `yaml:"maxComputeUtilization,omitempty"`
+}
+
+type LimitName string
+
+const (
+	LimitMaxCodeBytes          LimitName = "maxCodeBytes"
+	LimitMaxComputeUtilization LimitName = "maxComputeUtilization"
+)
+
+type LimitExceededError struct {
+	FunctionName string
+	Limit        LimitName
+	Max          int
+	Actual       int
+}
+
+func (e *LimitExceededError) Error() string {
+	return fmt.Sprintf("function %s exceeds limits.%s: %d > %d", e.FunctionName, e.Limit, e.Actual, e.Max)
+}
+
+func (e *LimitExceededError) Is(other error) bool {
+	otherErr := new(LimitExceededError)
+	if !errors.As(other, &otherErr) {
+		return false
+	}
+	return *otherErr == *e
+}
+
+func (fn *Function) validateLimits() error {
+	if fn.Limits == nil {
+		return nil
+	}
+	if v := fn.Limits.MaxCodeBytes; v < 0 || v > MaxFunctionCodeBytes {
+		return &InvalidConfigError{Field: "limits.maxCodeBytes", Reason: fmt.Sprintf("must be between 0 and %d", MaxFunctionCodeBytes)}
+	}
+	if v := fn.Limits.MaxComputeUtilization; v < 0 || v > maxComputeUtilization {
+		return &InvalidConfigError{Field: "limits.maxComputeUtilization", Reason: "must be between 0 and 100"}
+	}
+	if fn.Limits.MaxComputeUtilization > 0 && len(fn.TestEvents) == 0 {
+		return &InvalidConfigError{Field: "limits.maxComputeUtilization", Reason: "requires testEvents"}
+	}
+	return nil
+}
+
+func (fn *Function) maxCodeBytes() int {
+	if fn.Limits == nil {
+		return 0
+	}
+	return fn.Limits.MaxCodeBytes
+}
+
+// codeSizeLimit returns limits.maxCodeBytes if configured, or [MaxFunctionCodeBytes].
+func (fn *Function) codeSizeLimit() int {
+	if limit := fn.maxCodeBytes(); limit > 0 {
+		return limit
+	}
+	return MaxFunctionCodeBytes
+}
+
+func (fn *Function) maxComputeUtilization() int {
+	if fn.Limits == nil {
+		return 0
+	}
+	return fn.Limits.MaxComputeUtilization
+}
+
+func (fn *Function) checkCodeSize(size int) error {
+	if limit := fn.codeSizeLimit(); size > limit {
+		return &LimitExceededError{FunctionName: fn.Name, Limit: LimitMaxCodeBytes, Max: limit, Actual: size}
+	}
+	return nil
+}
+
+func (fn *Function) checkComputeUtilization(tests []*FunctionTestResult) error {
+	if limit := fn.maxComputeUtilization(); limit > 0 {
+		if peak := peakComputeUtilization(tests); peak > limit {
+			return &LimitExceededError{FunctionName: fn.Name, Limit: LimitMaxComputeUtilization, Max: limit, Actual: peak}
+		}
+	}
+	return nil
+}
+
+// peakComputeUtilization returns the highest compute utilization of the tests; the values not in number are ignored.
+func peakComputeUtilization(tests []*FunctionTestResult) int {
+	var peak int
+	for _, t := range tests {
+		if v, err := strconv.Atoi(t.ComputeUtilization); err == nil && v > peak {
+			peak = v
+		}
+	}
+	return peak
+}
+
+// runFunctionTests runs TestFunction with each event object against the stage of the function identified by the etag.
+func runFunctionTests(ctx context.Context, client cf.CloudFrontClient, name, etag string, stage types.FunctionStage, eventPaths []string) (_ []*FunctionTestResult, err error) {
+	ctx, span := startSpan(ctx, "frontier.RunFunctionTests", attrFunctionName.String(name), attrETag.String(etag))
+	defer func() { endSpan(span, err) }()
+	results := make([]*FunctionTestResult, 0, len(eventPaths))
+	for _, eventPath := range eventPaths {
+		event, err := os.ReadFile(eventPath)
+		if err != nil {
+			return nil, err
+		}
+		out, err := client.TestFunction(ctx, &cloudfront.TestFunctionInput{
+			Name:        aws.String(name),
+			IfMatch:     aws.String(etag),
+			Stage:       stage,
+			EventObject: event,
+		})
+		if err != nil {
+			return nil, err
+		}
+		tr := &FunctionTestResult{EventPath: eventPath}
+		if out.TestResult != nil {
+			tr.ComputeUtilization = aws.ToString(out.TestResult.ComputeUtilization)
+			tr.ErrorMessage = aws.ToString(out.TestResult.FunctionErrorMessage)
+			tr.Output = aws.ToString(out.TestResult.FunctionOutput)
+			tr.Logs = out.TestResult.FunctionExecutionLogs
+		}
+		results = append(results, tr)
+	}
+	return results, nil
+}
@@ -0,0 +1,149 @@
+package frontier_test
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/aereal/frontier"
+	"github.com/aereal/frontier/cf"
+	"github.com/aereal/frontier/internal/cfmock"
+	"github.com/aws/aws-sdk-go-v2/service/cloudfront"
+	"github.com/aws/aws-sdk-go-v2/service/cloudfront/types"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"go.uber.org/mock/gomock"
+)
+
+func TestDeployer_limits(t *testing.T) {
+	codePath, err := filepath.Abs("./testdata/fn.js")
+	if err != nil {
+		t.Fatal(err)
+	}
+	testCases := []struct {
+		name          string
+		code          []byte
+		limits        string
+		mock          func(c *cfmock.MockCloudFrontClient)
+		wantErr       error
+		wantPublished bool
+	}{
+		{
+			name:    "code size exceeded",
+			limits:  "limits:\n  maxCodeBytes: 50\n",
+			mock:    func(_ *cfmock.MockCloudFrontClient) {},
+			wantErr: &frontier.LimitExceededError{FunctionName: "test-func", Limit: frontier.LimitMaxCodeBytes, Max: 50, Actual: 53},
+		},
+		{
+			name:    "code size exceeded the limit of CloudFront",
+			code:    largeCode,
+			mock:    func(_ *cfmock.MockCloudFrontClient) {},
+			wantErr: &frontier.LimitExceededError{FunctionName: "test-func", Limit: frontier.LimitMaxCodeBytes, Max: frontier.MaxFunctionCodeBytes, Actual: len(largeCode)},
+		},
+		{
+			name:    "code size limit above the limit of CloudFront",
+			limits:  "limits:\n  maxCodeBytes: 10241\n",
+			mock:    func(_ *cfmock.MockCloudFrontClient) {},
+			wantErr: &frontier.InvalidConfigError{Field: "limits.maxCodeBytes", Reason: "must be between 0 and 10240"},
+		},
+		{
+			name:   "compute utilization within the limit",
+			limits: "limits:\n  maxCodeBytes: 53\n  maxComputeUtilization: 30\ntestEvents:\n  - $EVENT\n",
+			mock: func(c *cfmock.MockCloudFrontClient) {
+				gomock.InOrder(
+					expectUpdatedOnDevelopment(c),
+					returnTestResult(c, "30"),
+					c.EXPECT().
+						PublishFunction(gomock.Any(), &cloudfront.PublishFunctionInput{Name: ref("test-func"), IfMatch: ref("etag-2")}).
+						Return(&cloudfront.PublishFunctionOutput{}, nil).
+						Times(1),
+				)
+			},
+			wantPublished: true,
+		},
+		{
+			name:   "compute utilization exceeded",
+			limits: "limits:\n  maxComputeUtilization: 30\ntestEvents:\n  - $EVENT\n",
+			mock: func(c *cfmock.MockCloudFrontClient) {
+				gomock.InOrder(
+					expectUpdatedOnDevelopment(c),
+					returnTestResult(c, "31"),
+				)
+			},
+			wantErr: &frontier.LimitExceededError{FunctionName: "test-func", Limit: frontier.LimitMaxComputeUtilization, Max: 30, Actual: 31},
+		},
+		{
+			name:    "compute utilization without test events",
+			limits:  "limits:\n  maxComputeUtilization: 30\n",
+			mock:    func(_ *cfmock.MockCloudFrontClient) {},
+			wantErr: &frontier.InvalidConfigError{Field: "limits.maxComputeUtilization", Reason: "requires testEvents"},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			if deadline, ok := t.Deadline(); ok {
+				ctx, cancel = context.WithDeadline(ctx, deadline)
+			}
+			defer cancel()
+
+			dir := t.TempDir()
+			eventPath := filepath.Join(dir, "event.json")
+			if err := os.WriteFile(eventPath, []byte(`{"version":"1.0"}`), 0600); err != nil {
+				t.Fatal(err)
+			}
+			fnPath := codePath
+			if tc.code != nil {
+				fnPath = filepath.Join(dir, "fn.js")
+				if err := os.WriteFile(fnPath, tc.code, 0600); err != nil {
+					t.Fatal(err)
+				}
+			}
+			configPath := filepath.Join(dir, "function.yml")
+			config := "name: test-func\ncode:\n  path: " + fnPath + "\nconfig:\n  comment: blah blah\n  runtime: cloudfront-js-1.0\n" + strings.ReplaceAll(tc.limits, "$EVENT", eventPath)
+			if err := os.WriteFile(configPath, []byte(config), 0600); err != nil {
+				t.Fatal(err)
+			}
+
+			ctrl := gomock.NewController(t)
+			client := cfmock.NewMockCloudFrontClient(ctrl)
+			tc.mock(client)
+			deployer := frontier.NewDeployer(&cf.StaticCFProvider{Client: client})
+			got, gotErr := deployer.Deploy(ctx, configPath, true)
+			if diff := cmp.Diff(tc.wantErr, gotErr, cmpopts.EquateErrors()); diff != "" {
+				t.Errorf("error (-want, +got):\n%s", diff)
+			}
+			if got != nil && got.Published != tc.wantPublished {
+				t.Errorf("published: want=%v got=%v", tc.wantPublished, got.Published)
+			}
+		})
+	}
+}
+
+func expectUpdatedOnDevelopment(c *cfmock.MockCloudFrontClient) *gomock.Call {
+	c.EXPECT().
+		GetFunction(gomock.Any(), &cloudfront.GetFunctionInput{Name: ref("test-func")}).
+		Return(&cloudfront.GetFunctionOutput{ETag: ref("etag-1")}, nil).
+		Times(1)
+	return c.EXPECT().
+		UpdateFunction(gomock.Any(), gomock.Any()).
+		Return(&cloudfront.UpdateFunctionOutput{ETag: ref("etag-2")}, nil).
+		Times(1)
+}
+
+func returnTestResult(c *cfmock.MockCloudFrontClient, computeUtilization string) *gomock.Call {
+	return c.EXPECT().
+		TestFunction(gomock.Any(), &cloudfront.TestFunctionInput{
+			Name:        ref("test-func"),
+			IfMatch:     ref("etag-2"),
+			Stage:       types.FunctionStageDevelopment,
+			EventObject: []byte(`{"version":"1.0"}`),
+		}).
+		Return(&cloudfront.TestFunctionOutput{TestResult: &types.TestResult{ComputeUtilization: ref(computeUtilization)}}, nil).
+		Times(1)
+}
+
+var largeCode = append(bytes.Repeat([]byte("// padding\n"), frontier.MaxFunctionCodeBytes/11), []byte("function handler(event) { return event.request; }\n")...)
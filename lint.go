@@ -0,0 +1,102 @@
+package frontier
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	"github.com/aereal/frontier/lint"
+)
+
+type LintOption interface {
+	applyLintOption(cfg *configLint)
+}
+
+type configLint struct {
+	eventType lint.EventType
+}
+
+// LintEventType sets the event type that decides the read-only headers and the shape of the returned object.
+// It takes precedence over lint.eventType and the associations in the function config.
+func LintEventType(eventType lint.EventType) LintOption { //nolint:ireturn
+	return &optLintEventType{eventType: eventType}
+}
+
+type optLintEventType struct{ eventType lint.EventType }
+
+var _ LintOption = (*optLintEventType)(nil)
+
+func (o *optLintEventType) applyLintOption(cfg *configLint) { cfg.eventType = o.eventType }
+
+type LintReport struct {
+	FunctionName string
+	CodePath     string
+	EventType    lint.EventType
+	Findings     []*lint.Finding
+}
+
+// Failed tells whether any finding is an error.
+func (r *LintReport) Failed() bool {
+	return slices.ContainsFunc(r.Findings, func(f *lint.Finding) bool { return f.Severity == lint.SeverityError })
+}
+
+func NewLinter() *Linter {
+	return &Linter{}
+}
+
+// Linter checks the code of the function against the restrictions of CloudFront Functions with the rules configured in `lint`.
+type Linter struct{}
+
+func (l *Linter) Lint(ctx context.Context, configPath string, opts ...LintOption) (_ *LintReport, err error) {
+	ctx, span := startSpan(ctx, "frontier.Lint", attrConfigPath.String(configPath))
+	defer func() { endSpan(span, err) }()
+	var cfg configLint
+	for _, o := range opts {
+		o.applyLintOption(&cfg)
+	}
+	fn, err := parseConfig(ctx, configPath)
+	if err != nil {
+		return nil, err
+	}
+	if fn.Code == nil || fn.Code.Path == "" {
+		return nil, &InvalidConfigError{Field: "code.path", Reason: "is required"}
+	}
+	body, err := readCode(ctx, fn)
+	if err != nil {
+		return nil, err
+	}
+	lintCfg := new(lint.Config)
+	if fn.Lint != nil {
+		*lintCfg = *fn.Lint
+	}
+	if cfg.eventType != "" {
+		lintCfg.EventType = cfg.eventType
+	}
+	if lintCfg.EventType == "" {
+		lintCfg.EventType = fn.associatedEventType()
+	}
+	findings, err := lint.Lint(fn.Code.Path, body, lintCfg)
+	if configErr := new(lint.ConfigError); errors.As(err, &configErr) {
+		return nil, &InvalidConfigError{Field: "lint." + configErr.Field, Reason: configErr.Reason}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &LintReport{FunctionName: fn.Name, CodePath: fn.Code.Path, EventType: lintCfg.EventType, Findings: findings}, nil
+}
+
+// associatedEventType returns the event type of the associations if all of them are the same viewer event; empty otherwise.
+func (fn *Function) associatedEventType() lint.EventType {
+	var eventType lint.EventType
+	for _, a := range fn.Associations {
+		et := lint.EventType(a.EventType)
+		if et != lint.EventTypeViewerRequest && et != lint.EventTypeViewerResponse {
+			return ""
+		}
+		if eventType != "" && eventType != et {
+			return ""
+		}
+		eventType = et
+	}
+	return eventType
+}
@@ -0,0 +1,128 @@
+package lint
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+)
+
+type Severity string
+
+const (
+	SeverityOff     Severity = "off"
+	SeverityWarning Severity = "warning"
+	SeverityError   Severity = "error"
+)
+
+func (s Severity) valid() bool {
+	return s == SeverityOff || s == SeverityWarning || s == SeverityError
+}
+
+// EventType is the event type of the distribution the function is associated with.
+type EventType string
+
+const (
+	EventTypeViewerRequest  EventType = "viewer-request"
+	EventTypeViewerResponse EventType = "viewer-response"
+)
+
+// Config is the `lint` section of the function config.
+type Config struct {
+	// EventType decides the read-only headers and the shape of the returned object.
+	// The rules depending on the event type are relaxed if it is empty.
+	EventType EventType `yaml:"eventType,omitempty"`
+	// Rules overrides the severities of the rules by the rule ID.
+	Rules map[string]Severity `yaml:"rules,omitempty"`
+}
+
+type ConfigError struct {
+	Field  string
+	Reason string
+}
+
+func (e *ConfigError) Error() string { return fmt.Sprintf("%s: %s", e.Field, e.Reason) }
+
+func (e *ConfigError) Is(other error) bool {
+	otherErr := new(ConfigError)
+	if !errors.As(other, &otherErr) {
+		return false
+	}
+	return *otherErr == *e
+}
+
+// Validate tells the unknown rules, the invalid severities and the unsupported event type.
+func (c *Config) Validate() error {
+	if c == nil {
+		return nil
+	}
+	if c.EventType != "" && c.EventType != EventTypeViewerRequest && c.EventType != EventTypeViewerResponse {
+		return &ConfigError{Field: "eventType", Reason: fmt.Sprintf("unsupported event type %q", c.EventType)}
+	}
+	for _, id := range slices.Sorted(maps.Keys(c.Rules)) {
+		if findRule(id) == nil {
+			return &ConfigError{Field: "rules." + id, Reason: "unknown rule"}
+		}
+		if !c.Rules[id].valid() {
+			return &ConfigError{Field: "rules." + id, Reason: fmt.Sprintf("invalid severity %q", c.Rules[id])}
+		}
+	}
+	return nil
+}
+
+func (c *Config) severityOf(rule *Rule) Severity {
+	if c != nil {
+		if s, ok := c.Rules[rule.ID]; ok {
+			return s
+		}
+	}
+	return rule.DefaultSeverity
+}
+
+func (c *Config) eventType() EventType {
+	if c == nil {
+		return ""
+	}
+	return c.EventType
+}
+
+type Finding struct {
+	RuleID   string
+	Severity Severity
+	Message  string
+	Path     string
+	// Line and Column are 1-based; Column counts the code points.
+	Line   int
+	Column int
+}
+
+// Lint checks the source of the function at the path with the rules enabled by the config.
+// The findings are sorted by the position.
+func Lint(path string, src []byte, cfg *Config) ([]*Finding, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	f := &file{tokens: tokenize(string(src)), eventType: cfg.eventType()}
+	var findings []*Finding
+	for _, rule := range rules {
+		severity := cfg.severityOf(rule)
+		if severity == SeverityOff {
+			continue
+		}
+		for _, p := range rule.check(f) {
+			findings = append(findings, &Finding{
+				RuleID:   rule.ID,
+				Severity: severity,
+				Message:  p.message,
+				Path:     path,
+				Line:     p.at.line,
+				Column:   p.at.column,
+			})
+		}
+	}
+	slices.SortStableFunc(findings, func(a, b *Finding) int {
+		return cmp.Or(cmp.Compare(a.Line, b.Line), cmp.Compare(a.Column, b.Column))
+	})
+	return findings, nil
+}
@@ -0,0 +1,158 @@
+package lint_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/aereal/frontier/lint"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestLint(t *testing.T) {
+	testCases := []struct {
+		name    string
+		src     string
+		cfg     *lint.Config
+		want    []*lint.Finding
+		wantErr error
+	}{
+		{
+			name: "ok",
+			src: `// fetch(url) in comments is ignored
+var allowed = "setTimeout";
+function handler(event) {
+  var request = event.request;
+  var params = request.querystring;
+  if (params.lang && params.lang.value === 'ja') {
+    request.uri = '/ja' + request.uri;
+  }
+  request.headers['x-forwarded-host'] = { value: request.headers.host.value };
+  return request;
+}`,
+			cfg: &lint.Config{EventType: lint.EventTypeViewerRequest},
+		},
+		{
+			name: "network and file APIs",
+			src: `import http from "node:http";
+var fs = require('fs');
+function handler(event) {
+  fetch('https://example.com/');
+  return event.request;
+}`,
+			want: []*lint.Finding{
+				{RuleID: lint.RuleNoNetworkAPI, Severity: lint.SeverityError, Message: `module "node:http" is not available: CloudFront Functions cannot access the network`, Path: "fn.js", Line: 1, Column: 18},
+				{RuleID: lint.RuleNoFileAPI, Severity: lint.SeverityError, Message: `module "fs" is not available: CloudFront Functions cannot access the file system`, Path: "fn.js", Line: 2, Column: 18},
+				{RuleID: lint.RuleNoNetworkAPI, Severity: lint.SeverityError, Message: "fetch is not available: CloudFront Functions cannot access the network", Path: "fn.js", Line: 4, Column: 3},
+			},
+		},
+		{
+			name: "timers and global mutation",
+			src: `Array.prototype.last = function () { return this[this.length - 1]; };
+globalThis.counter = 0;
+function handler(event) {
+  setTimeout(function () {}, 10);
+  var n = Math.max(1, 2) / 2;
+  return event.request;
+}`,
+			want: []*lint.Finding{
+				{RuleID: lint.RuleNoGlobalMutation, Severity: lint.SeverityError, Message: "mutating the built-in Array.prototype.last is not allowed in CloudFront Functions", Path: "fn.js", Line: 1, Column: 1},
+				{RuleID: lint.RuleNoGlobalMutation, Severity: lint.SeverityError, Message: "mutating the built-in globalThis.counter is not allowed in CloudFront Functions", Path: "fn.js", Line: 2, Column: 1},
+				{RuleID: lint.RuleNoTimers, Severity: lint.SeverityError, Message: "setTimeout is not available: CloudFront Functions do not provide timers", Path: "fn.js", Line: 4, Column: 3},
+			},
+		},
+		{
+			name: "read-only headers of viewer-response",
+			src: `function handler(event) {
+  var response = event.response;
+  response.headers['content-encoding'] = { value: 'gzip' };
+  delete response.headers.via;
+  response.headers.host = { value: 'example.com' };
+  return response;
+}`,
+			cfg: &lint.Config{EventType: lint.EventTypeViewerResponse},
+			want: []*lint.Finding{
+				{RuleID: lint.RuleReadOnlyHeader, Severity: lint.SeverityError, Message: "the content-encoding header is read-only for viewer-response events", Path: "fn.js", Line: 3, Column: 20},
+				{RuleID: lint.RuleReadOnlyHeader, Severity: lint.SeverityError, Message: "the via header is read-only for viewer-response events", Path: "fn.js", Line: 4, Column: 27},
+			},
+		},
+		{
+			name: "read-only headers without the event type",
+			src:  `function handler(event) { delete event.request.headers.host; return event.request; }`,
+		},
+		{
+			name: "response shape",
+			src: `function handler(event) {
+  function header(v) { return { value: v }; }
+  if (event.request.uri === '/old') {
+    return { statusCode: '301', statusDescription: 'Moved', headers: { location: header('/new') }, status: 301 };
+  }
+  if (event.request.uri === '/mixed') {
+    return { statusCode: 200, uri: '/index.html' };
+  }
+  return { ...event.request, uri: '/index.html' };
+}`,
+			cfg: &lint.Config{EventType: lint.EventTypeViewerRequest},
+			want: []*lint.Finding{
+				{RuleID: lint.RuleResponseShape, Severity: lint.SeverityWarning, Message: "statusCode must be a number", Path: "fn.js", Line: 4, Column: 26},
+				{RuleID: lint.RuleResponseShape, Severity: lint.SeverityWarning, Message: "status is not a property of the request or the response object", Path: "fn.js", Line: 4, Column: 100},
+				{RuleID: lint.RuleResponseShape, Severity: lint.SeverityWarning, Message: "the returned object mixes the properties of the request and the response", Path: "fn.js", Line: 7, Column: 12},
+			},
+		},
+		{
+			name: "querystring as string",
+			src: `function handler(event) {
+  var request = event.request;
+  var parts = request.querystring.split('&');
+  if (request.querystring === '') {}
+  request.uri = request.uri + '?' + request.querystring;
+  return request;
+}`,
+			want: []*lint.Finding{
+				{RuleID: lint.RuleQuerystringAsString, Severity: lint.SeverityWarning, Message: "request.querystring is an object of the query parameters, not a string", Path: "fn.js", Line: 3, Column: 23},
+				{RuleID: lint.RuleQuerystringAsString, Severity: lint.SeverityWarning, Message: "request.querystring is an object of the query parameters, not a string", Path: "fn.js", Line: 4, Column: 15},
+				{RuleID: lint.RuleQuerystringAsString, Severity: lint.SeverityWarning, Message: "request.querystring is an object of the query parameters, not a string", Path: "fn.js", Line: 5, Column: 45},
+			},
+		},
+		{
+			name: "severities overridden",
+			src:  "function handler(event) { setTimeout(f, 1); var q = event.request.querystring.length; return event.request; }",
+			cfg:  &lint.Config{Rules: map[string]lint.Severity{lint.RuleNoTimers: lint.SeverityOff, lint.RuleQuerystringAsString: lint.SeverityError}},
+			want: []*lint.Finding{
+				{RuleID: lint.RuleQuerystringAsString, Severity: lint.SeverityError, Message: "request.querystring is an object of the query parameters, not a string", Path: "fn.js", Line: 1, Column: 67},
+			},
+		},
+		{
+			name: "regular expressions and templates",
+			src:  "function handler(event) { var re = /fetch\\(/g; var s = `${setTimeout}`; var d = 4 / 2 / 1; return event.request; }",
+		},
+		{
+			name:    "unknown rule",
+			src:     "",
+			cfg:     &lint.Config{Rules: map[string]lint.Severity{"no-such-rule": lint.SeverityError}},
+			wantErr: &lint.ConfigError{Field: "rules.no-such-rule", Reason: "unknown rule"},
+		},
+		{
+			name:    "invalid severity",
+			src:     "",
+			cfg:     &lint.Config{Rules: map[string]lint.Severity{lint.RuleNoTimers: "fatal"}},
+			wantErr: &lint.ConfigError{Field: "rules.no-timers", Reason: `invalid severity "fatal"`},
+		},
+		{
+			name:    "unsupported event type",
+			src:     "",
+			cfg:     &lint.Config{EventType: "origin-request"},
+			wantErr: &lint.ConfigError{Field: "eventType", Reason: `unsupported event type "origin-request"`},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, gotErr := lint.Lint("fn.js", []byte(tc.src), tc.cfg)
+			if !errors.Is(gotErr, tc.wantErr) {
+				t.Errorf("error:\n\twant: %v\n\t got: %v", tc.wantErr, gotErr)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("findings (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,397 @@
+package lint
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Rule is a built-in check of the lint.
+type Rule struct {
+	ID              string
+	Description     string
+	DefaultSeverity Severity
+	check           func(f *file) []problem
+}
+
+type problem struct {
+	at      token
+	message string
+}
+
+type file struct {
+	tokens    []token
+	eventType EventType
+}
+
+// at returns the token at the index, or the zero token out of the range.
+func (f *file) at(i int) token {
+	if i < 0 || i >= len(f.tokens) {
+		return token{kind: -1}
+	}
+	return f.tokens[i]
+}
+
+const (
+	RuleNoNetworkAPI        = "no-network-api"
+	RuleNoFileAPI           = "no-file-api"
+	RuleNoTimers            = "no-timers"
+	RuleNoGlobalMutation    = "no-global-mutation"
+	RuleReadOnlyHeader      = "read-only-header"
+	RuleResponseShape       = "response-shape"
+	RuleQuerystringAsString = "querystring-as-string"
+)
+
+var rules = []*Rule{
+	{
+		ID:              RuleNoNetworkAPI,
+		Description:     "CloudFront Functions cannot access the network",
+		DefaultSeverity: SeverityError,
+		check:           checkNoNetworkAPI,
+	},
+	{
+		ID:              RuleNoFileAPI,
+		Description:     "CloudFront Functions cannot access the file system",
+		DefaultSeverity: SeverityError,
+		check:           checkNoFileAPI,
+	},
+	{
+		ID:              RuleNoTimers,
+		Description:     "CloudFront Functions do not provide timers such as setTimeout",
+		DefaultSeverity: SeverityError,
+		check:           checkNoTimers,
+	},
+	{
+		ID:              RuleNoGlobalMutation,
+		Description:     "the built-in objects and their prototypes must not be modified",
+		DefaultSeverity: SeverityError,
+		check:           checkNoGlobalMutation,
+	},
+	{
+		ID:              RuleReadOnlyHeader,
+		Description:     "the read-only headers of the event type must not be modified",
+		DefaultSeverity: SeverityError,
+		check:           checkReadOnlyHeader,
+	},
+	{
+		ID:              RuleResponseShape,
+		Description:     "the handler must return the request or the response object of the event type",
+		DefaultSeverity: SeverityWarning,
+		check:           checkResponseShape,
+	},
+	{
+		ID:              RuleQuerystringAsString,
+		Description:     "request.querystring is an object of the query parameters, not a string",
+		DefaultSeverity: SeverityWarning,
+		check:           checkQuerystringAsString,
+	},
+}
+
+// Rules returns the built-in rules.
+func Rules() []*Rule { return slices.Clone(rules) }
+
+func findRule(id string) *Rule {
+	for _, r := range rules {
+		if r.ID == id {
+			return r
+		}
+	}
+	return nil
+}
+
+var (
+	networkGlobals = []string{"fetch", "XMLHttpRequest", "WebSocket", "EventSource"}
+	networkModules = []string{"http", "https", "http2", "net", "tls", "dgram", "dns"}
+	fileModules    = []string{"fs", "fs/promises"}
+	timers         = []string{"setTimeout", "setInterval", "setImmediate"}
+	builtins       = []string{"globalThis", "Object", "Array", "String", "Number", "Boolean", "Math", "JSON", "Date", "RegExp", "Function", "Promise", "Symbol", "Error"}
+	assignOps      = []string{"=", "+=", "-=", "*=", "/=", "%=", "**=", "<<=", ">>=", ">>>=", "&=", "|=", "^=", "&&=", "||=", "??="}
+	stringMethods  = []string{"length", "split", "indexOf", "includes", "startsWith", "endsWith", "replace", "replaceAll", "match", "substring", "substr", "slice", "trim", "toLowerCase", "toUpperCase", "charAt"}
+	readOnlyHeader = map[EventType][]string{
+		EventTypeViewerRequest:  {"content-length", "host", "transfer-encoding", "via"},
+		EventTypeViewerResponse: {"content-encoding", "content-length", "transfer-encoding", "warning", "via"},
+	}
+	requestOnlyKeys  = []string{"method", "uri", "querystring"}
+	responseOnlyKeys = []string{"statusCode", "statusDescription", "body"}
+	sharedKeys       = []string{"headers", "cookies"}
+)
+
+func checkNoNetworkAPI(f *file) []problem {
+	problems := globalReferences(f, networkGlobals, "%s is not available: CloudFront Functions cannot access the network")
+	for _, i := range moduleSpecifiers(f) {
+		if slices.Contains(networkModules, strings.TrimPrefix(f.tokens[i].value, "node:")) {
+			problems = append(problems, problem{at: f.tokens[i], message: fmt.Sprintf("module %q is not available: CloudFront Functions cannot access the network", f.tokens[i].value)})
+		}
+	}
+	return problems
+}
+
+func checkNoFileAPI(f *file) []problem {
+	var problems []problem
+	for _, i := range moduleSpecifiers(f) {
+		if slices.Contains(fileModules, strings.TrimPrefix(f.tokens[i].value, "node:")) {
+			problems = append(problems, problem{at: f.tokens[i], message: fmt.Sprintf("module %q is not available: CloudFront Functions cannot access the file system", f.tokens[i].value)})
+		}
+	}
+	return problems
+}
+
+func checkNoTimers(f *file) []problem {
+	return globalReferences(f, timers, "%s is not available: CloudFront Functions do not provide timers")
+}
+
+func checkNoGlobalMutation(f *file) []problem {
+	var problems []problem
+	for i, t := range f.tokens {
+		if t.kind != tokenIdent || !slices.Contains(builtins, t.value) || !isGlobalReference(f, i) {
+			continue
+		}
+		end := skipMemberChain(f, i+1)
+		if f.at(end).isPunct(assignOps...) || f.at(end).isPunct("++", "--") || f.at(i-1).is(tokenIdent, "delete") {
+			problems = append(problems, problem{at: t, message: fmt.Sprintf("mutating the built-in %s is not allowed in CloudFront Functions", sourceOf(f.tokens[i:end]))})
+		}
+	}
+	return problems
+}
+
+func checkReadOnlyHeader(f *file) []problem {
+	names := readOnlyHeader[f.eventType]
+	if len(names) == 0 {
+		return nil
+	}
+	var problems []problem
+	for i, t := range f.tokens {
+		if !t.is(tokenIdent, "headers") {
+			continue
+		}
+		var (
+			name  token
+			after int
+		)
+		switch {
+		case f.at(i+1).isPunct(".", "?.") && f.at(i+2).kind == tokenIdent:
+			name, after = f.at(i+2), i+3
+		case f.at(i+1).isPunct("[") && f.at(i+2).kind == tokenString && f.at(i+3).isPunct("]"):
+			name, after = f.at(i+2), i+4
+		default:
+			continue
+		}
+		if !slices.Contains(names, strings.ToLower(name.value)) {
+			continue
+		}
+		end := skipMemberChain(f, after)
+		if f.at(end).isPunct(assignOps...) || f.at(chainStart(f, i)-1).is(tokenIdent, "delete") {
+			problems = append(problems, problem{at: name, message: fmt.Sprintf("the %s header is read-only for %s events", strings.ToLower(name.value), f.eventType)})
+		}
+	}
+	return problems
+}
+
+func checkResponseShape(f *file) []problem {
+	var problems []problem
+	for _, i := range handlerReturns(f) {
+		keys, ok := objectKeys(f, i+1)
+		if !ok {
+			continue
+		}
+		var hasRequestKey, hasResponseKey bool
+		for _, k := range keys {
+			switch {
+			case slices.Contains(requestOnlyKeys, k.key.value) && f.eventType != EventTypeViewerResponse:
+				hasRequestKey = true
+			case slices.Contains(responseOnlyKeys, k.key.value):
+				hasResponseKey = true
+			case slices.Contains(sharedKeys, k.key.value):
+			default:
+				problems = append(problems, problem{at: k.key, message: fmt.Sprintf("%s is not a property of the %s object", k.key.value, returnedObjectName(f.eventType))})
+			}
+			if k.key.value == "statusCode" && (k.value.kind == tokenString || k.value.kind == tokenTemplate) {
+				problems = append(problems, problem{at: k.value, message: "statusCode must be a number"})
+			}
+		}
+		if hasRequestKey && hasResponseKey && f.eventType == EventTypeViewerRequest {
+			problems = append(problems, problem{at: f.tokens[i+1], message: "the returned object mixes the properties of the request and the response"})
+		}
+	}
+	return problems
+}
+
+func returnedObjectName(eventType EventType) string {
+	if eventType == EventTypeViewerResponse {
+		return "response"
+	}
+	return "request or the response"
+}
+
+func checkQuerystringAsString(f *file) []problem {
+	var problems []problem
+	for i, t := range f.tokens {
+		if !t.is(tokenIdent, "querystring") || !f.at(i-1).isPunct(".", "?.") {
+			continue
+		}
+		next, afterNext := f.at(i+1), f.at(i+2)
+		switch {
+		case next.isPunct(".", "?.") && afterNext.kind == tokenIdent && slices.Contains(stringMethods, afterNext.value),
+			next.isPunct("+", "+="),
+			next.isPunct("=", "==", "===", "!=", "!==") && (afterNext.kind == tokenString || afterNext.kind == tokenTemplate),
+			!next.isPunct(".", "?.", "[") && f.at(chainStart(f, i)-1).isPunct("+"):
+			problems = append(problems, problem{at: t, message: "request.querystring is an object of the query parameters, not a string"})
+		}
+	}
+	return problems
+}
+
+// globalReferences returns the problems at the references to the global names.
+func globalReferences(f *file, names []string, format string) []problem {
+	var problems []problem
+	for i, t := range f.tokens {
+		if t.kind == tokenIdent && slices.Contains(names, t.value) && isGlobalReference(f, i) {
+			problems = append(problems, problem{at: t, message: fmt.Sprintf(format, t.value)})
+		}
+	}
+	return problems
+}
+
+// isGlobalReference tells the identifier at i is neither a property nor a key of an object literal.
+func isGlobalReference(f *file, i int) bool {
+	return !f.at(i-1).isPunct(".", "?.") && !f.at(i+1).isPunct(":")
+}
+
+// moduleSpecifiers returns the indexes of the strings given to require() or import.
+func moduleSpecifiers(f *file) []int {
+	var indexes []int
+	for i, t := range f.tokens {
+		switch {
+		case t.is(tokenIdent, "require") && f.at(i+1).isPunct("(") && f.at(i+2).kind == tokenString:
+			indexes = append(indexes, i+2)
+		case (t.is(tokenIdent, "from") || t.is(tokenIdent, "import")) && f.at(i+1).kind == tokenString:
+			indexes = append(indexes, i+1)
+		}
+	}
+	return indexes
+}
+
+// skipMemberChain returns the index after the property accesses such as `.a`, `?.b` and `[c]` from i.
+func skipMemberChain(f *file, i int) int {
+	for {
+		switch {
+		case f.at(i).isPunct(".", "?.") && f.at(i+1).kind == tokenIdent:
+			i += 2
+		case f.at(i).isPunct("["):
+			i = matchingBracket(f, i) + 1
+		default:
+			return i
+		}
+	}
+}
+
+// chainStart returns the index of the first identifier of the property accesses ending at i, such as `event` of `event.request.headers`.
+func chainStart(f *file, i int) int {
+	for f.at(i-1).isPunct(".", "?.") && f.at(i-2).kind == tokenIdent {
+		i -= 2
+	}
+	return i
+}
+
+// matchingBracket returns the index of the bracket closing the one at i, or the last index if it is not closed.
+func matchingBracket(f *file, i int) int {
+	depth := 0
+	for j := i; j < len(f.tokens); j++ {
+		switch {
+		case f.tokens[j].isPunct("(", "[", "{"):
+			depth++
+		case f.tokens[j].isPunct(")", "]", "}"):
+			depth--
+			if depth == 0 {
+				return j
+			}
+		}
+	}
+	return len(f.tokens) - 1
+}
+
+// handlerReturns returns the indexes of `return` directly in the function named handler, excluding the nested functions.
+func handlerReturns(f *file) []int {
+	body := -1
+	for i := range f.tokens {
+		if f.tokens[i].is(tokenIdent, "function") && f.at(i+1).is(tokenIdent, "handler") && f.at(i+2).isPunct("(") {
+			if open := matchingBracket(f, i+2) + 1; f.at(open).isPunct("{") {
+				body = open
+				break
+			}
+		}
+	}
+	if body < 0 {
+		return nil
+	}
+	end := matchingBracket(f, body)
+	var indexes []int
+	for i := body + 1; i < end; i++ {
+		t := f.tokens[i]
+		switch {
+		case t.is(tokenIdent, "function"):
+			i = skipFunctionBody(f, i)
+		case t.isPunct("=>") && f.at(i+1).isPunct("{"):
+			i = matchingBracket(f, i+1)
+		case t.is(tokenIdent, "return") && f.at(i+1).isPunct("{"):
+			indexes = append(indexes, i)
+		}
+	}
+	return indexes
+}
+
+func skipFunctionBody(f *file, i int) int {
+	for j := i; j < len(f.tokens); j++ {
+		if f.tokens[j].isPunct("(") {
+			open := matchingBracket(f, j) + 1
+			if f.at(open).isPunct("{") {
+				return matchingBracket(f, open)
+			}
+			return open
+		}
+	}
+	return len(f.tokens)
+}
+
+type objectKey struct {
+	key   token
+	value token
+}
+
+// objectKeys returns the keys of the object literal starting at i; false if the keys cannot be known, such as the object has a spread.
+func objectKeys(f *file, i int) ([]objectKey, bool) {
+	end := matchingBracket(f, i)
+	var keys []objectKey
+	for j := i + 1; j < end; {
+		t := f.tokens[j]
+		switch {
+		case t.isPunct("..."):
+			return nil, false
+		case (t.kind == tokenIdent || t.kind == tokenString) && f.at(j+1).isPunct(":"):
+			keys = append(keys, objectKey{key: t, value: f.at(j + 2)})
+		case t.kind == tokenIdent && f.at(j+1).isPunct(",", "}", "("):
+			keys = append(keys, objectKey{key: t})
+		}
+		// skip to the next property
+		for j < end && !f.tokens[j].isPunct(",") {
+			if f.tokens[j].isPunct("(", "[", "{") {
+				j = matchingBracket(f, j)
+			}
+			j++
+		}
+		j++
+	}
+	return keys, true
+}
+
+func sourceOf(tokens []token) string {
+	var b strings.Builder
+	for _, t := range tokens {
+		if t.kind == tokenString {
+			fmt.Fprintf(&b, "%q", t.value)
+			continue
+		}
+		b.WriteString(t.value)
+	}
+	return b.String()
+}
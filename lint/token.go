@@ -0,0 +1,261 @@
+package lint
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+type tokenKind int
+
+const (
+	tokenIdent tokenKind = iota
+	tokenNumber
+	tokenString
+	tokenTemplate
+	tokenRegExp
+	tokenPunct
+)
+
+type token struct {
+	kind tokenKind
+	// value is the content without the quotes for the strings, or the source text for the others.
+	value  string
+	line   int
+	column int
+}
+
+func (t token) is(kind tokenKind, value string) bool { return t.kind == kind && t.value == value }
+
+func (t token) isPunct(values ...string) bool {
+	if t.kind != tokenPunct {
+		return false
+	}
+	for _, v := range values {
+		if t.value == v {
+			return true
+		}
+	}
+	return false
+}
+
+// punctuators are ordered from the longest so that the longest match wins.
+var punctuators = []string{
+	">>>=",
+	"===", "!==", "**=", "<<=", ">>=", ">>>", "...", "&&=", "||=", "??=",
+	"=>", "==", "!=", "<=", ">=", "&&", "||", "??", "?.", "++", "--", "+=", "-=", "*=", "/=", "%=", "&=", "|=", "^=", "**", "<<", ">>",
+}
+
+// tokenize splits the source into the tokens skipping the whitespaces and the comments.
+//
+// It is not a complete JavaScript lexer: the expressions in the template literals are not tokenized,
+// and a slash is read as a regular expression if it cannot end an operand.
+func tokenize(src string) []token {
+	lx := &lexer{src: src, line: 1, column: 1}
+	var tokens []token
+	for {
+		lx.skipSpacesAndComments()
+		if lx.pos >= len(lx.src) {
+			return tokens
+		}
+		line, column := lx.line, lx.column
+		kind, value := lx.next(tokens)
+		tokens = append(tokens, token{kind: kind, value: value, line: line, column: column})
+	}
+}
+
+type lexer struct {
+	src    string
+	pos    int
+	line   int
+	column int
+}
+
+func (lx *lexer) peek(offset int) byte {
+	if lx.pos+offset >= len(lx.src) {
+		return 0
+	}
+	return lx.src[lx.pos+offset]
+}
+
+// advance moves n bytes forward keeping the line and the column in code points.
+func (lx *lexer) advance(n int) {
+	end := min(lx.pos+n, len(lx.src))
+	for lx.pos < end {
+		r, size := utf8.DecodeRuneInString(lx.src[lx.pos:])
+		lx.pos += size
+		if r == '\n' {
+			lx.line++
+			lx.column = 1
+		} else {
+			lx.column++
+		}
+	}
+}
+
+func (lx *lexer) skipSpacesAndComments() {
+	for lx.pos < len(lx.src) {
+		switch {
+		case lx.src[lx.pos] == ' ', lx.src[lx.pos] == '\t', lx.src[lx.pos] == '\n', lx.src[lx.pos] == '\r':
+			lx.advance(1)
+		case strings.HasPrefix(lx.src[lx.pos:], "//"):
+			end := strings.IndexByte(lx.src[lx.pos:], '\n')
+			if end < 0 {
+				end = len(lx.src) - lx.pos
+			}
+			lx.advance(end)
+		case strings.HasPrefix(lx.src[lx.pos:], "/*"):
+			end := strings.Index(lx.src[lx.pos+2:], "*/")
+			if end < 0 {
+				lx.advance(len(lx.src) - lx.pos)
+				return
+			}
+			lx.advance(end + 4)
+		default:
+			r, size := utf8.DecodeRuneInString(lx.src[lx.pos:])
+			if !unicode.IsSpace(r) {
+				return
+			}
+			lx.advance(size)
+		}
+	}
+}
+
+func (lx *lexer) next(prev []token) (tokenKind, string) {
+	c := lx.src[lx.pos]
+	switch {
+	case c == '"' || c == '\'':
+		return tokenString, lx.readString(c)
+	case c == '`':
+		return tokenTemplate, lx.readTemplate()
+	case c >= '0' && c <= '9', c == '.' && lx.peek(1) >= '0' && lx.peek(1) <= '9':
+		return tokenNumber, lx.readWhile(func(r rune) bool { return isIdentPart(r) || r == '.' })
+	case c == '/' && !endsOperand(prev):
+		return tokenRegExp, lx.readRegExp()
+	}
+	if r, _ := utf8.DecodeRuneInString(lx.src[lx.pos:]); isIdentStart(r) {
+		return tokenIdent, lx.readWhile(isIdentPart)
+	}
+	for _, p := range punctuators {
+		if strings.HasPrefix(lx.src[lx.pos:], p) {
+			lx.advance(len(p))
+			return tokenPunct, p
+		}
+	}
+	_, size := utf8.DecodeRuneInString(lx.src[lx.pos:])
+	value := lx.src[lx.pos : lx.pos+size]
+	lx.advance(size)
+	return tokenPunct, value
+}
+
+func (lx *lexer) readWhile(f func(r rune) bool) string {
+	start := lx.pos
+	for lx.pos < len(lx.src) {
+		r, size := utf8.DecodeRuneInString(lx.src[lx.pos:])
+		if !f(r) {
+			break
+		}
+		lx.advance(size)
+	}
+	return lx.src[start:lx.pos]
+}
+
+func (lx *lexer) readString(quote byte) string {
+	lx.advance(1)
+	var b strings.Builder
+	for lx.pos < len(lx.src) {
+		c := lx.src[lx.pos]
+		switch {
+		case c == quote:
+			lx.advance(1)
+			return b.String()
+		case c == '\\' && lx.pos+1 < len(lx.src):
+			b.WriteByte(lx.src[lx.pos+1])
+			lx.advance(2)
+		case c == '\n':
+			return b.String()
+		default:
+			b.WriteByte(c)
+			lx.advance(1)
+		}
+	}
+	return b.String()
+}
+
+func (lx *lexer) readTemplate() string {
+	start := lx.pos
+	lx.advance(1)
+	depth := 0
+	for lx.pos < len(lx.src) {
+		switch c := lx.src[lx.pos]; {
+		case c == '\\':
+			lx.advance(2)
+		case c == '$' && lx.peek(1) == '{':
+			depth++
+			lx.advance(2)
+		case c == '}' && depth > 0:
+			depth--
+			lx.advance(1)
+		case c == '`' && depth == 0:
+			lx.advance(1)
+			return lx.src[start:lx.pos]
+		default:
+			lx.advance(1)
+		}
+	}
+	return lx.src[start:]
+}
+
+func (lx *lexer) readRegExp() string {
+	start := lx.pos
+	lx.advance(1)
+	inClass := false
+	for lx.pos < len(lx.src) {
+		c := lx.src[lx.pos]
+		switch {
+		case c == '\\':
+			lx.advance(2)
+			continue
+		case c == '\n':
+			return lx.src[start:lx.pos]
+		case c == '[':
+			inClass = true
+		case c == ']':
+			inClass = false
+		case c == '/' && !inClass:
+			lx.advance(1)
+			lx.readWhile(isIdentPart)
+			return lx.src[start:lx.pos]
+		}
+		lx.advance(1)
+	}
+	return lx.src[start:]
+}
+
+// endsOperand tells whether the slash after the tokens is a division rather than a regular expression.
+func endsOperand(prev []token) bool {
+	if len(prev) == 0 {
+		return false
+	}
+	last := prev[len(prev)-1]
+	switch last.kind {
+	case tokenNumber, tokenString, tokenTemplate, tokenRegExp:
+		return true
+	case tokenIdent:
+		switch last.value {
+		case "return", "typeof", "instanceof", "in", "of", "new", "delete", "void", "throw", "case", "do", "else", "yield", "await":
+			return false
+		}
+		return true
+	default:
+		return last.isPunct(")", "]", "}", "++", "--")
+	}
+}
+
+func isIdentStart(r rune) bool {
+	return r == '$' || r == '_' || unicode.IsLetter(r)
+}
+
+func isIdentPart(r rune) bool {
+	return isIdentStart(r) || unicode.IsDigit(r)
+}
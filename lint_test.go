@@ -0,0 +1,95 @@
+package frontier_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/aereal/frontier"
+	"github.com/aereal/frontier/lint"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func TestLinter_Lint(t *testing.T) {
+	const code = "function handler(event) {\n  event.response.headers.via = { value: 'x' };\n  return event.response;\n}\n"
+	testCases := []struct {
+		name    string
+		config  string
+		opts    []frontier.LintOption
+		want    *frontier.LintReport
+		wantErr error
+	}{
+		{
+			name:   "no event type",
+			config: "",
+			want:   &frontier.LintReport{FunctionName: "test-func", CodePath: "fn.js"},
+		},
+		{
+			name:   "event type of the associations",
+			config: "associations:\n  - distributionId: E1\n    eventType: viewer-response\n",
+			want: &frontier.LintReport{
+				FunctionName: "test-func",
+				CodePath:     "fn.js",
+				EventType:    lint.EventTypeViewerResponse,
+				Findings: []*lint.Finding{
+					{RuleID: lint.RuleReadOnlyHeader, Severity: lint.SeverityError, Message: "the via header is read-only for viewer-response events", Path: "fn.js", Line: 2, Column: 26},
+				},
+			},
+		},
+		{
+			name:   "event type overridden",
+			config: "lint:\n  eventType: viewer-response\n  rules:\n    read-only-header: warning\n",
+			opts:   []frontier.LintOption{frontier.LintEventType(lint.EventTypeViewerRequest)},
+			want: &frontier.LintReport{
+				FunctionName: "test-func",
+				CodePath:     "fn.js",
+				EventType:    lint.EventTypeViewerRequest,
+				Findings: []*lint.Finding{
+					{RuleID: lint.RuleReadOnlyHeader, Severity: lint.SeverityWarning, Message: "the via header is read-only for viewer-request events", Path: "fn.js", Line: 2, Column: 26},
+				},
+			},
+		},
+		{
+			name:    "unknown rule",
+			config:  "lint:\n  rules:\n    no-such-rule: error\n",
+			wantErr: &frontier.InvalidConfigError{Field: "lint.rules.no-such-rule", Reason: "unknown rule"},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			if deadline, ok := t.Deadline(); ok {
+				ctx, cancel = context.WithDeadline(ctx, deadline)
+			}
+			defer cancel()
+
+			dir := t.TempDir()
+			codePath := filepath.Join(dir, "fn.js")
+			if err := os.WriteFile(codePath, []byte(code), 0600); err != nil {
+				t.Fatal(err)
+			}
+			configPath := filepath.Join(dir, "function.yml")
+			config := "name: test-func\ncode:\n  path: " + codePath + "\n" + tc.config
+			if err := os.WriteFile(configPath, []byte(config), 0600); err != nil {
+				t.Fatal(err)
+			}
+
+			got, gotErr := frontier.NewLinter().Lint(ctx, configPath, tc.opts...)
+			if diff := cmp.Diff(tc.wantErr, gotErr, cmpopts.EquateErrors()); diff != "" {
+				t.Errorf("error (-want, +got):\n%s", diff)
+			}
+			// the paths in the temporary directory are replaced to compare
+			if got != nil {
+				got.CodePath = filepath.Base(got.CodePath)
+				for _, f := range got.Findings {
+					f.Path = filepath.Base(f.Path)
+				}
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("report (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
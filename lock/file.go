@@ -0,0 +1,265 @@
+package lock
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+type NewFileLockerOption interface {
+	applyNewFileLockerOption(l *FileLocker)
+}
+
+func Clock(now func() time.Time) NewFileLockerOption { return &optClock{now: now} } //nolint:ireturn
+
+type optClock struct{ now func() time.Time }
+
+var _ NewFileLockerOption = (*optClock)(nil)
+
+func (o *optClock) applyNewFileLockerOption(l *FileLocker) { l.now = o.now }
+
+func Owner(owner string) NewFileLockerOption { return &optOwner{owner: owner} } //nolint:ireturn
+
+type optOwner struct{ owner string }
+
+var _ NewFileLockerOption = (*optOwner)(nil)
+
+func (o *optOwner) applyNewFileLockerOption(l *FileLocker) { l.owner = o.owner }
+
+func NewFileLocker(dir string, opts ...NewFileLockerOption) *FileLocker {
+	l := &FileLocker{
+		dir:   dir,
+		now:   time.Now,
+		owner: defaultOwner(),
+	}
+	for _, o := range opts {
+		o.applyNewFileLockerOption(l)
+	}
+	return l
+}
+
+type FileLocker struct {
+	now   func() time.Time
+	dir   string
+	owner string
+}
+
+var _ Locker = (*FileLocker)(nil)
+
+func (l *FileLocker) TryLock(_ context.Context, key string, ttl time.Duration) (*Lease, error) {
+	if err := os.MkdirAll(l.dir, 0700); err != nil {
+		return nil, err
+	}
+	token, err := newToken()
+	if err != nil {
+		return nil, err
+	}
+	now := l.now()
+	lease := &Lease{
+		Key:        key,
+		Owner:      l.owner,
+		Token:      token,
+		AcquiredAt: now,
+		ExpiresAt:  now.Add(ttl),
+	}
+	path := l.leasePath(key)
+	err = writeLeaseExclusively(path, lease)
+	if !errors.Is(err, fs.ErrExist) {
+		if err != nil {
+			return nil, err
+		}
+		return lease, nil
+	}
+	current, err := readLease(path, key)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		// released after the creation failed
+		return createLease(path, lease)
+	case err != nil:
+		return nil, err
+	case current.Token == "":
+		// the lease without the token is being written by another process, or left by the process crashed while writing it
+		if err := removePartialLease(path, key, now.Add(-ttl)); err != nil && !errors.Is(err, ErrNotLocked) {
+			return nil, err
+		}
+		return createLease(path, lease)
+	case !current.expired(now):
+		return nil, &HeldError{Lease: current}
+	}
+	// take over the stale lease: removeLeaseIfMatched lets only one process remove it
+	if err := removeLeaseIfMatched(path, key, current.Token); err != nil && !errors.Is(err, ErrNotLocked) {
+		return nil, err
+	}
+	return createLease(path, lease)
+}
+
+// createLease creates the lease file or tells who holds the lock if another process created it first.
+func createLease(path string, lease *Lease) (*Lease, error) {
+	err := writeLeaseExclusively(path, lease)
+	if errors.Is(err, fs.ErrExist) {
+		current, readErr := readLease(path, lease.Key)
+		if readErr != nil {
+			return nil, readErr
+		}
+		return nil, &HeldError{Lease: current}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return lease, nil
+}
+
+func (l *FileLocker) Unlock(_ context.Context, lease *Lease) error {
+	return removeLeaseIfMatched(l.leasePath(lease.Key), lease.Key, lease.Token)
+}
+
+func (l *FileLocker) ForceUnlock(_ context.Context, key string) error {
+	path := l.leasePath(key)
+	// the guards left by the processes aborted while removing the lease
+	guards, err := filepath.Glob(guardPath(path, "*"))
+	if err != nil {
+		return err
+	}
+	for _, guard := range guards {
+		if err := os.Remove(guard); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+	}
+	err = os.Remove(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return ErrNotLocked
+	}
+	return err
+}
+
+func (l *FileLocker) leasePath(key string) string {
+	return filepath.Join(l.dir, strings.ReplaceAll(key, string(filepath.Separator), "_")+".lock")
+}
+
+func writeLeaseExclusively(path string, lease *Lease) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return err
+	}
+	if err := json.NewEncoder(f).Encode(lease); err != nil {
+		_ = f.Close()
+		_ = os.Remove(path)
+		return fmt.Errorf("json.Encoder.Encode: %w", err)
+	}
+	return f.Close()
+}
+
+// readLease reads the lease file; the lease being written by another process is returned as held by an unknown owner.
+func readLease(path, key string) (*Lease, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	lease := new(Lease)
+	if err := json.NewDecoder(f).Decode(lease); err != nil {
+		// the file is created before the lease is encoded into it
+		return &Lease{Key: key}, nil //nolint:nilerr
+	}
+	return lease, nil
+}
+
+// removeLeaseIfMatched removes the lease only if it has the token.
+//
+// Reading the lease and removing it are not atomic, so the removal is guarded by the file named after the token and created exclusively.
+// Only one process can remove the lease of the token at a time, and the lease written after the removal is never removed
+// because it has another token.
+func removeLeaseIfMatched(path, key, token string) error {
+	guard := guardPath(path, token)
+	f, err := os.OpenFile(guard, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if errors.Is(err, fs.ErrExist) {
+		// another process is removing the same lease
+		return ErrNotLocked
+	}
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.Remove(guard) }()
+	if err := f.Close(); err != nil {
+		return err
+	}
+	current, err := readLease(path, key)
+	if errors.Is(err, fs.ErrNotExist) {
+		return ErrNotLocked
+	}
+	if err != nil {
+		return err
+	}
+	if current.Token != token {
+		return &HeldError{Lease: current}
+	}
+	return os.Remove(path)
+}
+
+// removePartialLease removes the lease without the token only if the file is not modified after staleBefore.
+//
+// The lease without the token cannot tell when it expires, so the modification time of the file is regarded as the time it is acquired.
+// The removal is guarded in the same way as [removeLeaseIfMatched].
+func removePartialLease(path, key string, staleBefore time.Time) error {
+	guard := guardPath(path, "partial")
+	f, err := os.OpenFile(guard, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if errors.Is(err, fs.ErrExist) {
+		return ErrNotLocked
+	}
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.Remove(guard) }()
+	if err := f.Close(); err != nil {
+		return err
+	}
+	current, err := readLease(path, key)
+	if errors.Is(err, fs.ErrNotExist) {
+		return ErrNotLocked
+	}
+	if err != nil {
+		return err
+	}
+	if current.Token != "" {
+		return &HeldError{Lease: current}
+	}
+	stat, err := os.Stat(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return ErrNotLocked
+	}
+	if err != nil {
+		return err
+	}
+	if stat.ModTime().After(staleBefore) {
+		return &HeldError{Lease: current}
+	}
+	return os.Remove(path)
+}
+
+func guardPath(path, token string) string {
+	return path + "." + token + ".removing"
+}
+
+func newToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func defaultOwner() string {
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "unknown"
+	}
+	return fmt.Sprintf("%s/%d", hostname, os.Getpid())
+}
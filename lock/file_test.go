@@ -0,0 +1,205 @@
+package lock_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aereal/frontier/lock"
+)
+
+func TestFileLocker(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	if deadline, ok := t.Deadline(); ok {
+		ctx, cancel = context.WithDeadline(ctx, deadline)
+	}
+	defer cancel()
+
+	dir := t.TempDir()
+	now := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	clock := lock.Clock(func() time.Time { return now })
+	alice := lock.NewFileLocker(dir, clock, lock.Owner("alice"))
+	bob := lock.NewFileLocker(dir, clock, lock.Owner("bob"))
+	ttl := time.Minute
+
+	aliceLease, err := alice.TryLock(ctx, "test-fn", ttl)
+	if err != nil {
+		t.Fatalf("alice.TryLock: %+v", err)
+	}
+	if _, err := bob.TryLock(ctx, "test-fn", ttl); !isHeldBy(err, "alice") {
+		t.Errorf("bob.TryLock: want held by alice, got %v", err)
+	}
+	if _, err := bob.TryLock(ctx, "another-fn", ttl); err != nil {
+		t.Errorf("bob.TryLock(another-fn): %+v", err)
+	}
+
+	now = now.Add(ttl)
+	bobLease, err := bob.TryLock(ctx, "test-fn", ttl)
+	if err != nil {
+		t.Fatalf("bob.TryLock after expiration: %+v", err)
+	}
+	if err := alice.Unlock(ctx, aliceLease); !isHeldBy(err, "bob") {
+		t.Errorf("alice.Unlock: want held by bob, got %v", err)
+	}
+	if err := bob.Unlock(ctx, bobLease); err != nil {
+		t.Errorf("bob.Unlock: %+v", err)
+	}
+	if err := bob.Unlock(ctx, bobLease); !errors.Is(err, lock.ErrNotLocked) {
+		t.Errorf("bob.Unlock twice: want %v, got %v", lock.ErrNotLocked, err)
+	}
+
+	if _, err := alice.TryLock(ctx, "test-fn", ttl); err != nil {
+		t.Fatalf("alice.TryLock: %+v", err)
+	}
+	if err := bob.ForceUnlock(ctx, "test-fn"); err != nil {
+		t.Errorf("bob.ForceUnlock: %+v", err)
+	}
+	if err := bob.ForceUnlock(ctx, "test-fn"); !errors.Is(err, lock.ErrNotLocked) {
+		t.Errorf("bob.ForceUnlock twice: want %v, got %v", lock.ErrNotLocked, err)
+	}
+}
+
+func TestFileLocker_TryLock_concurrentTakeover(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	if deadline, ok := t.Deadline(); ok {
+		ctx, cancel = context.WithDeadline(ctx, deadline)
+	}
+	defer cancel()
+
+	const lockers = 16
+	ttl := time.Minute
+	for i := 0; i < 100; i++ {
+		dir := t.TempDir()
+		start := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+		if _, err := lock.NewFileLocker(dir, lock.Clock(func() time.Time { return start }), lock.Owner("stale")).TryLock(ctx, "test-fn", ttl); err != nil {
+			t.Fatal(err)
+		}
+		later := lock.Clock(func() time.Time { return start.Add(ttl) })
+		var (
+			wg       sync.WaitGroup
+			mux      sync.Mutex
+			acquired []*lock.Lease
+		)
+		for j := 0; j < lockers; j++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				lease, err := lock.NewFileLocker(dir, later, lock.Owner(fmt.Sprintf("locker-%d", j))).TryLock(ctx, "test-fn", ttl)
+				var heldErr *lock.HeldError
+				if err != nil && !errors.As(err, &heldErr) {
+					t.Errorf("TryLock: %+v", err)
+				}
+				if lease != nil {
+					mux.Lock()
+					acquired = append(acquired, lease)
+					mux.Unlock()
+				}
+			}()
+		}
+		wg.Wait()
+		if len(acquired) != 1 {
+			t.Fatalf("#%d: want exactly one lease, got %d", i, len(acquired))
+		}
+	}
+}
+
+func TestFileLocker_TryLock_partialLease(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	if deadline, ok := t.Deadline(); ok {
+		ctx, cancel = context.WithDeadline(ctx, deadline)
+	}
+	defer cancel()
+
+	for _, content := range []string{"", `{"key":"test-fn","own`} {
+		dir := t.TempDir()
+		if err := os.WriteFile(filepath.Join(dir, "test-fn.lock"), []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+		locker := lock.NewFileLocker(dir, lock.Owner("alice"))
+		if _, err := locker.TryLock(ctx, "test-fn", time.Minute); !isHeldBy(err, "") {
+			t.Errorf("TryLock(%q): want held, got %v", content, err)
+		}
+		if err := locker.ForceUnlock(ctx, "test-fn"); err != nil {
+			t.Fatalf("ForceUnlock: %+v", err)
+		}
+		if _, err := locker.TryLock(ctx, "test-fn", time.Minute); err != nil {
+			t.Errorf("TryLock after ForceUnlock: %+v", err)
+		}
+	}
+}
+
+func TestFileLocker_TryLock_crashedWriter(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	if deadline, ok := t.Deadline(); ok {
+		ctx, cancel = context.WithDeadline(ctx, deadline)
+	}
+	defer cancel()
+
+	for _, content := range []string{"", `{"key":"test-fn","own`} {
+		dir := t.TempDir()
+		path := filepath.Join(dir, "test-fn.lock")
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+		crashedAt := time.Now().Add(-2 * time.Minute)
+		if err := os.Chtimes(path, crashedAt, crashedAt); err != nil {
+			t.Fatal(err)
+		}
+		locker := lock.NewFileLocker(dir, lock.Owner("alice"))
+		lease, err := locker.TryLock(ctx, "test-fn", time.Minute)
+		if err != nil {
+			t.Fatalf("TryLock(%q): %+v", content, err)
+		}
+		if lease.Owner != "alice" {
+			t.Errorf("TryLock(%q): owner: want alice, got %s", content, lease.Owner)
+		}
+		if err := locker.Unlock(ctx, lease); err != nil {
+			t.Errorf("Unlock: %+v", err)
+		}
+	}
+}
+
+func TestFileLocker_ForceUnlock_leakedGuard(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	if deadline, ok := t.Deadline(); ok {
+		ctx, cancel = context.WithDeadline(ctx, deadline)
+	}
+	defer cancel()
+
+	dir := t.TempDir()
+	now := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	clock := lock.Clock(func() time.Time { return now })
+	ttl := time.Minute
+	stale, err := lock.NewFileLocker(dir, clock, lock.Owner("alice")).TryLock(ctx, "test-fn", ttl)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the process removing the stale lease was aborted
+	if err := os.WriteFile(filepath.Join(dir, "test-fn.lock."+stale.Token+".removing"), nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+	now = now.Add(ttl)
+	bob := lock.NewFileLocker(dir, clock, lock.Owner("bob"))
+	if _, err := bob.TryLock(ctx, "test-fn", ttl); !isHeldBy(err, "alice") {
+		t.Errorf("TryLock: want held by alice, got %v", err)
+	}
+	if err := bob.ForceUnlock(ctx, "test-fn"); err != nil {
+		t.Fatalf("ForceUnlock: %+v", err)
+	}
+	if _, err := bob.TryLock(ctx, "test-fn", ttl); err != nil {
+		t.Errorf("TryLock after ForceUnlock: %+v", err)
+	}
+	if guards, _ := filepath.Glob(filepath.Join(dir, "*.removing")); len(guards) > 0 {
+		t.Errorf("guards are left: %v", guards)
+	}
+}
+
+func isHeldBy(err error, owner string) bool {
+	var heldErr *lock.HeldError
+	return errors.As(err, &heldErr) && heldErr.Lease.Owner == owner
+}
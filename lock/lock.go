@@ -0,0 +1,57 @@
+package lock
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+type Locker interface {
+	TryLock(ctx context.Context, key string, ttl time.Duration) (*Lease, error)
+	Unlock(ctx context.Context, lease *Lease) error
+	ForceUnlock(ctx context.Context, key string) error
+}
+
+type Lease struct {
+	Key        string    `json:"key"`
+	Owner      string    `json:"owner"`
+	Token      string    `json:"token"`
+	AcquiredAt time.Time `json:"acquiredAt"`
+	ExpiresAt  time.Time `json:"expiresAt"`
+}
+
+func (l *Lease) expired(now time.Time) bool {
+	return !now.Before(l.ExpiresAt)
+}
+
+type HeldError struct {
+	Lease *Lease
+}
+
+func (e *HeldError) Error() string {
+	if e.Lease.Owner == "" {
+		return fmt.Sprintf("%s is being deployed by another process", e.Lease.Key)
+	}
+	return fmt.Sprintf("%s is being deployed by %s since %s (expires at %s)", e.Lease.Key, e.Lease.Owner, e.Lease.AcquiredAt.Format(time.RFC3339), e.Lease.ExpiresAt.Format(time.RFC3339))
+}
+
+var ErrNotLocked = errors.New("not locked")
+
+func Wait(ctx context.Context, locker Locker, key string, ttl, wait, interval time.Duration) (*Lease, error) {
+	deadline := time.Now().Add(wait)
+	for {
+		lease, err := locker.TryLock(ctx, key, ttl)
+		var heldErr *HeldError
+		if !errors.As(err, &heldErr) || !time.Now().Add(interval).Before(deadline) {
+			return lease, err
+		}
+		timer := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
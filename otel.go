@@ -0,0 +1,83 @@
+package frontier
+
+import (
+	"context"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "github.com/aereal/frontier"
+
+const (
+	attrFunctionName  = attribute.Key("frontier.function.name")
+	attrFunctionStage = attribute.Key("frontier.function.stage")
+	attrETag          = attribute.Key("frontier.function.etag")
+	attrConfigPath    = attribute.Key("frontier.config.path")
+	attrCodePath      = attribute.Key("frontier.code.path")
+	attrCodeSize      = attribute.Key("frontier.code.size")
+	attrCodeSHA256    = attribute.Key("frontier.code.sha256")
+	attrDeployAction  = attribute.Key("frontier.deploy.action")
+	attrDeployPublish = attribute.Key("frontier.deploy.publish")
+	attrOperation     = attribute.Key("frontier.operation")
+	attrOutcome       = attribute.Key("frontier.outcome")
+)
+
+// startSpan and the metric recorders look up the global providers on each call,
+// so that the providers configured after the package initialization are used.
+func startSpan(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) { //nolint:ireturn
+	return otel.GetTracerProvider().Tracer(instrumentationName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// traceEnviron returns the environment variables such as TRACEPARENT, so that the child processes can continue the trace.
+func traceEnviron(ctx context.Context) []string {
+	carrier := propagation.MapCarrier{}
+	otel.GetTextMapPropagator().Inject(ctx, carrier)
+	env := make([]string, 0, len(carrier))
+	for _, key := range carrier.Keys() {
+		env = append(env, strings.ToUpper(key)+"="+carrier.Get(key))
+	}
+	return env
+}
+
+func outcomeOf(err error) attribute.KeyValue {
+	if err != nil {
+		return attrOutcome.String("failure")
+	}
+	return attrOutcome.String("success")
+}
+
+func recordDeployDuration(ctx context.Context, seconds float64, attrs ...attribute.KeyValue) {
+	h, err := otel.GetMeterProvider().Meter(instrumentationName).Float64Histogram("frontier.deploy.duration",
+		metric.WithUnit("s"),
+		metric.WithDescription("duration of the deployment including hooks"))
+	if err != nil {
+		otel.Handle(err)
+		return
+	}
+	h.Record(ctx, seconds, metric.WithAttributes(attrs...))
+}
+
+func recordCodeSize(ctx context.Context, size int, attrs ...attribute.KeyValue) {
+	h, err := otel.GetMeterProvider().Meter(instrumentationName).Int64Histogram("frontier.function.code.size",
+		metric.WithUnit("By"),
+		metric.WithDescription("size of the function code deployed or imported"))
+	if err != nil {
+		otel.Handle(err)
+		return
+	}
+	h.Record(ctx, int64(size), metric.WithAttributes(attrs...))
+}
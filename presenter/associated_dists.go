@@ -0,0 +1,20 @@
+package presenter
+
+import (
+	"iter"
+
+	"github.com/aereal/frontier"
+)
+
+type AssociatedDistributionsPresenter interface {
+	PresentAssociatedDistributions(associations iter.Seq2[frontier.FunctionAssociation, error]) error
+}
+
+// ResultPresenter presents the result of a command such as DeployResult and DriftReport.
+type ResultPresenter[T any] interface {
+	PresentResult(result T) error
+}
+
+type LintReportPresenter interface {
+	PresentLintReport(report *frontier.LintReport) error
+}
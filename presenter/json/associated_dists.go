@@ -3,9 +3,10 @@ package json
 import (
 	"encoding/json"
 	"io"
+	"iter"
 
 	"github.com/aereal/frontier"
-	"github.com/aereal/frontier/internal/presenter"
+	"github.com/aereal/frontier/presenter"
 )
 
 type NewAssociatedDistributionsPresenterOption interface {
@@ -16,12 +17,18 @@ type configNewAssociatedDistributionsPresenter struct {
 	pretty bool
 }
 
-func Pretty(pretty bool) NewAssociatedDistributionsPresenterOption { return &optPretty{pretty: pretty} } //nolint:ireturn
+type PrettyOption interface {
+	NewAssociatedDistributionsPresenterOption
+	NewResultPresenterOption
+}
+
+func Pretty(pretty bool) PrettyOption { return &optPretty{pretty: pretty} } //nolint:ireturn
 
 type optPretty struct{ pretty bool }
 
 var (
 	_ NewAssociatedDistributionsPresenterOption = (*optPretty)(nil)
+	_ NewResultPresenterOption                  = (*optPretty)(nil)
 )
 
 func (o *optPretty) applyNewAssociatedDistributionsPresenterOption(cfg *configNewAssociatedDistributionsPresenter) {
@@ -48,8 +55,14 @@ type AssociatedDistributionsPresenter struct {
 
 var _ presenter.AssociatedDistributionsPresenter = (*AssociatedDistributionsPresenter)(nil)
 
-func (p *AssociatedDistributionsPresenter) PresentAssociatedDistributions(associations []frontier.FunctionAssociation) {
-	for _, a := range associations {
-		_ = p.enc.Encode(a) //nolint:errchkjson
+func (p *AssociatedDistributionsPresenter) PresentAssociatedDistributions(associations iter.Seq2[frontier.FunctionAssociation, error]) error {
+	for a, err := range associations {
+		if err != nil {
+			return err
+		}
+		if err := p.enc.Encode(a); err != nil {
+			return err
+		}
 	}
+	return nil
 }
@@ -2,10 +2,11 @@ package json_test
 
 import (
 	"bytes"
+	"iter"
 	"testing"
 
 	"github.com/aereal/frontier"
-	"github.com/aereal/frontier/internal/presenter/json"
+	"github.com/aereal/frontier/presenter/json"
 	"github.com/google/go-cmp/cmp"
 )
 
@@ -64,7 +65,9 @@ func TestAssociatedDistributionsPresenter(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			out := new(bytes.Buffer)
 			presenter := json.NewAssociatedDistributionsPresenter(out, tc.options...)
-			presenter.PresentAssociatedDistributions(tc.input)
+			if err := presenter.PresentAssociatedDistributions(values(tc.input)); err != nil {
+				t.Fatal(err)
+			}
 			got := out.String()
 			if diff := cmp.Diff(tc.want, got); diff != "" {
 				t.Logf("got: %q", got)
@@ -73,3 +76,13 @@ func TestAssociatedDistributionsPresenter(t *testing.T) {
 		})
 	}
 }
+
+func values[T any](xs []T) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		for _, x := range xs {
+			if !yield(x, nil) {
+				return
+			}
+		}
+	}
+}
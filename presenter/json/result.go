@@ -0,0 +1,44 @@
+package json
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/aereal/frontier"
+	"github.com/aereal/frontier/presenter"
+)
+
+type NewResultPresenterOption interface {
+	applyNewResultPresenterOption(cfg *configNewResultPresenter)
+}
+
+type configNewResultPresenter struct {
+	pretty bool
+}
+
+func (o *optPretty) applyNewResultPresenterOption(cfg *configNewResultPresenter) {
+	cfg.pretty = o.pretty
+}
+
+// NewResultPresenter returns the presenter that writes each result as a JSON value.
+func NewResultPresenter[T any](out io.Writer, opts ...NewResultPresenterOption) *ResultPresenter[T] {
+	var cfg configNewResultPresenter
+	for _, o := range opts {
+		o.applyNewResultPresenterOption(&cfg)
+	}
+	enc := json.NewEncoder(out)
+	if cfg.pretty {
+		enc.SetIndent("", "  ")
+	}
+	return &ResultPresenter[T]{enc: enc}
+}
+
+type ResultPresenter[T any] struct {
+	enc *json.Encoder
+}
+
+var _ presenter.ResultPresenter[*frontier.DeployResult] = (*ResultPresenter[*frontier.DeployResult])(nil)
+
+func (p *ResultPresenter[T]) PresentResult(result T) error {
+	return p.enc.Encode(result)
+}
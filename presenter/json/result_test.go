@@ -0,0 +1,50 @@
+package json_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/aereal/frontier"
+	"github.com/aereal/frontier/presenter/json"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestResultPresenter(t *testing.T) {
+	result := &frontier.DeployResult{
+		FunctionName: "test-fn",
+		FunctionARN:  "arn:aws:cloudfront::123456789012:function/test-fn",
+		Action:       frontier.DeployActionUpdated,
+		ETagBefore:   "E1",
+		ETagAfter:    "E2",
+		Published:    true,
+		Stage:        "LIVE",
+	}
+	testCases := []struct {
+		name    string
+		options []json.NewResultPresenterOption
+		want    string
+	}{
+		{
+			name: "compact",
+			want: `{"FunctionName":"test-fn","FunctionARN":"arn:aws:cloudfront::123456789012:function/test-fn","Action":"updated","ETagBefore":"E1","ETagAfter":"E2","CodeSHA256":"","Published":true,"Stage":"LIVE","Duration":0}` + "\n",
+		},
+		{
+			name:    "pretty",
+			options: []json.NewResultPresenterOption{json.Pretty(true)},
+			want:    "{\n  \"FunctionName\": \"test-fn\",\n  \"FunctionARN\": \"arn:aws:cloudfront::123456789012:function/test-fn\",\n  \"Action\": \"updated\",\n  \"ETagBefore\": \"E1\",\n  \"ETagAfter\": \"E2\",\n  \"CodeSHA256\": \"\",\n  \"Published\": true,\n  \"Stage\": \"LIVE\",\n  \"Duration\": 0\n}\n",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			out := new(bytes.Buffer)
+			if err := json.NewResultPresenter[*frontier.DeployResult](out, tc.options...).PresentResult(result); err != nil {
+				t.Fatal(err)
+			}
+			got := out.String()
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Logf("got: %q", got)
+				t.Errorf("(-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,136 @@
+package sarif
+
+import (
+	"encoding/json"
+	"io"
+	"path/filepath"
+
+	"github.com/aereal/frontier"
+	"github.com/aereal/frontier/lint"
+	"github.com/aereal/frontier/presenter"
+)
+
+const (
+	schemaURI = "https://json.schemastore.org/sarif-2.1.0.json"
+	version   = "2.1.0"
+	toolName  = "frontier"
+	toolURI   = "https://github.com/aereal/frontier"
+)
+
+// NewLintReportPresenter returns the presenter that writes the report in SARIF 2.1.0 for the code scanning services.
+func NewLintReportPresenter(out io.Writer) *LintReportPresenter {
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", "  ")
+	return &LintReportPresenter{enc: enc}
+}
+
+type LintReportPresenter struct {
+	enc *json.Encoder
+}
+
+var _ presenter.LintReportPresenter = (*LintReportPresenter)(nil)
+
+func (p *LintReportPresenter) PresentLintReport(report *frontier.LintReport) error {
+	rules := lint.Rules()
+	driver := &toolComponent{Name: toolName, InformationURI: toolURI, Rules: make([]*reportingDescriptor, 0, len(rules))}
+	for _, r := range rules {
+		driver.Rules = append(driver.Rules, &reportingDescriptor{
+			ID:                   r.ID,
+			ShortDescription:     &message{Text: r.Description},
+			DefaultConfiguration: &reportingConfiguration{Level: levelOf(r.DefaultSeverity)},
+		})
+	}
+	results := make([]*result, 0, len(report.Findings))
+	for _, f := range report.Findings {
+		results = append(results, &result{
+			RuleID:  f.RuleID,
+			Level:   levelOf(f.Severity),
+			Message: &message{Text: f.Message},
+			Locations: []*location{
+				{
+					PhysicalLocation: &physicalLocation{
+						ArtifactLocation: &artifactLocation{URI: filepath.ToSlash(f.Path)},
+						Region:           &region{StartLine: f.Line, StartColumn: f.Column},
+					},
+				},
+			},
+		})
+	}
+	return p.enc.Encode(&log{
+		Schema:  schemaURI,
+		Version: version,
+		Runs:    []*run{{Tool: &tool{Driver: driver}, ColumnKind: "unicodeCodePoints", Results: results}},
+	})
+}
+
+func levelOf(severity lint.Severity) string {
+	switch severity {
+	case lint.SeverityError:
+		return "error"
+	case lint.SeverityWarning:
+		return "warning"
+	default:
+		return "none"
+	}
+}
+
+type log struct {
+	Schema  string `json:"$schema"`
+	Version string `json:"version"`
+	Runs    []*run `json:"runs"`
+}
+
+type run struct {
+	Tool       *tool     `json:"tool"`
+	ColumnKind string    `json:"columnKind"`
+	Results    []*result `json:"results"`
+}
+
+type tool struct {
+	Driver *toolComponent `json:"driver"`
+}
+
+type toolComponent struct {
+	Name           string                 `json:"name"`
+	InformationURI string                 `json:"informationUri"`
+	Rules          []*reportingDescriptor `json:"rules"`
+}
+
+type reportingDescriptor struct {
+	ID                   string                  `json:"id"`
+	ShortDescription     *message                `json:"shortDescription"`
+	DefaultConfiguration *reportingConfiguration `json:"defaultConfiguration"`
+}
+
+type reportingConfiguration struct {
+	Level string `json:"level"`
+}
+
+type result struct {
+	RuleID    string      `json:"ruleId"`
+	Level     string      `json:"level"`
+	Message   *message    `json:"message"`
+	Locations []*location `json:"locations"`
+}
+
+type message struct {
+	Text string `json:"text"`
+}
+
+type location struct {
+	PhysicalLocation *physicalLocation `json:"physicalLocation"`
+}
+
+type physicalLocation struct {
+	ArtifactLocation *artifactLocation `json:"artifactLocation"`
+	Region           *region           `json:"region"`
+}
+
+type artifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type region struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn"`
+}
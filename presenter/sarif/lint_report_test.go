@@ -0,0 +1,98 @@
+package sarif_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/aereal/frontier"
+	"github.com/aereal/frontier/lint"
+	"github.com/aereal/frontier/presenter/sarif"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestLintReportPresenter(t *testing.T) {
+	report := &frontier.LintReport{
+		FunctionName: "test-fn",
+		CodePath:     "src/fn.js",
+		EventType:    lint.EventTypeViewerRequest,
+		Findings: []*lint.Finding{
+			{RuleID: lint.RuleNoTimers, Severity: lint.SeverityError, Message: "setTimeout is not available", Path: "src/fn.js", Line: 4, Column: 3},
+			{RuleID: lint.RuleQuerystringAsString, Severity: lint.SeverityWarning, Message: "request.querystring is not a string", Path: "src/fn.js", Line: 5, Column: 23},
+		},
+	}
+	buf := new(bytes.Buffer)
+	if err := sarif.NewLintReportPresenter(buf).PresentLintReport(report); err != nil {
+		t.Fatal(err)
+	}
+	var got struct {
+		Schema  string `json:"$schema"`
+		Version string `json:"version"`
+		Runs    []struct {
+			Tool struct {
+				Driver struct {
+					Name  string `json:"name"`
+					Rules []struct {
+						ID                   string `json:"id"`
+						DefaultConfiguration struct {
+							Level string `json:"level"`
+						} `json:"defaultConfiguration"`
+					} `json:"rules"`
+				} `json:"driver"`
+			} `json:"tool"`
+			ColumnKind string           `json:"columnKind"`
+			Results    []map[string]any `json:"results"`
+		} `json:"runs"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Schema != "https://json.schemastore.org/sarif-2.1.0.json" || got.Version != "2.1.0" {
+		t.Errorf("schema: %q, version: %q", got.Schema, got.Version)
+	}
+	if len(got.Runs) != 1 {
+		t.Fatalf("runs: %d", len(got.Runs))
+	}
+	run := got.Runs[0]
+	if run.Tool.Driver.Name != "frontier" || run.ColumnKind != "unicodeCodePoints" {
+		t.Errorf("driver: %q, columnKind: %q", run.Tool.Driver.Name, run.ColumnKind)
+	}
+	if len(run.Tool.Driver.Rules) != len(lint.Rules()) {
+		t.Errorf("rules: want %d, got %d", len(lint.Rules()), len(run.Tool.Driver.Rules))
+	}
+	for i, r := range lint.Rules() {
+		if i >= len(run.Tool.Driver.Rules) {
+			break
+		}
+		if got := run.Tool.Driver.Rules[i]; got.ID != r.ID || got.DefaultConfiguration.Level != string(r.DefaultSeverity) {
+			t.Errorf("rules[%d]: want %s (%s), got %s (%s)", i, r.ID, r.DefaultSeverity, got.ID, got.DefaultConfiguration.Level)
+		}
+	}
+	wantResults := []map[string]any{
+		{
+			"ruleId":  "no-timers",
+			"level":   "error",
+			"message": map[string]any{"text": "setTimeout is not available"},
+			"locations": []any{
+				map[string]any{"physicalLocation": map[string]any{
+					"artifactLocation": map[string]any{"uri": "src/fn.js"},
+					"region":           map[string]any{"startLine": float64(4), "startColumn": float64(3)},
+				}},
+			},
+		},
+		{
+			"ruleId":  "querystring-as-string",
+			"level":   "warning",
+			"message": map[string]any{"text": "request.querystring is not a string"},
+			"locations": []any{
+				map[string]any{"physicalLocation": map[string]any{
+					"artifactLocation": map[string]any{"uri": "src/fn.js"},
+					"region":           map[string]any{"startLine": float64(5), "startColumn": float64(23)},
+				}},
+			},
+		},
+	}
+	if diff := cmp.Diff(wantResults, run.Results); diff != "" {
+		t.Errorf("results (-want, +got):\n%s", diff)
+	}
+}
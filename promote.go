@@ -0,0 +1,175 @@
+package frontier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/aereal/frontier/cf"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudfront/types"
+)
+
+// PromoteEndpoint is the function in the account that the provider sends requests to.
+type PromoteEndpoint struct {
+	Provider     cf.Provider
+	FunctionName string
+	// KeyValueStoreARNs maps the ARNs of the key value stores associated with the source to the ARNs in the account of the target.
+	// It is required if the source is associated with key value stores and the target is in another account.
+	KeyValueStoreARNs map[string]string
+}
+
+// UnmappedKeyValueStoreError is returned if the source is associated with the key value stores
+// that the target in another account cannot be associated with.
+type UnmappedKeyValueStoreError struct {
+	FunctionName string
+	ARNs         []string
+}
+
+func (e *UnmappedKeyValueStoreError) Error() string {
+	return fmt.Sprintf("function %s is associated with the key value stores in another account, which are not mapped to the target: %s", e.FunctionName, strings.Join(e.ARNs, ", "))
+}
+
+func (e *UnmappedKeyValueStoreError) Is(other error) bool {
+	otherErr := new(UnmappedKeyValueStoreError)
+	if !errors.As(other, &otherErr) {
+		return false
+	}
+	return otherErr.FunctionName == e.FunctionName && slices.Equal(otherErr.ARNs, e.ARNs)
+}
+
+type PromoteResult struct {
+	DeployResult
+	SourceFunctionName string
+	SourceETag         string
+}
+
+// NewPromoter returns the Promoter; the options are applied to the deployer of the target function.
+func NewPromoter(opts ...NewDeployerOption) *Promoter {
+	return &Promoter{deployerOpts: opts}
+}
+
+type Promoter struct {
+	deployerOpts []NewDeployerOption
+}
+
+// Promote creates or updates the target function with the code and the config of the LIVE stage of the source function.
+// The comment of the target records the ETag and the code hash of the source.
+func (p *Promoter) Promote(ctx context.Context, source, target *PromoteEndpoint, publish bool, opts ...DeployOption) (_ *PromoteResult, err error) {
+	startedAt := time.Now()
+	ctx, span := startSpan(ctx, "frontier.Promote", attrFunctionName.String(target.FunctionName), attrDeployPublish.Bool(publish))
+	defer func() { endSpan(span, err) }()
+	var cfg configDeploy
+	for _, o := range opts {
+		o.applyDeployOption(&cfg)
+	}
+	fn, remote, err := NewImporter(source.Provider).fetchFunction(ctx, source.FunctionName, types.FunctionStageLive)
+	if err != nil {
+		return nil, err
+	}
+	result := &PromoteResult{
+		SourceFunctionName: fn.Name,
+		SourceETag:         aws.ToString(remote.ETag),
+		DeployResult:       DeployResult{FunctionName: target.FunctionName},
+	}
+	if err := mapKeyValueStores(ctx, fn, source, target); err != nil {
+		return nil, err
+	}
+	fn.Name = target.FunctionName
+	fn.Config.Comment = promotedComment(fn.Config.Comment, result.SourceFunctionName, result.SourceETag, codeSHA256(remote.Code))
+
+	deployer := NewDeployer(target.Provider, p.deployerOpts...)
+	release, err := deployer.lock(ctx, fn.Name, cfg.lockWait)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	if err := deployer.upsert(ctx, fn, remote.Code, publish, &result.DeployResult); err != nil {
+		return nil, err
+	}
+	result.Duration = time.Since(startedAt)
+	return result, nil
+}
+
+// mapKeyValueStores replaces the key value stores of the function with the ARNs mapped by the target.
+// The unmapped ones are kept only if both endpoints are in the same account, because the key value stores belong to the account.
+func mapKeyValueStores(ctx context.Context, fn *Function, source, target *PromoteEndpoint) error {
+	if len(fn.Config.KeyValueStoreAssociations) == 0 {
+		return nil
+	}
+	var unmapped []string
+	for i, kvs := range fn.Config.KeyValueStoreAssociations {
+		if arn, ok := target.KeyValueStoreARNs[kvs.ARN]; ok {
+			fn.Config.KeyValueStoreAssociations[i].ARN = arn
+			continue
+		}
+		unmapped = append(unmapped, kvs.ARN)
+	}
+	if len(unmapped) == 0 {
+		return nil
+	}
+	same, err := sameAccount(ctx, source.Provider, target.Provider)
+	if err != nil {
+		return err
+	}
+	if !same {
+		return &UnmappedKeyValueStoreError{FunctionName: fn.Name, ARNs: unmapped}
+	}
+	return nil
+}
+
+// sameAccount tells whether both providers send requests to the same account.
+// The providers that do not tell the account are regarded as in different accounts unless they are same.
+func sameAccount(ctx context.Context, a, b cf.Provider) (bool, error) {
+	if a == b {
+		return true, nil
+	}
+	identityA, okA := a.(cf.IdentityProvider)
+	identityB, okB := b.(cf.IdentityProvider)
+	if !okA || !okB {
+		return false, nil
+	}
+	idA, err := identityA.ProvideIdentity(ctx)
+	if err != nil {
+		return false, err
+	}
+	idB, err := identityB.ProvideIdentity(ctx)
+	if err != nil {
+		return false, err
+	}
+	if idA == nil || idB == nil || idA.AccountID == "" {
+		return false, nil
+	}
+	return idA.AccountID == idB.AccountID, nil
+}
+
+const (
+	// maxCommentLength is the limit of the length of the function comment by CloudFront.
+	maxCommentLength      = 128
+	promotedCommentPrefix = "[promoted from "
+)
+
+// promotedComment appends the record of the promotion to the comment replacing the previous one.
+// The comment is truncated to keep the record within the limit.
+func promotedComment(comment, sourceName, etag, sha256 string) string {
+	if i := strings.Index(comment, promotedCommentPrefix); i >= 0 {
+		comment = comment[:i]
+	}
+	comment = strings.TrimSpace(comment)
+	record := fmt.Sprintf("%s%s etag=%s sha256=%s]", promotedCommentPrefix, sourceName, etag, sha256[:12])
+	room := maxCommentLength - len(record) - 1
+	if room <= 0 || comment == "" {
+		return record
+	}
+	if len(comment) > room {
+		comment = comment[:room]
+		for !utf8.ValidString(comment) {
+			comment = comment[:len(comment)-1]
+		}
+	}
+	return comment + " " + record
+}
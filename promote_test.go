@@ -0,0 +1,254 @@
+package frontier_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/aereal/frontier"
+	"github.com/aereal/frontier/cf"
+	"github.com/aereal/frontier/internal/cfmock"
+	"github.com/aws/aws-sdk-go-v2/service/cloudfront"
+	"github.com/aws/aws-sdk-go-v2/service/cloudfront/types"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"go.uber.org/mock/gomock"
+)
+
+func TestPromoter_Promote(t *testing.T) {
+	promotedComment := "blah blah [promoted from test-func etag=etag-LIVE sha256=" + sha256Hex(functionCode)[:12] + "]"
+	testCases := []struct {
+		name       string
+		publish    bool
+		mockSource func(c *cfmock.MockCloudFrontClient)
+		mockTarget func(c *cfmock.MockCloudFrontClient)
+		wantResult *frontier.PromoteResult
+		wantErr    error
+	}{
+		{
+			name:    "update and publish",
+			publish: true,
+			mockSource: func(c *cfmock.MockCloudFrontClient) {
+				returnStage(c, types.FunctionStageLive, functionCode, "blah blah")
+			},
+			mockTarget: func(c *cfmock.MockCloudFrontClient) {
+				c.EXPECT().
+					GetFunction(gomock.Any(), &cloudfront.GetFunctionInput{Name: ref("test-func-prod")}).
+					Return(&cloudfront.GetFunctionOutput{ETag: ref("etag-prod")}, nil).
+					Times(1)
+				c.EXPECT().
+					UpdateFunction(gomock.Any(), &cloudfront.UpdateFunctionInput{
+						Name:           ref("test-func-prod"),
+						FunctionCode:   functionCode,
+						IfMatch:        ref("etag-prod"),
+						FunctionConfig: &types.FunctionConfig{Comment: ref(promotedComment), Runtime: types.FunctionRuntimeCloudfrontJs10},
+					}).
+					Return(&cloudfront.UpdateFunctionOutput{ETag: ref("etag-prod-updated")}, nil).
+					Times(1)
+				c.EXPECT().
+					PublishFunction(gomock.Any(), &cloudfront.PublishFunctionInput{Name: ref("test-func-prod"), IfMatch: ref("etag-prod-updated")}).
+					Return(&cloudfront.PublishFunctionOutput{}, nil).
+					Times(1)
+			},
+			wantResult: &frontier.PromoteResult{
+				SourceFunctionName: "test-func",
+				SourceETag:         "etag-LIVE",
+				DeployResult: frontier.DeployResult{
+					FunctionName: "test-func-prod",
+					Action:       frontier.DeployActionUpdated,
+					ETagBefore:   "etag-prod",
+					ETagAfter:    "etag-prod-updated",
+					CodeSHA256:   sha256Hex(functionCode),
+					Published:    true,
+					Stage:        types.FunctionStageLive,
+				},
+			},
+		},
+		{
+			name: "create",
+			mockSource: func(c *cfmock.MockCloudFrontClient) {
+				returnStage(c, types.FunctionStageLive, functionCode, "blah blah [promoted from test-func-dev etag=E1 sha256=000000000000]")
+			},
+			mockTarget: func(c *cfmock.MockCloudFrontClient) {
+				c.EXPECT().
+					GetFunction(gomock.Any(), &cloudfront.GetFunctionInput{Name: ref("test-func-prod")}).
+					Return(nil, errNoSuchFn).
+					Times(1)
+				c.EXPECT().
+					CreateFunction(gomock.Any(), &cloudfront.CreateFunctionInput{
+						Name:           ref("test-func-prod"),
+						FunctionCode:   functionCode,
+						FunctionConfig: &types.FunctionConfig{Comment: ref(promotedComment), Runtime: types.FunctionRuntimeCloudfrontJs10},
+					}).
+					Return(&cloudfront.CreateFunctionOutput{ETag: ref("etag-prod")}, nil).
+					Times(1)
+			},
+			wantResult: &frontier.PromoteResult{
+				SourceFunctionName: "test-func",
+				SourceETag:         "etag-LIVE",
+				DeployResult: frontier.DeployResult{
+					FunctionName: "test-func-prod",
+					Action:       frontier.DeployActionCreated,
+					ETagAfter:    "etag-prod",
+					CodeSHA256:   sha256Hex(functionCode),
+					Stage:        types.FunctionStageDevelopment,
+				},
+			},
+		},
+		{
+			name: "long comment",
+			mockSource: func(c *cfmock.MockCloudFrontClient) {
+				returnStage(c, types.FunctionStageLive, functionCode, strings.Repeat("あ", 100))
+			},
+			mockTarget: func(c *cfmock.MockCloudFrontClient) {
+				c.EXPECT().
+					GetFunction(gomock.Any(), gomock.Any()).
+					Return(nil, errNoSuchFn).
+					Times(1)
+				c.EXPECT().
+					CreateFunction(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, input *cloudfront.CreateFunctionInput, _ ...func(*cloudfront.Options)) (*cloudfront.CreateFunctionOutput, error) {
+						comment := *input.FunctionConfig.Comment
+						if len(comment) > 128 {
+							t.Errorf("comment is too long: %d bytes", len(comment))
+						}
+						if record := " [promoted from test-func etag=etag-LIVE sha256=" + sha256Hex(functionCode)[:12] + "]"; !strings.HasPrefix(comment, "あ") || !strings.HasSuffix(comment, "あ"+record) {
+							t.Errorf("comment is not truncated at the character boundary: %q", comment)
+						}
+						return &cloudfront.CreateFunctionOutput{ETag: ref("etag-prod")}, nil
+					}).
+					Times(1)
+			},
+			wantResult: &frontier.PromoteResult{
+				SourceFunctionName: "test-func",
+				SourceETag:         "etag-LIVE",
+				DeployResult: frontier.DeployResult{
+					FunctionName: "test-func-prod",
+					Action:       frontier.DeployActionCreated,
+					ETagAfter:    "etag-prod",
+					CodeSHA256:   sha256Hex(functionCode),
+					Stage:        types.FunctionStageDevelopment,
+				},
+			},
+		},
+		{
+			name: "source not published",
+			mockSource: func(c *cfmock.MockCloudFrontClient) {
+				returnNoSuchFunction(c, types.FunctionStageLive)
+			},
+			mockTarget: func(*cfmock.MockCloudFrontClient) {},
+			wantErr:    errNoSuchFn,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			if deadline, ok := t.Deadline(); ok {
+				ctx, cancel = context.WithDeadline(ctx, deadline)
+			}
+			defer cancel()
+
+			ctrl := gomock.NewController(t)
+			sourceClient := cfmock.NewMockCloudFrontClient(ctrl)
+			tc.mockSource(sourceClient)
+			targetClient := cfmock.NewMockCloudFrontClient(ctrl)
+			tc.mockTarget(targetClient)
+			source := &frontier.PromoteEndpoint{Provider: &cf.StaticCFProvider{Client: sourceClient}, FunctionName: "test-func"}
+			target := &frontier.PromoteEndpoint{Provider: &cf.StaticCFProvider{Client: targetClient}, FunctionName: "test-func-prod"}
+			gotResult, gotErr := frontier.NewPromoter().Promote(ctx, source, target, tc.publish)
+			if !errors.Is(gotErr, tc.wantErr) {
+				t.Errorf("error:\n\twant: %s (%T)\n\t got: %s (%T)", tc.wantErr, tc.wantErr, gotErr, gotErr)
+			}
+			if diff := cmp.Diff(tc.wantResult, gotResult, cmpopts.IgnoreFields(frontier.DeployResult{}, "Duration")); diff != "" {
+				t.Errorf("result (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestPromoter_Promote_keyValueStores(t *testing.T) {
+	sourceKVS := "arn:aws:cloudfront::111111111111:key-value-store/kvs-stg"
+	targetKVS := "arn:aws:cloudfront::222222222222:key-value-store/kvs-prod"
+	testCases := []struct {
+		name           string
+		sourceIdentity *cf.Identity
+		targetIdentity *cf.Identity
+		mapping        map[string]string
+		wantKVS        string
+		wantErr        error
+	}{
+		{
+			name:           "another account",
+			sourceIdentity: &cf.Identity{AccountID: "111111111111"},
+			targetIdentity: &cf.Identity{AccountID: "222222222222"},
+			wantErr:        &frontier.UnmappedKeyValueStoreError{FunctionName: "test-func", ARNs: []string{sourceKVS}},
+		},
+		{
+			name:    "unknown account",
+			wantErr: &frontier.UnmappedKeyValueStoreError{FunctionName: "test-func", ARNs: []string{sourceKVS}},
+		},
+		{
+			name:           "mapped",
+			sourceIdentity: &cf.Identity{AccountID: "111111111111"},
+			targetIdentity: &cf.Identity{AccountID: "222222222222"},
+			mapping:        map[string]string{sourceKVS: targetKVS},
+			wantKVS:        targetKVS,
+		},
+		{
+			name:           "same account",
+			sourceIdentity: &cf.Identity{AccountID: "111111111111"},
+			targetIdentity: &cf.Identity{AccountID: "111111111111"},
+			wantKVS:        sourceKVS,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			if deadline, ok := t.Deadline(); ok {
+				ctx, cancel = context.WithDeadline(ctx, deadline)
+			}
+			defer cancel()
+
+			ctrl := gomock.NewController(t)
+			sourceClient := cfmock.NewMockCloudFrontClient(ctrl)
+			sourceClient.EXPECT().
+				GetFunction(gomock.Any(), gomock.Any()).
+				Return(&cloudfront.GetFunctionOutput{FunctionCode: functionCode, ETag: ref("etag-LIVE")}, nil).
+				Times(1)
+			sourceClient.EXPECT().
+				DescribeFunction(gomock.Any(), gomock.Any()).
+				Return(&cloudfront.DescribeFunctionOutput{FunctionSummary: &types.FunctionSummary{
+					Name: ref("test-func"),
+					FunctionConfig: &types.FunctionConfig{
+						Comment:                   ref("blah blah"),
+						Runtime:                   types.FunctionRuntimeCloudfrontJs20,
+						KeyValueStoreAssociations: &types.KeyValueStoreAssociations{Quantity: ref(int32(1)), Items: []types.KeyValueStoreAssociation{{KeyValueStoreARN: ref(sourceKVS)}}},
+					},
+				}}, nil).
+				Times(1)
+			targetClient := cfmock.NewMockCloudFrontClient(ctrl)
+			if tc.wantErr == nil {
+				targetClient.EXPECT().
+					GetFunction(gomock.Any(), gomock.Any()).
+					Return(nil, errNoSuchFn).
+					Times(1)
+				targetClient.EXPECT().
+					CreateFunction(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, input *cloudfront.CreateFunctionInput, _ ...func(*cloudfront.Options)) (*cloudfront.CreateFunctionOutput, error) {
+						if got := input.FunctionConfig.KeyValueStoreAssociations.Items[0].KeyValueStoreARN; *got != tc.wantKVS {
+							t.Errorf("key value store: want %s, got %s", tc.wantKVS, *got)
+						}
+						return &cloudfront.CreateFunctionOutput{ETag: ref("etag-prod")}, nil
+					}).
+					Times(1)
+			}
+			source := &frontier.PromoteEndpoint{Provider: &cf.StaticCFProvider{Client: sourceClient, Identity: tc.sourceIdentity}, FunctionName: "test-func"}
+			target := &frontier.PromoteEndpoint{Provider: &cf.StaticCFProvider{Client: targetClient, Identity: tc.targetIdentity}, FunctionName: "test-func", KeyValueStoreARNs: tc.mapping}
+			_, gotErr := frontier.NewPromoter().Promote(ctx, source, target, false)
+			if diff := cmp.Diff(tc.wantErr, gotErr, cmpopts.EquateErrors()); diff != "" {
+				t.Errorf("error (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -5,18 +5,28 @@ import (
 	"io"
 )
 
-func NewRenderer() *Renderer {
-	return &Renderer{}
+type NewRendererOption interface {
+	applyNewRendererOption(r *Renderer)
 }
 
-type Renderer struct{}
+func NewRenderer(opts ...NewRendererOption) *Renderer {
+	r := &Renderer{indent: defaultIndent}
+	for _, o := range opts {
+		o.applyNewRendererOption(r)
+	}
+	return r
+}
+
+type Renderer struct {
+	indent int
+}
 
 func (r *Renderer) Render(ctx context.Context, configPath string, output io.Writer) error {
 	fn, err := ParseConfigFromPath(configPath)
 	if err != nil {
 		return err
 	}
-	if err := writeFunctionToStream(fn, output); err != nil {
+	if err := writeFunctionToStream(fn, output, r.indent); err != nil {
 		return err
 	}
 	return nil
@@ -21,10 +21,16 @@ config:
 func TestRenderer_Render(t *testing.T) {
 	testCases := []struct {
 		name       string
+		opts       []frontier.NewRendererOption
 		wantOutput string
 		wantErr    error
 	}{
 		{name: "ok", wantOutput: wantConfig, wantErr: nil},
+		{
+			name:       "indented",
+			opts:       []frontier.NewRendererOption{frontier.Indent(4)},
+			wantOutput: "name: test-func\ncode:\n    path: ./testdata/fn.js\nconfig:\n    comment: blah blah\n    runtime: cloudfront-js-1.0\n",
+		},
 	}
 	for _, tc := range testCases {
 		tc := tc
@@ -36,7 +42,7 @@ func TestRenderer_Render(t *testing.T) {
 			defer cancel()
 
 			buf := new(bytes.Buffer)
-			renderer := frontier.NewRenderer()
+			renderer := frontier.NewRenderer(tc.opts...)
 			gotErr := renderer.Render(ctx, "./testdata/config.yml", buf)
 			if !errors.Is(gotErr, tc.wantErr) {
 				t.Errorf("want error: %s\n got error: %s", tc.wantErr, gotErr)
@@ -0,0 +1,112 @@
+package frontier
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"math/rand/v2"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/retry"
+	"github.com/aws/aws-sdk-go-v2/service/cloudfront/types"
+)
+
+type RetryOption interface {
+	NewDeployerOption
+	NewImporterOption
+}
+
+func RetryMaxAttempts(maxAttempts int) RetryOption { //nolint:ireturn
+	return &optRetryMaxAttempts{maxAttempts: maxAttempts}
+}
+
+type optRetryMaxAttempts struct{ maxAttempts int }
+
+var _ RetryOption = (*optRetryMaxAttempts)(nil)
+
+func (o *optRetryMaxAttempts) applyNewDeployerOption(d *Deployer) {
+	d.retrier.maxAttempts = o.maxAttempts
+}
+
+func (o *optRetryMaxAttempts) applyNewImporterOption(i *Importer) {
+	i.retrier.maxAttempts = o.maxAttempts
+}
+
+func RetryBackoff(base, maxDelay time.Duration) RetryOption { //nolint:ireturn
+	return &optRetryBackoff{base: base, maxDelay: maxDelay}
+}
+
+type optRetryBackoff struct{ base, maxDelay time.Duration }
+
+var _ RetryOption = (*optRetryBackoff)(nil)
+
+func (o *optRetryBackoff) applyNewDeployerOption(d *Deployer) {
+	d.retrier.backoffBase = o.base
+	d.retrier.backoffMax = o.maxDelay
+}
+
+func (o *optRetryBackoff) applyNewImporterOption(i *Importer) {
+	i.retrier.backoffBase = o.base
+	i.retrier.backoffMax = o.maxDelay
+}
+
+type retrier struct {
+	maxAttempts int
+	backoffBase time.Duration
+	backoffMax  time.Duration
+}
+
+func defaultRetrier() retrier {
+	return retrier{
+		maxAttempts: 5,
+		backoffBase: 200 * time.Millisecond,
+		backoffMax:  5 * time.Second,
+	}
+}
+
+func (r retrier) do(ctx context.Context, op func() error) error {
+	for attempt := 1; ; attempt++ {
+		err := op()
+		if err == nil {
+			return nil
+		}
+		if attempt >= r.maxAttempts || !isRetryable(err) {
+			return err
+		}
+		delay := r.backoffDelay(attempt)
+		slog.DebugContext(ctx, "retry the operation", slog.Int("attempt", attempt), slog.Duration("delay", delay), slog.String("error", err.Error()))
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+func (r retrier) backoffDelay(attempt int) time.Duration {
+	if r.backoffBase <= 0 {
+		return 0
+	}
+	delay := r.backoffBase << (attempt - 1)
+	if delay <= 0 || delay > r.backoffMax {
+		delay = r.backoffMax
+	}
+	// full jitter
+	return time.Duration(rand.Int64N(int64(delay) + 1)) //nolint:gosec
+}
+
+func isETagMismatch(err error) bool {
+	var preconditionFailed *types.PreconditionFailed
+	var invalidIfMatch *types.InvalidIfMatchVersion
+	return errors.As(err, &preconditionFailed) || errors.As(err, &invalidIfMatch)
+}
+
+func isRetryable(err error) bool {
+	if isETagMismatch(err) {
+		return true
+	}
+	return retry.IsErrorThrottles(retry.DefaultThrottles).IsErrorThrottle(err) == aws.TrueTernary
+}
@@ -0,0 +1,125 @@
+package frontier
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	"github.com/aereal/frontier/cf"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudfront/types"
+)
+
+type StatsOption interface {
+	applyStatsOption(cfg *configStats)
+}
+
+type configStats struct {
+	stage          types.FunctionStage
+	testEventPaths []string
+}
+
+// StatsStage sets the stage of the deployed function to measure; defaults to DEVELOPMENT.
+func StatsStage(stage types.FunctionStage) StatsOption { return &optStatsStage{stage: stage} } //nolint:ireturn
+
+type optStatsStage struct{ stage types.FunctionStage }
+
+var _ StatsOption = (*optStatsStage)(nil)
+
+func (o *optStatsStage) applyStatsOption(cfg *configStats) { cfg.stage = o.stage }
+
+func (o *optTestEvents) applyStatsOption(cfg *configStats) {
+	cfg.testEventPaths = append(cfg.testEventPaths, o.paths...)
+}
+
+// CodeSizeStats compares the size of the code in bytes with the limit.
+type CodeSizeStats struct {
+	// Bundled is the size of the local code at code.path.
+	Bundled int
+	// Deployed is the size of the code of the stage; nil if the function is not deployed.
+	Deployed *int
+	// Limit is limits.maxCodeBytes if configured, or [MaxFunctionCodeBytes].
+	Limit int
+}
+
+// ComputeUtilizationStats is the compute utilization of the deployed function seen across the test events.
+type ComputeUtilizationStats struct {
+	Peak  int
+	Limit int `json:",omitempty"`
+	Tests []*FunctionTestResult
+}
+
+type FunctionStats struct {
+	FunctionName       string
+	Stage              types.FunctionStage
+	CodeSize           *CodeSizeStats
+	ComputeUtilization *ComputeUtilizationStats `json:",omitempty"`
+	// Exceeded lists the limits that the function exceeds. maxCodeBytes is checked against [MaxFunctionCodeBytes] if not configured.
+	Exceeded []LimitName `json:",omitempty"`
+}
+
+func NewStatsReporter(clientProvider cf.Provider) *StatsReporter {
+	return &StatsReporter{clientProvider: clientProvider}
+}
+
+type StatsReporter struct {
+	clientProvider cf.Provider
+}
+
+// Stats reports the code size and the compute utilization of the function against the limits.
+//
+// The compute utilization is measured by running TestFunction with the test events of the function config and [TestEvents] against the deployed stage.
+func (r *StatsReporter) Stats(ctx context.Context, configPath string, opts ...StatsOption) (_ *FunctionStats, err error) {
+	ctx, span := startSpan(ctx, "frontier.Stats", attrConfigPath.String(configPath))
+	defer func() { endSpan(span, err) }()
+	cfg := &configStats{stage: types.FunctionStageDevelopment}
+	for _, o := range opts {
+		o.applyStatsOption(cfg)
+	}
+	fn, err := parseConfig(ctx, configPath)
+	if err != nil {
+		return nil, err
+	}
+	if err := fn.validateLimits(); err != nil {
+		return nil, err
+	}
+	body, err := readCode(ctx, fn)
+	if err != nil {
+		return nil, err
+	}
+	stats := &FunctionStats{
+		FunctionName: fn.Name,
+		Stage:        cfg.stage,
+		CodeSize:     &CodeSizeStats{Bundled: len(body), Limit: fn.codeSizeLimit()},
+	}
+	if err := fn.checkCodeSize(len(body)); err != nil {
+		stats.Exceeded = append(stats.Exceeded, LimitMaxCodeBytes)
+	}
+
+	client, err := r.clientProvider.ProvideCloudFrontClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+	remote, err := fetchRemoteFunction(ctx, client, fn.Name, cfg.stage)
+	if noSuchFn := new(types.NoSuchFunctionExists); errors.As(err, &noSuchFn) {
+		return stats, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	stats.CodeSize.Deployed = aws.Int(len(remote.Code))
+
+	eventPaths := slices.Concat(fn.TestEvents, cfg.testEventPaths)
+	if len(eventPaths) == 0 {
+		return stats, nil
+	}
+	tests, err := runFunctionTests(ctx, client, fn.Name, aws.ToString(remote.ETag), cfg.stage, eventPaths)
+	if err != nil {
+		return nil, err
+	}
+	stats.ComputeUtilization = &ComputeUtilizationStats{Peak: peakComputeUtilization(tests), Limit: fn.maxComputeUtilization(), Tests: tests}
+	if err := fn.checkComputeUtilization(tests); err != nil {
+		stats.Exceeded = append(stats.Exceeded, LimitMaxComputeUtilization)
+	}
+	return stats, nil
+}
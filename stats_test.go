@@ -0,0 +1,203 @@
+package frontier_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/aereal/frontier"
+	"github.com/aereal/frontier/cf"
+	"github.com/aereal/frontier/internal/cfmock"
+	"github.com/aws/aws-sdk-go-v2/service/cloudfront"
+	"github.com/aws/aws-sdk-go-v2/service/cloudfront/types"
+	"github.com/google/go-cmp/cmp"
+	"go.opentelemetry.io/otel"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.uber.org/mock/gomock"
+)
+
+func TestStatsReporter_Stats(t *testing.T) {
+	codePath, err := filepath.Abs("./testdata/fn.js")
+	if err != nil {
+		t.Fatal(err)
+	}
+	testCases := []struct {
+		name       string
+		code       []byte
+		config     string
+		opts       func(eventPath string) []frontier.StatsOption
+		mock       func(c *cfmock.MockCloudFrontClient)
+		wantResult func(eventPath string) *frontier.FunctionStats
+	}{
+		{
+			name:   "no limits",
+			config: "",
+			mock: func(c *cfmock.MockCloudFrontClient) {
+				returnStage(c, types.FunctionStageDevelopment, []byte("function handler(event) { return event.request; }"), "blah blah")
+			},
+			wantResult: func(_ string) *frontier.FunctionStats {
+				return &frontier.FunctionStats{
+					FunctionName: "test-func",
+					Stage:        types.FunctionStageDevelopment,
+					CodeSize:     &frontier.CodeSizeStats{Bundled: 53, Deployed: ref(49), Limit: frontier.MaxFunctionCodeBytes},
+				}
+			},
+		},
+		{
+			name:   "exceeded",
+			config: "limits:\n  maxCodeBytes: 50\n  maxComputeUtilization: 30\ntestEvents:\n  - $EVENT\n",
+			opts: func(eventPath string) []frontier.StatsOption {
+				return []frontier.StatsOption{frontier.StatsStage(types.FunctionStageLive), frontier.TestEvents(eventPath)}
+			},
+			mock: func(c *cfmock.MockCloudFrontClient) {
+				returnStage(c, types.FunctionStageLive, functionCode, "blah blah")
+				c.EXPECT().
+					TestFunction(gomock.Any(), &cloudfront.TestFunctionInput{
+						Name:        ref("test-func"),
+						IfMatch:     ref("etag-LIVE"),
+						Stage:       types.FunctionStageLive,
+						EventObject: []byte(`{"version":"1.0"}`),
+					}).
+					Return(&cloudfront.TestFunctionOutput{TestResult: &types.TestResult{ComputeUtilization: ref("12")}}, nil).
+					Times(1)
+				c.EXPECT().
+					TestFunction(gomock.Any(), gomock.Any()).
+					Return(&cloudfront.TestFunctionOutput{TestResult: &types.TestResult{ComputeUtilization: ref("35")}}, nil).
+					Times(1)
+			},
+			wantResult: func(eventPath string) *frontier.FunctionStats {
+				return &frontier.FunctionStats{
+					FunctionName: "test-func",
+					Stage:        types.FunctionStageLive,
+					CodeSize:     &frontier.CodeSizeStats{Bundled: 53, Deployed: ref(53), Limit: 50},
+					ComputeUtilization: &frontier.ComputeUtilizationStats{
+						Peak:  35,
+						Limit: 30,
+						Tests: []*frontier.FunctionTestResult{
+							{EventPath: eventPath, ComputeUtilization: "12"},
+							{EventPath: eventPath, ComputeUtilization: "35"},
+						},
+					},
+					Exceeded: []frontier.LimitName{frontier.LimitMaxCodeBytes, frontier.LimitMaxComputeUtilization},
+				}
+			},
+		},
+		{
+			name: "exceeded the limit of CloudFront",
+			code: largeCode,
+			mock: func(c *cfmock.MockCloudFrontClient) {
+				returnStage(c, types.FunctionStageDevelopment, functionCode, "blah blah")
+			},
+			wantResult: func(_ string) *frontier.FunctionStats {
+				return &frontier.FunctionStats{
+					FunctionName: "test-func",
+					Stage:        types.FunctionStageDevelopment,
+					CodeSize:     &frontier.CodeSizeStats{Bundled: len(largeCode), Deployed: ref(53), Limit: frontier.MaxFunctionCodeBytes},
+					Exceeded:     []frontier.LimitName{frontier.LimitMaxCodeBytes},
+				}
+			},
+		},
+		{
+			name:   "not deployed",
+			config: "testEvents:\n  - $EVENT\n",
+			mock: func(c *cfmock.MockCloudFrontClient) {
+				returnNoSuchFunction(c, types.FunctionStageDevelopment)
+			},
+			wantResult: func(_ string) *frontier.FunctionStats {
+				return &frontier.FunctionStats{
+					FunctionName: "test-func",
+					Stage:        types.FunctionStageDevelopment,
+					CodeSize:     &frontier.CodeSizeStats{Bundled: 53, Limit: frontier.MaxFunctionCodeBytes},
+				}
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			if deadline, ok := t.Deadline(); ok {
+				ctx, cancel = context.WithDeadline(ctx, deadline)
+			}
+			defer cancel()
+
+			dir := t.TempDir()
+			eventPath := filepath.Join(dir, "event.json")
+			if err := os.WriteFile(eventPath, []byte(`{"version":"1.0"}`), 0600); err != nil {
+				t.Fatal(err)
+			}
+			fnPath := codePath
+			if tc.code != nil {
+				fnPath = filepath.Join(dir, "fn.js")
+				if err := os.WriteFile(fnPath, tc.code, 0600); err != nil {
+					t.Fatal(err)
+				}
+			}
+			configPath := filepath.Join(dir, "function.yml")
+			config := "name: test-func\ncode:\n  path: " + fnPath + "\nconfig:\n  comment: blah blah\n  runtime: cloudfront-js-1.0\n" + strings.ReplaceAll(tc.config, "$EVENT", eventPath)
+			if err := os.WriteFile(configPath, []byte(config), 0600); err != nil {
+				t.Fatal(err)
+			}
+
+			ctrl := gomock.NewController(t)
+			client := cfmock.NewMockCloudFrontClient(ctrl)
+			tc.mock(client)
+			var opts []frontier.StatsOption
+			if tc.opts != nil {
+				opts = tc.opts(eventPath)
+			}
+			got, err := frontier.NewStatsReporter(&cf.StaticCFProvider{Client: client}).Stats(ctx, configPath, opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tc.wantResult(eventPath), got); diff != "" {
+				t.Errorf("result (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestStatsReporter_Stats_withoutCode(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	reporter := frontier.NewStatsReporter(&cf.StaticCFProvider{Client: cfmock.NewMockCloudFrontClient(ctrl)})
+	_, err := reporter.Stats(context.Background(), "./testdata/config-without-code.yml")
+	want := &frontier.InvalidConfigError{Field: "code.path", Reason: "is required"}
+	if !errors.Is(err, want) {
+		t.Errorf("error:\n\twant: %s (%T)\n\t got: %s (%T)", want, want, err, err)
+	}
+}
+
+func TestStatsReporter_Stats_notRecordedAsDeploy(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	if deadline, ok := t.Deadline(); ok {
+		ctx, cancel = context.WithDeadline(ctx, deadline)
+	}
+	defer cancel()
+
+	metricReader := sdkmetric.NewManualReader()
+	prevMP := otel.GetMeterProvider()
+	otel.SetMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(metricReader)))
+	defer otel.SetMeterProvider(prevMP)
+
+	ctrl := gomock.NewController(t)
+	client := cfmock.NewMockCloudFrontClient(ctrl)
+	returnNoSuchFunction(client, types.FunctionStageDevelopment)
+	if _, err := frontier.NewStatsReporter(&cf.StaticCFProvider{Client: client}).Stats(ctx, "./testdata/config.yml"); err != nil {
+		t.Fatal(err)
+	}
+
+	var rm metricdata.ResourceMetrics
+	if err := metricReader.Collect(ctx, &rm); err != nil {
+		t.Fatal(err)
+	}
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if m.Name == "frontier.function.code.size" {
+				t.Errorf("unexpected metric: %s", m.Name)
+			}
+		}
+	}
+}
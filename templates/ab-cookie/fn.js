@@ -0,0 +1,17 @@
+// Routes the viewers to the variant a or b by the cookie, and picks a variant at random for the viewers without it.
+// The cookie is passed to the origin; let the origin set it on the viewer to keep the variant.
+var cookieName = 'ab-variant';
+
+function handler(event) {
+  var request = event.request;
+  var cookie = request.cookies[cookieName];
+  var variant;
+  if (cookie && (cookie.value === 'a' || cookie.value === 'b')) {
+    variant = cookie.value;
+  } else {
+    variant = Math.random() < 0.5 ? 'a' : 'b';
+  }
+  request.cookies[cookieName] = { value: variant };
+  request.uri = '/' + variant + request.uri;
+  return request;
+}
@@ -0,0 +1,15 @@
+// Adds the CORS headers to the responses for the requests from allowedOrigins.
+var allowedOrigins = ['https://www.example.com', 'https://app.example.com'];
+
+function handler(event) {
+  var response = event.response;
+  var origin = event.request.headers.origin;
+  if (!origin || allowedOrigins.indexOf(origin.value) === -1) {
+    return response;
+  }
+  response.headers['access-control-allow-origin'] = { value: origin.value };
+  response.headers['access-control-allow-methods'] = { value: 'GET, HEAD, OPTIONS' };
+  response.headers['access-control-max-age'] = { value: '86400' };
+  response.headers['vary'] = { value: 'Origin' };
+  return response;
+}
@@ -0,0 +1,20 @@
+// Redirects the paths in redirects to their new locations with 301.
+var redirects = {
+  '/old-path': '/new-path',
+  '/blog': 'https://blog.example.com/'
+};
+
+function handler(event) {
+  var request = event.request;
+  var location = redirects[request.uri];
+  if (!location) {
+    return request;
+  }
+  return {
+    statusCode: 301,
+    statusDescription: 'Moved Permanently',
+    headers: {
+      location: { value: location }
+    }
+  };
+}
@@ -0,0 +1,11 @@
+// Adds the security headers to all responses.
+function handler(event) {
+  var response = event.response;
+  var headers = response.headers;
+  headers['strict-transport-security'] = { value: 'max-age=63072000; includeSubDomains; preload' };
+  headers['content-security-policy'] = { value: "default-src 'self'; frame-ancestors 'none'" };
+  headers['x-content-type-options'] = { value: 'nosniff' };
+  headers['x-frame-options'] = { value: 'DENY' };
+  headers['referrer-policy'] = { value: 'strict-origin-when-cross-origin' };
+  return response;
+}
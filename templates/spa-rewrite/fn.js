@@ -0,0 +1,10 @@
+// Rewrites the paths without a file extension to /index.html so that the single page application handles the routes.
+function handler(event) {
+  var request = event.request;
+  var uri = request.uri;
+  var lastSegment = uri.substring(uri.lastIndexOf('/') + 1);
+  if (lastSegment.indexOf('.') === -1) {
+    request.uri = '/index.html';
+  }
+  return request;
+}
@@ -0,0 +1,149 @@
+package frontier
+
+import (
+	"context"
+	"iter"
+	"time"
+
+	"github.com/aereal/frontier/watch"
+	"github.com/aws/aws-sdk-go-v2/service/cloudfront/types"
+)
+
+type WatchOption interface {
+	applyWatchOption(cfg *configWatch)
+}
+
+type configWatch struct {
+	testEventPaths []string
+	pollerOptions  []watch.Option
+}
+
+// WatchInterval sets the interval to check whether the files are changed.
+func WatchInterval(interval time.Duration) WatchOption { //nolint:ireturn
+	return &optWatchPoller{opt: watch.Interval(interval)}
+}
+
+// WatchDebounce sets the duration to wait for the files to stop changing before deploying.
+func WatchDebounce(debounce time.Duration) WatchOption { //nolint:ireturn
+	return &optWatchPoller{opt: watch.Debounce(debounce)}
+}
+
+type optWatchPoller struct{ opt watch.Option }
+
+var _ WatchOption = (*optWatchPoller)(nil)
+
+func (o *optWatchPoller) applyWatchOption(cfg *configWatch) {
+	cfg.pollerOptions = append(cfg.pollerOptions, o.opt)
+}
+
+type TestEventsOption interface {
+	WatchOption
+	StatsOption
+}
+
+// TestEvents adds the event objects that TestFunction runs with:
+// the watcher runs them after each deployment, and the stats measure the compute utilization with them.
+func TestEvents(paths ...string) TestEventsOption { return &optTestEvents{paths: paths} } //nolint:ireturn
+
+type optTestEvents struct{ paths []string }
+
+var _ TestEventsOption = (*optTestEvents)(nil)
+
+func (o *optTestEvents) applyWatchOption(cfg *configWatch) {
+	cfg.testEventPaths = append(cfg.testEventPaths, o.paths...)
+}
+
+type WatchResult struct {
+	ChangedPaths []string
+	Deploy       *DeployResult
+	Tests        []*FunctionTestResult
+}
+
+type FunctionTestResult struct {
+	EventPath          string
+	ComputeUtilization string
+	ErrorMessage       string
+	Output             string
+	Logs               []string
+}
+
+func NewWatcher(deployer *Deployer) *Watcher {
+	return &Watcher{deployer: deployer}
+}
+
+type Watcher struct {
+	deployer *Deployer
+}
+
+// Watch deploys the function to the DEVELOPMENT stage once and then each time the config, the code or the files listed in `watch` change.
+//
+// The errors of each deployment are yielded and watching goes on; the sequence ends when ctx is done.
+func (w *Watcher) Watch(ctx context.Context, configPath string, opts ...WatchOption) iter.Seq2[*WatchResult, error] {
+	return func(yield func(*WatchResult, error) bool) {
+		var cfg configWatch
+		for _, o := range opts {
+			o.applyWatchOption(&cfg)
+		}
+		patterns := watchPatterns(configPath)
+		changes := watch.NewPoller(cfg.pollerOptions...).Changes(ctx, func() []string { return patterns })
+		redeploy := func(changed []string) bool {
+			result, err := w.redeploy(ctx, configPath, &cfg, changed, &patterns)
+			if ctx.Err() != nil {
+				return false
+			}
+			if err != nil {
+				return yield(nil, err)
+			}
+			return yield(result, nil)
+		}
+		if !redeploy(nil) {
+			return
+		}
+		for changed := range changes {
+			if !redeploy(changed) {
+				return
+			}
+		}
+	}
+}
+
+func watchPatterns(configPath string) []string {
+	patterns := []string{configPath}
+	fn, err := ParseConfigFromPath(configPath)
+	if err != nil {
+		return patterns
+	}
+	patterns = append(patterns, fn.Watch...)
+	if fn.Code != nil {
+		patterns = append(patterns, fn.Code.Path)
+	}
+	return patterns
+}
+
+func (w *Watcher) redeploy(ctx context.Context, configPath string, cfg *configWatch, changed []string, patterns *[]string) (*WatchResult, error) {
+	*patterns = watchPatterns(configPath)
+	fn, err := ParseConfigFromPath(configPath)
+	if err != nil {
+		return nil, err
+	}
+	if err := fn.validate(); err != nil {
+		return nil, err
+	}
+	deployed, err := w.deployer.Deploy(ctx, configPath, false)
+	if err != nil {
+		return nil, err
+	}
+	result := &WatchResult{ChangedPaths: changed, Deploy: deployed}
+	if len(cfg.testEventPaths) == 0 {
+		return result, nil
+	}
+	client, err := w.deployer.clientProvider.ProvideCloudFrontClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result.Tests, err = runFunctionTests(ctx, client, fn.Name, deployed.ETagAfter, types.FunctionStageDevelopment, cfg.testEventPaths)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
@@ -0,0 +1,147 @@
+package watch
+
+import (
+	"context"
+	"iter"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+type Option interface {
+	applyOption(p *Poller)
+}
+
+func Interval(interval time.Duration) Option { return &optInterval{interval: interval} } //nolint:ireturn
+
+type optInterval struct{ interval time.Duration }
+
+var _ Option = (*optInterval)(nil)
+
+func (o *optInterval) applyOption(p *Poller) { p.interval = o.interval }
+
+func Debounce(debounce time.Duration) Option { return &optDebounce{debounce: debounce} } //nolint:ireturn
+
+type optDebounce struct{ debounce time.Duration }
+
+var _ Option = (*optDebounce)(nil)
+
+func (o *optDebounce) applyOption(p *Poller) { p.debounce = o.debounce }
+
+// Poller detects file changes by comparing the modification time and the size periodically,
+// so that it works on any filesystem without OS specific notification mechanisms.
+type Poller struct {
+	interval time.Duration
+	debounce time.Duration
+}
+
+func NewPoller(opts ...Option) *Poller {
+	p := &Poller{
+		interval: 500 * time.Millisecond,
+		debounce: 300 * time.Millisecond,
+	}
+	for _, o := range opts {
+		o.applyOption(p)
+	}
+	return p
+}
+
+// Changes yields the changed paths once the files stay unchanged for the debounce duration.
+//
+// The files are snapshotted at the call, so that the changes made before the iteration starts are also yielded.
+// patterns are called on every poll so that the caller can change the watched files; each pattern may be a glob.
+// Files matched by the newly added patterns are not considered changed.
+// The sequence ends when ctx is done.
+func (p *Poller) Changes(ctx context.Context, patterns func() []string) iter.Seq[[]string] {
+	current := snapshot(patterns())
+	return func(yield func([]string) bool) {
+		var (
+			pending   = map[string]struct{}{}
+			changedAt time.Time
+		)
+		ticker := time.NewTicker(p.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case now := <-ticker.C:
+				next := snapshot(patterns())
+				for _, path := range diff(current, next) {
+					pending[path] = struct{}{}
+					changedAt = now
+				}
+				current = next
+				if len(pending) == 0 || now.Sub(changedAt) < p.debounce {
+					continue
+				}
+				changed := make([]string, 0, len(pending))
+				for path := range pending {
+					changed = append(changed, path)
+				}
+				slices.Sort(changed)
+				clear(pending)
+				if !yield(changed) {
+					return
+				}
+			}
+		}
+	}
+}
+
+type fileState struct {
+	modTime time.Time
+	size    int64
+}
+
+// snapshot returns the states of the files matched by each pattern.
+func snapshot(patterns []string) map[string]map[string]fileState {
+	states := map[string]map[string]fileState{}
+	for _, pattern := range patterns {
+		matches := []string{pattern}
+		if isGlob(pattern) {
+			var err error
+			if matches, err = filepath.Glob(pattern); err != nil {
+				continue
+			}
+		}
+		files := map[string]fileState{}
+		for _, path := range matches {
+			fi, err := os.Stat(path)
+			if err != nil {
+				files[path] = fileState{}
+				continue
+			}
+			files[path] = fileState{modTime: fi.ModTime(), size: fi.Size()}
+		}
+		states[pattern] = files
+	}
+	return states
+}
+
+func isGlob(pattern string) bool {
+	return strings.ContainsAny(pattern, `*?[\`)
+}
+
+func diff(prev, next map[string]map[string]fileState) []string {
+	var changed []string
+	for pattern, nextFiles := range next {
+		prevFiles, ok := prev[pattern]
+		if !ok {
+			continue
+		}
+		for path, state := range nextFiles {
+			if prevState, ok := prevFiles[path]; !ok || !prevState.modTime.Equal(state.modTime) || prevState.size != state.size {
+				changed = append(changed, path)
+			}
+		}
+		for path := range prevFiles {
+			if _, ok := nextFiles[path]; !ok {
+				changed = append(changed, path)
+			}
+		}
+	}
+	return changed
+}
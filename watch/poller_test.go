@@ -0,0 +1,91 @@
+package watch_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/aereal/frontier/watch"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestPoller_Changes(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	if deadline, ok := t.Deadline(); ok {
+		ctx, cancel = context.WithDeadline(ctx, deadline)
+	}
+	defer cancel()
+
+	dir := t.TempDir()
+	fnPath := filepath.Join(dir, "fn.js")
+	writeFile(t, fnPath, "v1")
+	srcPattern := filepath.Join(dir, "src", "*.ts")
+	if err := os.Mkdir(filepath.Join(dir, "src"), 0700); err != nil {
+		t.Fatal(err)
+	}
+
+	poller := watch.NewPoller(watch.Interval(10*time.Millisecond), watch.Debounce(30*time.Millisecond))
+	changes := make(chan []string)
+	go func() {
+		defer close(changes)
+		for changed := range poller.Changes(ctx, func() []string { return []string{fnPath, srcPattern} }) {
+			changes <- changed
+		}
+	}()
+
+	steps := []struct {
+		name string
+		do   func()
+		want []string
+	}{
+		{
+			name: "modified",
+			do:   func() { writeFile(t, fnPath, "v2 longer") },
+			want: []string{fnPath},
+		},
+		{
+			name: "created by glob and modified together",
+			do: func() {
+				writeFile(t, filepath.Join(dir, "src", "a.ts"), "a")
+				writeFile(t, fnPath, "v3 much longer")
+			},
+			want: []string{fnPath, filepath.Join(dir, "src", "a.ts")},
+		},
+		{
+			name: "removed",
+			do: func() {
+				if err := os.Remove(filepath.Join(dir, "src", "a.ts")); err != nil {
+					t.Fatal(err)
+				}
+			},
+			want: []string{filepath.Join(dir, "src", "a.ts")},
+		},
+	}
+	for _, step := range steps {
+		// wait for the poller to take the initial snapshot
+		time.Sleep(50 * time.Millisecond)
+		step.do()
+		select {
+		case got := <-changes:
+			if diff := cmp.Diff(step.want, got); diff != "" {
+				t.Errorf("%s: changed paths (-want, +got):\n%s", step.name, diff)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("%s: no changes detected", step.name)
+		}
+	}
+
+	cancel()
+	if _, ok := <-changes; ok {
+		t.Error("the sequence must end after the context is done")
+	}
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+}
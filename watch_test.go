@@ -0,0 +1,108 @@
+package frontier_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aereal/frontier"
+	"github.com/aereal/frontier/cf"
+	"github.com/aereal/frontier/internal/cfmock"
+	"github.com/aws/aws-sdk-go-v2/service/cloudfront"
+	"github.com/aws/aws-sdk-go-v2/service/cloudfront/types"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"go.uber.org/mock/gomock"
+)
+
+func TestWatcher_Watch(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	if deadline, ok := t.Deadline(); ok {
+		ctx, cancel = context.WithDeadline(ctx, deadline)
+	}
+	defer cancel()
+
+	dir := t.TempDir()
+	codePath := filepath.Join(dir, "fn.js")
+	eventPath := filepath.Join(dir, "event.json")
+	configPath := filepath.Join(dir, "function.yml")
+	writeWatchedFile(t, codePath, "function handler(event) { return event.request }")
+	writeWatchedFile(t, eventPath, `{"version":"1.0"}`)
+	validConfig := "name: test-func\ncode:\n  path: " + codePath + "\nconfig:\n  comment: blah blah\n  runtime: cloudfront-js-2.0\n"
+	writeWatchedFile(t, configPath, validConfig)
+
+	ctrl := gomock.NewController(t)
+	client := cfmock.NewMockCloudFrontClient(ctrl)
+	gomock.InOrder(
+		client.EXPECT().GetFunction(gomock.Any(), gomock.Any()).
+			Return(&cloudfront.GetFunctionOutput{ETag: ref("etag-1")}, nil),
+		client.EXPECT().UpdateFunction(gomock.Any(), gomock.Any()).
+			Return(&cloudfront.UpdateFunctionOutput{ETag: ref("etag-2")}, nil),
+		client.EXPECT().TestFunction(gomock.Any(), &cloudfront.TestFunctionInput{Name: ref("test-func"), IfMatch: ref("etag-2"), Stage: types.FunctionStageDevelopment, EventObject: []byte(`{"version":"1.0"}`)}).
+			Return(&cloudfront.TestFunctionOutput{TestResult: &types.TestResult{ComputeUtilization: ref("12"), FunctionOutput: ref("{}")}}, nil),
+		client.EXPECT().GetFunction(gomock.Any(), gomock.Any()).
+			Return(&cloudfront.GetFunctionOutput{ETag: ref("etag-2")}, nil),
+		client.EXPECT().UpdateFunction(gomock.Any(), gomock.Any()).
+			Return(&cloudfront.UpdateFunctionOutput{ETag: ref("etag-3")}, nil),
+		client.EXPECT().TestFunction(gomock.Any(), gomock.Any()).
+			Return(&cloudfront.TestFunctionOutput{TestResult: &types.TestResult{ComputeUtilization: ref("30"), FunctionErrorMessage: ref("oops")}}, nil),
+	)
+	watcher := frontier.NewWatcher(frontier.NewDeployer(&cf.StaticCFProvider{Client: client}))
+
+	steps := []func(){
+		func() {
+			writeWatchedFile(t, configPath, strings.Replace(validConfig, "cloudfront-js-2.0", "unknown", 1))
+		},
+		func() {
+			writeWatchedFile(t, configPath, validConfig)
+			writeWatchedFile(t, codePath, "function handler(event) { return event.response }")
+		},
+		cancel,
+	}
+	type event struct {
+		result *frontier.WatchResult
+		err    error
+	}
+	var got []event
+	for result, err := range watcher.Watch(ctx, configPath, frontier.TestEvents(eventPath), frontier.WatchInterval(10*time.Millisecond), frontier.WatchDebounce(20*time.Millisecond)) {
+		got = append(got, event{result: result, err: err})
+		// let the poller take the snapshot before changing files
+		time.Sleep(50 * time.Millisecond)
+		steps[len(got)-1]()
+	}
+
+	want := []event{
+		{
+			result: &frontier.WatchResult{
+				Deploy: &frontier.DeployResult{FunctionName: "test-func", Action: frontier.DeployActionUpdated, ETagBefore: "etag-1", ETagAfter: "etag-2", Stage: types.FunctionStageDevelopment},
+				Tests:  []*frontier.FunctionTestResult{{EventPath: eventPath, ComputeUtilization: "12", Output: "{}"}},
+			},
+		},
+		{err: &frontier.InvalidConfigError{Field: "config.runtime", Reason: `unknown runtime "unknown"`}},
+		{
+			result: &frontier.WatchResult{
+				ChangedPaths: []string{codePath, configPath},
+				Deploy:       &frontier.DeployResult{FunctionName: "test-func", Action: frontier.DeployActionUpdated, ETagBefore: "etag-2", ETagAfter: "etag-3", Stage: types.FunctionStageDevelopment},
+				Tests:        []*frontier.FunctionTestResult{{EventPath: eventPath, ComputeUtilization: "30", ErrorMessage: "oops"}},
+			},
+		},
+	}
+	opts := []cmp.Option{
+		cmp.AllowUnexported(event{}),
+		cmpopts.EquateErrors(),
+		cmpopts.IgnoreFields(frontier.DeployResult{}, "CodeSHA256", "Duration"),
+	}
+	if diff := cmp.Diff(want, got, opts...); diff != "" {
+		t.Errorf("events (-want, +got):\n%s", diff)
+	}
+}
+
+func writeWatchedFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+}